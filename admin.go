@@ -0,0 +1,279 @@
+package main
+
+import (
+	"config-service/app"
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/logging"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/secret"
+	"config-service/service"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/user"
+
+	"github.com/go-playground/validator"
+)
+
+// loadSettings resolves the settings of a subcommand and exits on invalid ones.
+func loadSettings(flags *flag.FlagSet, args []string) app.Settings {
+	settings, err := app.LoadSettings(flags, args)
+	if err != nil {
+		fatal("invalid settings", "error", err)
+	}
+
+	// Logs go to stderr so that stdout stays usable for command output
+	if err := logging.Setup(os.Stderr, settings.LogLevel); err != nil {
+		fatal("invalid log level", "error", err)
+	}
+
+	domain.SchemaDir = settings.SchemaDir
+
+	return settings
+}
+
+// fatal logs an error and exits with status 1.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func newAdminService(settings app.Settings) service.AdminService {
+	db := app.NewDB(settings)
+	return service.NewAdminService(newConfigRepository(settings), repository.NewVariableRepository(), repository.NewLabelRepository(), repository.NewFreezeRepository(), db, service.NewAuditService(repository.NewAuditRepository(), db))
+}
+
+// newKeyring returns the keyring of the configured secret keys.
+func newKeyring(settings app.Settings) *secret.Keyring {
+	keyring, err := secret.NewKeyring(settings.SecretKeys, settings.SecretKeyID)
+	if err != nil {
+		fatal("invalid secret keys", "error", err)
+	}
+	return keyring
+}
+
+// newConfigRepository returns the config repository encrypting secret
+// values with the configured keys.
+func newConfigRepository(settings app.Settings) repository.ConfigRepository {
+	return repository.NewSecretConfigRepository(repository.NewConfigRepository(), newKeyring(settings))
+}
+
+// adminContext attributes the changes of a subcommand to the local user in
+// the audit log.
+func adminContext() context.Context {
+	subject := "cli"
+	if current, err := user.Current(); err == nil {
+		subject = "cli:" + current.Username
+	}
+
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject})
+}
+
+func loadSchemas() {
+	err := domain.LoadSchemas(domain.SchemaDir)
+	if err != nil {
+		fatal("error loading schemas", "error", err)
+	}
+}
+
+// runAdmin turns the panics raised by the service layer into a fatal error.
+func runAdmin(command string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fatal("command failed", "command", command, "error", exception.ErrorMessage(r))
+		}
+	}()
+
+	fn()
+}
+
+func printJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fatal("error writing output", "error", err)
+	}
+}
+
+func migrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	status := flags.Bool("status", false, "only list pending migrations")
+	settings := loadSettings(flags, args)
+
+	db := app.OpenDB(settings)
+	defer db.Close()
+
+	if *status {
+		pending, err := app.PendingMigrations(context.Background(), db)
+		if err != nil {
+			fatal("error reading migrations", "error", err)
+		}
+		for _, migration := range pending {
+			fmt.Printf("pending %d %s\n", migration.Version, migration.Name)
+		}
+		if len(pending) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return
+	}
+
+	applied, err := app.Migrate(context.Background(), db)
+	for _, migration := range applied {
+		fmt.Printf("applied %d %s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		fatal("error applying migrations", "error", err)
+	}
+	if len(applied) == 0 {
+		fmt.Println("database is up to date")
+	}
+}
+
+func validateSchemas(args []string) {
+	flags := flag.NewFlagSet("validate-schemas", flag.ExitOnError)
+	loadSettings(flags, args)
+
+	loadSchemas()
+
+	invalid := 0
+	for _, result := range service.NewAdminService(nil, nil, nil, nil, nil, nil).ValidateSchemas() {
+		if result.Valid {
+			fmt.Printf("ok       %s\n", domain.QualifiedSchemaName(result.Tenant, result.Name))
+		} else {
+			invalid++
+			fmt.Printf("invalid  %s: %s\n", domain.QualifiedSchemaName(result.Tenant, result.Name), result.Error)
+		}
+	}
+
+	if invalid > 0 {
+		os.Exit(1)
+	}
+}
+
+func revalidateConfigs(args []string) {
+	flags := flag.NewFlagSet("revalidate-configs", flag.ExitOnError)
+	all := flags.Bool("all", false, "validate every version instead of only the active and scheduled ones")
+	settings := loadSettings(flags, args)
+
+	loadSchemas()
+
+	invalid := 0
+	runAdmin("revalidate-configs", func() {
+		for _, result := range newAdminService(settings).RevalidateConfigs(context.Background(), *all) {
+			if !result.Valid {
+				invalid++
+				fmt.Printf("invalid  %s/%s version %d in %s: %s\n", domain.QualifiedSchemaName(result.Tenant, result.Schema), result.Name, result.Version, result.Environment, result.Error)
+			}
+		}
+	})
+
+	fmt.Printf("%d invalid config(s)\n", invalid)
+	if invalid > 0 {
+		os.Exit(1)
+	}
+}
+
+func exportConfigs(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "-", "output file, - for stdout")
+	settings := loadSettings(flags, args)
+
+	runAdmin("export", func() {
+		configs := newAdminService(settings).ExportConfigs(context.Background())
+
+		if *output == "-" {
+			printJSON(os.Stdout, configs)
+			return
+		}
+
+		file, err := os.Create(*output)
+		if err != nil {
+			fatal("error creating export file", "path", *output, "error", err)
+		}
+		defer file.Close()
+
+		printJSON(file, configs)
+		slog.Info("exported configs", "versions", len(configs), "path", *output)
+	})
+}
+
+func importConfigs(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	input := flags.String("f", "-", "export file to import, - for stdin")
+	settings := loadSettings(flags, args)
+
+	loadSchemas()
+
+	var content []byte
+	var err error
+	if *input == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(*input)
+	}
+	if err != nil {
+		fatal("error reading import file", "path", *input, "error", err)
+	}
+
+	var configs []web.ConfigResponse
+	if err := json.Unmarshal(content, &configs); err != nil {
+		fatal("error parsing import file", "path", *input, "error", err)
+	}
+
+	runAdmin("import", func() {
+		result := newAdminService(settings).ImportConfigs(adminContext(), configs)
+		fmt.Printf("imported %d, skipped %d config version(s)\n", result.Imported, result.Skipped)
+	})
+}
+
+func compactConfigs(args []string) {
+	flags := flag.NewFlagSet("compact", flag.ExitOnError)
+	keep := flags.Int("keep", 0, "number of newest versions that took effect to keep per config, 0 keeps all")
+	override := flags.String("override", "", "reason for compacting configs locked by active freezes, recorded in the audit log")
+	settings := loadSettings(flags, args)
+
+	runAdmin("compact", func() {
+		result := newAdminService(settings).CompactConfigs(adminContext(), *keep, *override)
+		fmt.Printf("deleted %d config version(s)\n", result.Deleted)
+		if result.Skipped > 0 {
+			fmt.Printf("skipped %d config(s) locked by freezes, compact them with -override REASON\n", result.Skipped)
+		}
+	})
+}
+
+// rotateSecrets re-encrypts the secret values encrypted with other keys than
+// secret-key-id, after which the other keys can be removed.
+func rotateSecrets(args []string) {
+	flags := flag.NewFlagSet("rotate-secrets", flag.ExitOnError)
+	settings := loadSettings(flags, args)
+
+	loadSchemas()
+
+	runAdmin("rotate-secrets", func() {
+		result := newAdminService(settings).RotateSecrets(adminContext())
+		fmt.Printf("rotated the secrets of %d config version(s) to key %s\n", result.Rotated, settings.SecretKeyID)
+	})
+}
+
+// createAPIKey bootstraps keys, e.g. the first admin key, without going
+// through the authenticated admin endpoints.
+func createAPIKey(args []string) {
+	flags := flag.NewFlagSet("create-api-key", flag.ExitOnError)
+	name := flags.String("name", "", "unique name of the key")
+	admin := flags.Bool("admin", false, "allow the key to use the admin endpoints")
+	tenantName := flags.String("tenant", "", "bind the key to a tenant")
+	settings := loadSettings(flags, args)
+
+	runAdmin("create-api-key", func() {
+		db := app.NewDB(settings)
+		apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(), db, validator.New(), service.NewAuditService(repository.NewAuditRepository(), db))
+		printJSON(os.Stdout, apiKeyService.CreateKey(adminContext(), web.APIKeyCreateRequest{Name: *name, Admin: *admin, Tenant: *tenantName}))
+	})
+}
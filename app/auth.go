@@ -0,0 +1,245 @@
+package app
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/service"
+	"config-service/tenant"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Authenticator resolves the credentials of HTTP and gRPC requests into the
+// principal of the caller.
+type Authenticator struct {
+	// Required rejects requests without credentials
+	Required bool
+	APIKeys  service.APIKeyService
+	// Tokens validates bearer tokens, nil when they are disabled
+	Tokens *auth.JWTVerifier
+	// Audit records denied requests, nil disables it
+	Audit service.AuditService
+}
+
+// NewAuthenticator builds the authenticator configured by settings, loading
+// the JWKS when bearer tokens are enabled.
+func NewAuthenticator(ctx context.Context, settings Settings, apiKeyService service.APIKeyService, auditService service.AuditService) (*Authenticator, error) {
+	authenticator := &Authenticator{
+		Required: settings.AuthRequired,
+		APIKeys:  apiKeyService,
+		Audit:    auditService,
+	}
+
+	if settings.JWTJWKS != "" {
+		keySet, err := auth.LoadKeySet(ctx, settings.JWTJWKS)
+		if err != nil {
+			return nil, err
+		}
+		authenticator.Tokens = auth.NewJWTVerifier(keySet, auth.JWTConfig{
+			Issuer:      settings.JWTIssuer,
+			Audience:    settings.JWTAudience,
+			RoleClaim:   settings.JWTRoleClaim,
+			RoleMapping: settings.JWTRoleMapping,
+			TenantClaim: settings.JWTTenantClaim,
+		})
+	}
+
+	return authenticator, nil
+}
+
+// authenticate returns ctx with the principal of the given credentials. When
+// no credentials are given the request stays anonymous, unless required.
+func (authenticator *Authenticator) authenticate(ctx context.Context, apiKey, authorization string) context.Context {
+	if apiKey != "" {
+		return auth.WithPrincipal(ctx, authenticator.APIKeys.Authenticate(ctx, apiKey))
+	}
+
+	if authorization != "" {
+		scheme, token, _ := strings.Cut(authorization, " ")
+		if !strings.EqualFold(scheme, "Bearer") || authenticator.Tokens == nil {
+			panic(exception.NewUnauthorizedError("unsupported authorization scheme"))
+		}
+
+		principal, err := authenticator.Tokens.Verify(ctx, strings.TrimSpace(token))
+		if errors.Is(err, auth.ErrNoRoles) {
+			panic(exception.NewForbiddenError(err.Error()))
+		}
+		if err != nil {
+			slog.DebugContext(ctx, "bearer token rejected", "error", err)
+			panic(exception.NewUnauthorizedError("invalid bearer token"))
+		}
+		return auth.WithPrincipal(ctx, principal)
+	}
+
+	if authenticator.Required {
+		panic(exception.NewUnauthorizedError("missing credentials"))
+	}
+	return ctx
+}
+
+// resolveTenant returns ctx operating on the requested tenant, or on the
+// tenant the caller is bound to. Bound callers cannot request another one.
+func resolveTenant(ctx context.Context, requested string) context.Context {
+	if requested != "" && !tenant.Valid(requested) {
+		helper.PanicIfError(helper.ValidationError{Msg: "invalid tenant name"})
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	if principal.Tenant != "" {
+		if requested != "" && requested != principal.Tenant {
+			panic(exception.NewForbiddenError("caller is bound to tenant " + principal.Tenant))
+		}
+		requested = principal.Tenant
+	}
+
+	if requested == "" {
+		requested = tenant.Default
+	}
+	return tenant.With(ctx, requested)
+}
+
+// denied records r in the audit log when it rejects the caller.
+func (authenticator *Authenticator) denied(ctx context.Context, r interface{}, schema, name string) {
+	if authenticator.Audit == nil {
+		return
+	}
+
+	switch r.(type) {
+	case exception.UnauthorizedError, exception.ForbiddenError:
+		authenticator.Audit.RecordDenied(ctx, schema, name, exception.ErrorMessage(r))
+	}
+}
+
+// authMiddleware authenticates the X-API-Key or Authorization header and
+// attaches the caller to the request context. It must run after the error
+// handling middleware.
+func authMiddleware(authenticator *Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(exception.UnauthorizedError); ok && authenticator.Tokens != nil {
+					c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+				}
+				authenticator.denied(c.Request.Context(), r, c.Param("schema"), c.Param("name"))
+				panic(r)
+			}
+		}()
+
+		c.Request = c.Request.WithContext(auth.WithSource(c.Request.Context(), c.ClientIP()))
+		ctx := authenticator.authenticate(c.Request.Context(), c.GetHeader(auth.APIKeyHeader), c.GetHeader("Authorization"))
+		c.Request = c.Request.WithContext(ctx)
+
+		// The tenant of the route takes precedence over the header
+		requested := c.Param("tenant")
+		if requested == "" {
+			requested = c.GetHeader(tenant.Header)
+		}
+		c.Request = c.Request.WithContext(resolveTenant(c.Request.Context(), requested))
+
+		c.Next()
+	}
+}
+
+// requireRole rejects callers that were not granted role.
+func requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			panic(exception.NewUnauthorizedError("missing credentials"))
+		}
+		if !principal.HasRole(role) {
+			panic(exception.NewForbiddenError("requires the " + role + " role"))
+		}
+
+		c.Next()
+	}
+}
+
+// requireAllTenants rejects callers bound to a tenant, e.g. from the admin
+// endpoints which are not scoped to a tenant.
+func requireAllTenants(c *gin.Context) {
+	principal, _ := auth.FromContext(c.Request.Context())
+	if principal.Tenant != "" {
+		panic(exception.NewForbiddenError("callers bound to a tenant cannot use this endpoint"))
+	}
+
+	c.Next()
+}
+
+func grpcMetadata(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// grpcSource returns the address of the gRPC peer of ctx.
+func grpcSource(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// configTarget is implemented by the requests addressing a single config.
+type configTarget interface {
+	GetSchema() string
+	GetName() string
+}
+
+// deniedGRPC records the denial of req, see denied.
+func (authenticator *Authenticator) deniedGRPC(ctx context.Context, r interface{}, req interface{}) {
+	schema, name := "", ""
+	if target, ok := req.(configTarget); ok {
+		schema, name = target.GetSchema(), target.GetName()
+	}
+	authenticator.denied(ctx, r, schema, name)
+}
+
+func (authenticator *Authenticator) authenticateGRPC(ctx context.Context) context.Context {
+	ctx = auth.WithSource(ctx, grpcSource(ctx))
+	ctx = authenticator.authenticate(ctx, grpcMetadata(ctx, auth.APIKeyHeader), grpcMetadata(ctx, "authorization"))
+	return resolveTenant(ctx, grpcMetadata(ctx, strings.ToLower(tenant.Header)))
+}
+
+// authUnary is the gRPC counterpart of authMiddleware, reading the
+// x-api-key, authorization and x-tenant metadata.
+func authUnary(authenticator *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		defer func() {
+			if r := recover(); r != nil {
+				authenticator.deniedGRPC(ctx, r, req)
+				panic(r)
+			}
+		}()
+
+		ctx = authenticator.authenticateGRPC(ctx)
+		return handler(ctx, req)
+	}
+}
+
+func authStream(authenticator *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := stream.Context()
+		defer func() {
+			if r := recover(); r != nil {
+				// The request of a stream is only known to the handler
+				authenticator.deniedGRPC(ctx, r, nil)
+				panic(r)
+			}
+		}()
+
+		ctx = authenticator.authenticateGRPC(ctx)
+		return handler(srv, contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
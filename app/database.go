@@ -2,26 +2,31 @@ package app
 
 import (
 	"config-service/helper"
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
 )
 
-func NewDB() *sql.DB {
-	db, err := sql.Open("sqlite3", "config_database.db")
+// OpenDB connects to the database without touching its schema.
+func OpenDB(settings Settings) *sql.DB {
+	db, err := sql.Open("sqlite3", settings.DBPath)
 	helper.PanicIfError(err)
 
-	log.Println("Connected to SQLite successfully!")
+	slog.Info("connected to SQLite", "path", settings.DBPath)
 
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS configs (
-		schema TEXT NOT NULL,
-        name TEXT NOT NULL,
-        version INTEGER NOT NULL,
-        data TEXT NOT NULL,
-        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    );`)
+	return db
+}
+
+// NewDB connects to the database and applies pending migrations.
+func NewDB(settings Settings) *sql.DB {
+	db := OpenDB(settings)
+
+	applied, err := Migrate(context.Background(), db)
 	helper.PanicIfError(err)
 
-	log.Println("Created configs table successfully!")
+	for _, migration := range applied {
+		slog.Info("applied migration", "version", migration.Version, "name", migration.Name)
+	}
 
 	return db
 }
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"config-service/environment"
+	"config-service/exception"
+	"config-service/logging"
+	"config-service/proto/configpb"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+)
+
+func NewGRPCServer(authenticator *Authenticator, configServer configpb.ConfigServiceServer) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(requestIDUnary, recoverUnary, authUnary(authenticator), environmentUnary),
+		grpc.ChainStreamInterceptor(requestIDStream, recoverStream, authStream(authenticator), environmentStream),
+	)
+
+	configpb.RegisterConfigServiceServer(server, configServer)
+	reflection.Register(server)
+
+	return server
+}
+
+// recoverUnary is the gRPC counterpart of the router's panic handler.
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = exception.GRPCErrorHandler(ctx, info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = exception.GRPCErrorHandler(stream.Context(), info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, stream)
+}
+
+// grpcRequestID reads the x-request-id metadata or generates a new ID, and
+// sends it back in the response header.
+func grpcRequestID(ctx context.Context) context.Context {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(logging.RequestIDHeader); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if requestID == "" || len(requestID) > 128 {
+		requestID = logging.NewRequestID()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDHeader, requestID))
+	return logging.WithRequestID(ctx, requestID)
+}
+
+func requestIDUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(grpcRequestID(ctx), req)
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func requestIDStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, contextStream{ServerStream: stream, ctx: grpcRequestID(stream.Context())})
+}
+
+// grpcEnvironment attaches the environment of the x-environment metadata to
+// ctx, the gRPC counterpart of environmentMiddleware.
+func grpcEnvironment(ctx context.Context) context.Context {
+	if requested := grpcMetadata(ctx, strings.ToLower(environment.Header)); requested != "" {
+		return environment.With(ctx, requested)
+	}
+	return ctx
+}
+
+func environmentUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(grpcEnvironment(ctx), req)
+}
+
+func environmentStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, contextStream{ServerStream: stream, ctx: grpcEnvironment(stream.Context())})
+}
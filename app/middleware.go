@@ -0,0 +1,82 @@
+package app
+
+import (
+	"config-service/environment"
+	"config-service/logging"
+	"config-service/metrics"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// metricsMiddleware records request counts and latency per route template,
+// so /configs/:schema/:name is one series regardless of the config name.
+func metricsMiddleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	status := strconv.Itoa(c.Writer.Status())
+
+	metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+}
+
+// requestIDMiddleware propagates the X-Request-ID header, generating one when
+// missing, into the response and the request context.
+func requestIDMiddleware(c *gin.Context) {
+	requestID := c.GetHeader(logging.RequestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = logging.NewRequestID()
+	}
+
+	c.Header(logging.RequestIDHeader, requestID)
+	c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+
+	c.Next()
+}
+
+// accessLogMiddleware replaces gin's text logger with one JSON line per request.
+func accessLogMiddleware(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	status := c.Writer.Status()
+	level := slog.LevelInfo
+	if status >= 500 {
+		level = slog.LevelError
+	} else if status >= 400 {
+		level = slog.LevelWarn
+	}
+
+	slog.Log(c.Request.Context(), level, "http request",
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"route", c.FullPath(),
+		"status", status,
+		"duration_ms", float64(time.Since(start).Microseconds())/1000,
+		"client_ip", c.ClientIP(),
+	)
+}
+
+// environmentMiddleware attaches the environment of the env query parameter
+// or the X-Environment header to the request context. The config service
+// validates it.
+func environmentMiddleware(c *gin.Context) {
+	requested := c.Query(environment.Query)
+	if requested == "" {
+		requested = c.GetHeader(environment.Header)
+	}
+	if requested != "" {
+		c.Request = c.Request.WithContext(environment.With(c.Request.Context(), requested))
+	}
+
+	c.Next()
+}
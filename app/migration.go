@@ -0,0 +1,297 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// Migrations are applied in order and recorded in schema_migrations.
+// Append new migrations at the end, never edit an applied one.
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_configs_table",
+		SQL: `CREATE TABLE IF NOT EXISTS configs (
+		schema TEXT NOT NULL,
+        name TEXT NOT NULL,
+        version INTEGER NOT NULL,
+        data TEXT NOT NULL,
+        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );`,
+	},
+	{
+		Version: 2,
+		Name:    "index_configs_schema_name_version",
+		SQL:     `CREATE INDEX IF NOT EXISTS configs_schema_name_version ON configs (schema, name, version);`,
+	},
+	{
+		Version: 3,
+		Name:    "create_api_keys_table",
+		SQL: `CREATE TABLE IF NOT EXISTS api_keys (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		admin INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_used_at DATETIME,
+		revoked_at DATETIME
+	);`,
+	},
+	{
+		Version: 4,
+		Name:    "add_configs_created_by",
+		SQL:     `ALTER TABLE configs ADD COLUMN created_by TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		Version: 5,
+		Name:    "create_role_bindings_table",
+		SQL: `CREATE TABLE IF NOT EXISTS role_bindings (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		principal TEXT NOT NULL,
+		role TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name_pattern TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		created_by TEXT NOT NULL DEFAULT '',
+		UNIQUE (principal, role, schema, name_pattern)
+	);`,
+	},
+	{
+		Version: 6,
+		Name:    "create_audit_log_table",
+		SQL: `CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		occurred_at DATETIME NOT NULL,
+		actor TEXT NOT NULL,
+		source TEXT NOT NULL,
+		action TEXT NOT NULL,
+		outcome TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		before_hash TEXT NOT NULL,
+		after_hash TEXT NOT NULL,
+		detail TEXT NOT NULL,
+		prev_hash TEXT NOT NULL,
+		hash TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS audit_log_schema_name ON audit_log (schema, name);
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;
+	CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+	BEGIN SELECT RAISE(ABORT, 'audit log is append-only'); END;`,
+	},
+	{
+		// SQLite cannot change the UNIQUE constraint of role_bindings in
+		// place, so the table is rebuilt. Existing bindings belong to the
+		// default tenant.
+		Version: 7,
+		Name:    "add_tenants",
+		SQL: `ALTER TABLE configs ADD COLUMN tenant TEXT NOT NULL DEFAULT 'default';
+	CREATE INDEX IF NOT EXISTS configs_tenant_schema_name_version ON configs (tenant, schema, name, version);
+	ALTER TABLE api_keys ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+	ALTER TABLE audit_log ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
+	CREATE TABLE role_bindings_tenants (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant TEXT NOT NULL DEFAULT 'default',
+		principal TEXT NOT NULL,
+		role TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name_pattern TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		created_by TEXT NOT NULL DEFAULT '',
+		UNIQUE (tenant, principal, role, schema, name_pattern)
+	);
+	INSERT INTO role_bindings_tenants (id, principal, role, schema, name_pattern, created_at, created_by)
+		SELECT id, principal, role, schema, name_pattern, created_at, created_by FROM role_bindings;
+	DROP TABLE role_bindings;
+	ALTER TABLE role_bindings_tenants RENAME TO role_bindings;`,
+	},
+	{
+		Version: 8,
+		Name:    "add_environments",
+		SQL: `ALTER TABLE configs ADD COLUMN environment TEXT NOT NULL DEFAULT 'default';
+	CREATE INDEX IF NOT EXISTS configs_tenant_environment_schema_name_version ON configs (tenant, environment, schema, name, version);
+	ALTER TABLE audit_log ADD COLUMN environment TEXT NOT NULL DEFAULT '';`,
+	},
+	{
+		Version: 9,
+		Name:    "create_config_labels_table",
+		SQL: `CREATE TABLE IF NOT EXISTS config_labels (
+		tenant TEXT NOT NULL,
+		environment TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name TEXT NOT NULL,
+		label TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_by TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (tenant, environment, schema, name, label)
+	);`,
+	},
+	{
+		Version: 10,
+		Name:    "add_config_metadata",
+		SQL:     `ALTER TABLE configs ADD COLUMN metadata TEXT NOT NULL DEFAULT '{}';`,
+	},
+	{
+		Version: 11,
+		Name:    "add_variables",
+		SQL: `CREATE TABLE IF NOT EXISTS variables (
+		tenant TEXT NOT NULL,
+		environment TEXT NOT NULL,
+		name TEXT NOT NULL,
+		value TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_by TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (tenant, environment, name)
+	);`,
+	},
+	{
+		Version: 12,
+		Name:    "create_drafts_tables",
+		SQL: `CREATE TABLE IF NOT EXISTS drafts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant TEXT NOT NULL,
+		environment TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name TEXT NOT NULL,
+		base_version INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		comment TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		published_version INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		created_by TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS drafts_tenant_environment_status ON drafts (tenant, environment, status);
+	CREATE TABLE IF NOT EXISTS draft_reviews (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		draft_id INTEGER NOT NULL REFERENCES drafts (id),
+		reviewer TEXT NOT NULL,
+		decision TEXT NOT NULL,
+		comment TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS draft_reviews_draft_id ON draft_reviews (draft_id);`,
+	},
+	{
+		Version: 13,
+		Name:    "add_config_activation",
+		SQL: `ALTER TABLE configs ADD COLUMN effective_at DATETIME;
+	ALTER TABLE configs ADD COLUMN cancelled_at DATETIME;
+	CREATE INDEX IF NOT EXISTS configs_effective_at ON configs (effective_at);`,
+	},
+	{
+		Version: 14,
+		Name:    "create_freezes_table",
+		SQL: `CREATE TABLE IF NOT EXISTS freezes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		tenant TEXT NOT NULL,
+		environment TEXT NOT NULL,
+		schema TEXT NOT NULL,
+		name_pattern TEXT NOT NULL,
+		reason TEXT NOT NULL,
+		recurring_window TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		created_by TEXT NOT NULL DEFAULT ''
+	);`,
+	},
+}
+
+func ensureMigrationsTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`)
+	return err
+}
+
+// PendingMigrations returns the migrations that have not been applied yet.
+func PendingMigrations(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	if err := ensureMigrationsTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range Migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Migrate applies every pending migration, each in its own transaction,
+// and returns the applied ones.
+func Migrate(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	pending, err := PendingMigrations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		if err := applyMigration(ctx, db, migration); err != nil {
+			return pending[:i], fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+
+	return pending, nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, migration Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MigrationStatus adapts PendingMigrations to the check expected by
+// service.NewHealthService.
+func MigrationStatus(db *sql.DB) func(ctx context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		pending, err := PendingMigrations(ctx, db)
+		return len(pending), err
+	}
+}
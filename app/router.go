@@ -1,17 +1,37 @@
 package app
 
 import (
+	"config-service/auth"
 	"config-service/controller"
 	"config-service/exception"
+	"config-service/metrics"
+	"config-service/tracing"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func NewRouter(configController controller.ConfigController, schemaController controller.SchemaController) *gin.Engine {
+func NewRouter(settings Settings, authenticator *Authenticator, configController controller.ConfigController, schemaController controller.SchemaController, healthController controller.HealthController, apiKeyController controller.APIKeyController, roleBindingController controller.RoleBindingController, freezeController controller.FreezeController, auditController controller.AuditController, variableController controller.VariableController, draftController controller.DraftController) *gin.Engine {
 	// Create Gin engine
-	router := gin.Default()
+	gin.SetMode(settings.GinMode)
+	gin.DebugPrintRouteFunc = func(httpMethod, absolutePath, handlerName string, nuHandlers int) {
+		slog.Debug("route registered", "method", httpMethod, "path", absolutePath, "handler", handlerName)
+	}
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	// Server spans continue the W3C trace context of incoming requests
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+
+	// Request IDs come first so every later log line carries them
+	router.Use(requestIDMiddleware)
+	router.Use(accessLogMiddleware)
+
+	// Metrics wrap the error handler so panics are counted with their status
+	router.Use(metricsMiddleware)
 
 	// Global error handling (replace PanicHandler)
 	router.Use(func(c *gin.Context) {
@@ -24,24 +44,85 @@ func NewRouter(configController controller.ConfigController, schemaController co
 		c.Next()
 	})
 
-	// Config routes
-	configs := router.Group("/configs")
-	{
-		configs.POST("/:schema/:name", configController.CreateConfig)
-		configs.PUT("/:schema/:name", configController.UpdateConfig)
-		configs.POST("/:schema/:name/rollback", configController.RollbackConfig)
-		configs.GET("/:schema/:name", configController.FetchConfig)
-		configs.GET("/:schema/:name/versions", configController.ListVersions)
-	}
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Health routes
+	router.GET("/healthz", healthController.Healthz)
+	router.GET("/readyz", healthController.Readyz)
+	router.GET("/status", healthController.Status)
 
 	// Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Schema routers
-	schemas := router.Group("schemas")
+	// Everything below requires credentials, unless auth is optional
+	authenticated := authMiddleware(authenticator)
+
+	// Config and schema routes operate on the tenant of the caller or of the
+	// X-Tenant header, or under /tenants/:tenant on the tenant of the path
+	for _, scope := range []*gin.RouterGroup{router.Group("", authenticated), router.Group("/tenants/:tenant", authenticated)} {
+		// Config routes operate on the environment of the env query parameter
+		// or the X-Environment header
+		configs := scope.Group("/configs", environmentMiddleware)
+		{
+			configs.POST("", configController.BatchUpdateConfigs)
+			configs.POST("/:schema/:name", configController.CreateConfig)
+			configs.PUT("/:schema/:name", configController.UpdateConfig)
+			configs.POST("/:schema/:name/rollback", configController.RollbackConfig)
+			configs.POST("/:schema/:name/cancel", configController.CancelConfig)
+			configs.POST("/:schema/:name/promote", configController.PromoteConfig)
+			configs.GET("", configController.BulkFetchConfigs)
+			configs.GET("/:schema/:name", configController.FetchConfig)
+			configs.GET("/:schema/:name/versions", configController.ListVersions)
+			configs.GET("/:schema/:name/environments", configController.ListConfigEnvironments)
+			configs.GET("/:schema/:name/referrers", configController.ListReferrers)
+			configs.POST("/:schema/:name/drafts", draftController.ProposeConfig)
+			configs.GET("/:schema/:name/drafts", draftController.ListConfigDrafts)
+			configs.GET("/:schema/:name/labels", configController.ListLabels)
+			configs.PUT("/:schema/:name/labels/:label", configController.SetLabel)
+			configs.DELETE("/:schema/:name/labels/:label", configController.DeleteLabel)
+		}
+		scope.GET("/environments", configController.ListEnvironments)
+
+		// Variable routes operate on the environment like config routes
+		variables := scope.Group("/variables", environmentMiddleware)
+		{
+			variables.GET("", variableController.ListVariables)
+			variables.PUT("/:name", variableController.SetVariable)
+			variables.DELETE("/:name", variableController.DeleteVariable)
+		}
+
+		// Draft routes operate on the environment like config routes
+		drafts := scope.Group("/drafts", environmentMiddleware)
+		{
+			drafts.GET("", draftController.ListDrafts)
+			drafts.GET("/:id", draftController.GetDraft)
+			drafts.POST("/:id/approve", draftController.ApproveDraft)
+			drafts.POST("/:id/reject", draftController.RejectDraft)
+		}
+
+		// Schema routers
+		schemas := scope.Group("schemas")
+		{
+			schemas.GET("/", schemaController.ListSchemas)
+			schemas.GET("/:name", schemaController.GetSchema)
+		}
+	}
+
+	// Admin routes
+	admin := router.Group("/admin", authenticated, requireAllTenants, requireRole(auth.RoleAdmin))
 	{
-		schemas.GET("/", schemaController.ListSchemas)
-		schemas.GET("/:name", schemaController.GetSchema)
+		admin.POST("/api-keys", apiKeyController.CreateKey)
+		admin.GET("/api-keys", apiKeyController.ListKeys)
+		admin.DELETE("/api-keys/:id", apiKeyController.RevokeKey)
+		admin.POST("/role-bindings", roleBindingController.CreateBinding)
+		admin.GET("/role-bindings", roleBindingController.ListBindings)
+		admin.DELETE("/role-bindings/:id", roleBindingController.DeleteBinding)
+		admin.POST("/freezes", freezeController.CreateFreeze)
+		admin.GET("/freezes", freezeController.ListFreezes)
+		admin.DELETE("/freezes/:id", freezeController.DeleteFreeze)
+		admin.GET("/audit", auditController.ListEvents)
+		admin.GET("/audit/verify", auditController.Verify)
 	}
 
 	return router
@@ -0,0 +1,526 @@
+package app
+
+import (
+	"bytes"
+	"config-service/auth"
+	"config-service/environment"
+	"config-service/logging"
+	"config-service/secret"
+	"config-service/tenant"
+	"config-service/tracing"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings configures the server binary.
+type Settings struct {
+	HTTPAddr         string            `yaml:"http_addr"`
+	GRPCAddr         string            `yaml:"grpc_addr"`
+	DBPath           string            `yaml:"db_path"`
+	SchemaDir        string            `yaml:"schema_dir"`
+	ShutdownTimeout  time.Duration     `yaml:"shutdown_timeout"`
+	GinMode          string            `yaml:"gin_mode"`
+	LogLevel         string            `yaml:"log_level"`
+	TraceExporter    string            `yaml:"trace_exporter"`
+	TraceEndpoint    string            `yaml:"trace_endpoint"`
+	AuthRequired     bool              `yaml:"auth_required"`
+	JWTJWKS          string            `yaml:"jwt_jwks"`
+	JWTIssuer        string            `yaml:"jwt_issuer"`
+	JWTAudience      string            `yaml:"jwt_audience"`
+	JWTRoleClaim     string            `yaml:"jwt_role_claim"`
+	JWTRoleMapping   map[string]string `yaml:"jwt_role_mapping"`
+	JWTTenantClaim   string            `yaml:"jwt_tenant_claim"`
+	TenantMaxConfigs int               `yaml:"tenant_max_configs"`
+	TenantQuotas     map[string]int    `yaml:"tenant_quotas"`
+	Environments     []string          `yaml:"environments"`
+	SecretKeys       map[string]string `yaml:"secret_keys"`
+	SecretKeyID      string            `yaml:"secret_key_id"`
+	// RequiredApprovals is the number of approvals the drafts of a schema
+	// need, keyed by schema for the default tenant and by tenant/schema for
+	// the others; configs of listed schemas can only be changed through drafts
+	RequiredApprovals map[string]int `yaml:"required_approvals"`
+	// ActivationInterval is how often scheduled versions are checked for
+	// their activation
+	ActivationInterval time.Duration `yaml:"activation_interval"`
+}
+
+const settingsFileEnv = "CONFIG_SERVICE_CONFIG"
+
+func DefaultSettings() Settings {
+	return Settings{
+		HTTPAddr:        ":3000",
+		GRPCAddr:        ":3001",
+		DBPath:          "config_database.db",
+		SchemaDir:       "schemas",
+		ShutdownTimeout: 5 * time.Second,
+		GinMode:         gin.DebugMode,
+		LogLevel:        "info",
+		TraceExporter:   tracing.ExporterNone,
+		AuthRequired:    true,
+		JWTRoleClaim:    "roles",
+		JWTTenantClaim:  "tenant",
+		Environments:    []string{"dev", "staging", "prod"},
+
+		ActivationInterval: time.Second,
+	}
+}
+
+type settingField struct {
+	flag  string
+	env   string
+	usage string
+	get   func(settings *Settings) string
+	set   func(settings *Settings, value string) error
+}
+
+var settingFields = []settingField{
+	{
+		flag:  "http-addr",
+		env:   "CONFIG_SERVICE_HTTP_ADDR",
+		usage: "HTTP listen address",
+		get:   func(s *Settings) string { return s.HTTPAddr },
+		set:   func(s *Settings, v string) error { s.HTTPAddr = v; return nil },
+	},
+	{
+		flag:  "grpc-addr",
+		env:   "CONFIG_SERVICE_GRPC_ADDR",
+		usage: "gRPC listen address",
+		get:   func(s *Settings) string { return s.GRPCAddr },
+		set:   func(s *Settings, v string) error { s.GRPCAddr = v; return nil },
+	},
+	{
+		flag:  "db",
+		env:   "CONFIG_SERVICE_DB_PATH",
+		usage: "SQLite database file",
+		get:   func(s *Settings) string { return s.DBPath },
+		set:   func(s *Settings, v string) error { s.DBPath = v; return nil },
+	},
+	{
+		flag:  "schema-dir",
+		env:   "CONFIG_SERVICE_SCHEMA_DIR",
+		usage: "directory of JSON schema files",
+		get:   func(s *Settings) string { return s.SchemaDir },
+		set:   func(s *Settings, v string) error { s.SchemaDir = v; return nil },
+	},
+	{
+		flag:  "shutdown-timeout",
+		env:   "CONFIG_SERVICE_SHUTDOWN_TIMEOUT",
+		usage: "time given to in-flight requests on shutdown",
+		get:   func(s *Settings) string { return s.ShutdownTimeout.String() },
+		set: func(s *Settings, v string) error {
+			timeout, err := time.ParseDuration(v)
+			if err != nil {
+				return err
+			}
+			s.ShutdownTimeout = timeout
+			return nil
+		},
+	},
+	{
+		flag:  "gin-mode",
+		env:   "CONFIG_SERVICE_GIN_MODE",
+		usage: "gin mode: debug, release or test",
+		get:   func(s *Settings) string { return s.GinMode },
+		set:   func(s *Settings, v string) error { s.GinMode = v; return nil },
+	},
+	{
+		flag:  "log-level",
+		env:   "CONFIG_SERVICE_LOG_LEVEL",
+		usage: "log level: debug, info, warn or error",
+		get:   func(s *Settings) string { return s.LogLevel },
+		set:   func(s *Settings, v string) error { s.LogLevel = v; return nil },
+	},
+	{
+		flag:  "trace-exporter",
+		env:   "CONFIG_SERVICE_TRACE_EXPORTER",
+		usage: "trace exporter: none, stdout or otlp",
+		get:   func(s *Settings) string { return s.TraceExporter },
+		set:   func(s *Settings, v string) error { s.TraceExporter = v; return nil },
+	},
+	{
+		flag:  "trace-endpoint",
+		env:   "CONFIG_SERVICE_TRACE_ENDPOINT",
+		usage: "OTLP/HTTP endpoint URL, defaults to OTEL_EXPORTER_OTLP_ENDPOINT",
+		get:   func(s *Settings) string { return s.TraceEndpoint },
+		set:   func(s *Settings, v string) error { s.TraceEndpoint = v; return nil },
+	},
+	{
+		flag:  "auth-required",
+		env:   "CONFIG_SERVICE_AUTH_REQUIRED",
+		usage: "reject config and schema requests without credentials",
+		get:   func(s *Settings) string { return strconv.FormatBool(s.AuthRequired) },
+		set: func(s *Settings, v string) error {
+			required, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			s.AuthRequired = required
+			return nil
+		},
+	},
+	{
+		flag:  "jwt-jwks",
+		env:   "CONFIG_SERVICE_JWT_JWKS",
+		usage: "JWKS file or URL enabling bearer tokens",
+		get:   func(s *Settings) string { return s.JWTJWKS },
+		set:   func(s *Settings, v string) error { s.JWTJWKS = v; return nil },
+	},
+	{
+		flag:  "jwt-issuer",
+		env:   "CONFIG_SERVICE_JWT_ISSUER",
+		usage: "required iss claim of bearer tokens",
+		get:   func(s *Settings) string { return s.JWTIssuer },
+		set:   func(s *Settings, v string) error { s.JWTIssuer = v; return nil },
+	},
+	{
+		flag:  "jwt-audience",
+		env:   "CONFIG_SERVICE_JWT_AUDIENCE",
+		usage: "required aud claim of bearer tokens",
+		get:   func(s *Settings) string { return s.JWTAudience },
+		set:   func(s *Settings, v string) error { s.JWTAudience = v; return nil },
+	},
+	{
+		flag:  "jwt-role-claim",
+		env:   "CONFIG_SERVICE_JWT_ROLE_CLAIM",
+		usage: "claim holding the roles or groups of the caller",
+		get:   func(s *Settings) string { return s.JWTRoleClaim },
+		set:   func(s *Settings, v string) error { s.JWTRoleClaim = v; return nil },
+	},
+	{
+		flag:  "jwt-role-mapping",
+		env:   "CONFIG_SERVICE_JWT_ROLE_MAPPING",
+		usage: "comma separated claim=role pairs, e.g. platform-admins=admin",
+		get:   func(s *Settings) string { return formatMapping(s.JWTRoleMapping) },
+		set: func(s *Settings, v string) error {
+			mapping, err := parseMapping(v)
+			if err != nil {
+				return err
+			}
+			s.JWTRoleMapping = mapping
+			return nil
+		},
+	},
+	{
+		flag:  "jwt-tenant-claim",
+		env:   "CONFIG_SERVICE_JWT_TENANT_CLAIM",
+		usage: "claim binding bearer tokens to a tenant, empty disables it",
+		get:   func(s *Settings) string { return s.JWTTenantClaim },
+		set:   func(s *Settings, v string) error { s.JWTTenantClaim = v; return nil },
+	},
+	{
+		flag:  "tenant-max-configs",
+		env:   "CONFIG_SERVICE_TENANT_MAX_CONFIGS",
+		usage: "maximum number of configs per tenant, 0 for unlimited",
+		get:   func(s *Settings) string { return strconv.Itoa(s.TenantMaxConfigs) },
+		set: func(s *Settings, v string) error {
+			limit, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+			s.TenantMaxConfigs = limit
+			return nil
+		},
+	},
+	{
+		flag:  "tenant-quotas",
+		env:   "CONFIG_SERVICE_TENANT_QUOTAS",
+		usage: "comma separated tenant=max-configs pairs overriding tenant-max-configs",
+		get: func(s *Settings) string {
+			mapping := map[string]string{}
+			for tenantName, limit := range s.TenantQuotas {
+				mapping[tenantName] = strconv.Itoa(limit)
+			}
+			return formatMapping(mapping)
+		},
+		set: func(s *Settings, v string) error {
+			mapping, err := parseMapping(v)
+			if err != nil {
+				return err
+			}
+			quotas := map[string]int{}
+			for tenantName, value := range mapping {
+				limit, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("quota of %s: %w", strconv.Quote(tenantName), err)
+				}
+				quotas[tenantName] = limit
+			}
+			s.TenantQuotas = quotas
+			return nil
+		},
+	},
+	{
+		flag:  "environments",
+		env:   "CONFIG_SERVICE_ENVIRONMENTS",
+		usage: "comma separated environments configs can be stored in besides default",
+		get:   func(s *Settings) string { return strings.Join(s.Environments, ",") },
+		set: func(s *Settings, v string) error {
+			environments := []string{}
+			for _, name := range strings.Split(v, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					environments = append(environments, name)
+				}
+			}
+			s.Environments = environments
+			return nil
+		},
+	},
+	{
+		flag:  "secret-keys",
+		env:   "CONFIG_SERVICE_SECRET_KEYS",
+		usage: "comma separated id=key pairs of base64 encoded 256 bit keys encrypting secret values",
+		get:   func(s *Settings) string { return formatMapping(s.SecretKeys) },
+		set: func(s *Settings, v string) error {
+			keys, err := parseMapping(v)
+			if err != nil {
+				return err
+			}
+			s.SecretKeys = keys
+			return nil
+		},
+	},
+	{
+		flag:  "secret-key-id",
+		env:   "CONFIG_SERVICE_SECRET_KEY_ID",
+		usage: "ID of the secret key encrypting new secret values",
+		get:   func(s *Settings) string { return s.SecretKeyID },
+		set:   func(s *Settings, v string) error { s.SecretKeyID = v; return nil },
+	},
+	{
+		flag:  "required-approvals",
+		env:   "CONFIG_SERVICE_REQUIRED_APPROVALS",
+		usage: "comma separated schema=approvals or tenant/schema=approvals pairs, configs of listed schemas can only be changed through approved drafts",
+		get: func(s *Settings) string {
+			mapping := map[string]string{}
+			for schema, approvals := range s.RequiredApprovals {
+				mapping[schema] = strconv.Itoa(approvals)
+			}
+			return formatMapping(mapping)
+		},
+		set: func(s *Settings, v string) error {
+			mapping, err := parseMapping(v)
+			if err != nil {
+				return err
+			}
+			approvals := map[string]int{}
+			for schema, value := range mapping {
+				count, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("approvals of %s: %w", strconv.Quote(schema), err)
+				}
+				approvals[schema] = count
+			}
+			s.RequiredApprovals = approvals
+			return nil
+		},
+	},
+	{
+		flag:  "activation-interval",
+		env:   "CONFIG_SERVICE_ACTIVATION_INTERVAL",
+		usage: "how often scheduled config versions are checked for their activation",
+		get:   func(s *Settings) string { return s.ActivationInterval.String() },
+		set: func(s *Settings, v string) error {
+			interval, err := time.ParseDuration(v)
+			if err != nil {
+				return err
+			}
+			s.ActivationInterval = interval
+			return nil
+		},
+	},
+}
+
+// parseMapping parses comma separated key=value pairs.
+func parseMapping(value string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s is not a key=value pair", strconv.Quote(pair))
+		}
+		mapping[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return mapping, nil
+}
+
+func formatMapping(mapping map[string]string) string {
+	pairs := make([]string, 0, len(mapping))
+	for key, value := range mapping {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// LoadSettings registers the settings flags on flags, parses args and
+// resolves every setting with the following precedence, highest first:
+// command line flags, environment variables, the settings file given by
+// -config or CONFIG_SERVICE_CONFIG, and the defaults.
+func LoadSettings(flags *flag.FlagSet, args []string) (Settings, error) {
+	defaults := DefaultSettings()
+
+	settingsFile := flags.String("config", "", "optional YAML or JSON settings file (env "+settingsFileEnv+")")
+	values := map[string]*string{}
+	for _, field := range settingFields {
+		values[field.flag] = flags.String(field.flag, field.get(&defaults), fmt.Sprintf("%s (env %s)", field.usage, field.env))
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return Settings{}, err
+	}
+
+	settings := defaults
+
+	path := *settingsFile
+	if path == "" {
+		path = os.Getenv(settingsFileEnv)
+	}
+	if path != "" {
+		if err := readSettingsFile(path, &settings); err != nil {
+			return Settings{}, err
+		}
+	}
+
+	for _, field := range settingFields {
+		if value, ok := os.LookupEnv(field.env); ok {
+			if err := field.set(&settings, value); err != nil {
+				return Settings{}, fmt.Errorf("invalid %s: %w", field.env, err)
+			}
+		}
+	}
+
+	var visitErr error
+	flags.Visit(func(f *flag.Flag) {
+		for _, field := range settingFields {
+			if field.flag == f.Name && visitErr == nil {
+				if err := field.set(&settings, *values[field.flag]); err != nil {
+					visitErr = fmt.Errorf("invalid -%s: %w", field.flag, err)
+				}
+			}
+		}
+	})
+	if visitErr != nil {
+		return Settings{}, visitErr
+	}
+
+	return settings, settings.Validate()
+}
+
+func readSettingsFile(path string, settings *Settings) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read settings file: %w", err)
+	}
+
+	// JSON is valid YAML, unknown keys are rejected to catch typos
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(settings); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse settings file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (settings Settings) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(settings.HTTPAddr); err != nil {
+		errs = append(errs, fmt.Errorf("http_addr: %w", err))
+	}
+	if _, _, err := net.SplitHostPort(settings.GRPCAddr); err != nil {
+		errs = append(errs, fmt.Errorf("grpc_addr: %w", err))
+	}
+	if settings.HTTPAddr == settings.GRPCAddr {
+		errs = append(errs, errors.New("http_addr and grpc_addr must differ"))
+	}
+	if settings.DBPath == "" {
+		errs = append(errs, errors.New("db_path must not be empty"))
+	}
+	if info, err := os.Stat(settings.SchemaDir); err != nil || !info.IsDir() {
+		errs = append(errs, fmt.Errorf("schema_dir: %s is not a directory", strconv.Quote(settings.SchemaDir)))
+	}
+	if settings.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown_timeout must be positive"))
+	}
+	if settings.ActivationInterval <= 0 {
+		errs = append(errs, errors.New("activation_interval must be positive"))
+	}
+	switch settings.GinMode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		errs = append(errs, fmt.Errorf("gin_mode: unknown mode %s", strconv.Quote(settings.GinMode)))
+	}
+
+	if !logging.ValidLevel(settings.LogLevel) {
+		errs = append(errs, fmt.Errorf("log_level: unknown level %s", strconv.Quote(settings.LogLevel)))
+	}
+
+	if !tracing.ValidExporter(settings.TraceExporter) {
+		errs = append(errs, fmt.Errorf("trace_exporter: unknown exporter %s", strconv.Quote(settings.TraceExporter)))
+	}
+
+	if settings.JWTJWKS != "" {
+		if settings.JWTIssuer == "" || settings.JWTAudience == "" {
+			errs = append(errs, errors.New("jwt_issuer and jwt_audience are required with jwt_jwks"))
+		}
+		if settings.JWTRoleClaim == "" {
+			errs = append(errs, errors.New("jwt_role_claim must not be empty"))
+		}
+	}
+	for claim, role := range settings.JWTRoleMapping {
+		if !auth.ValidRole(role) {
+			errs = append(errs, fmt.Errorf("jwt_role_mapping: %s maps to unknown role %s", strconv.Quote(claim), strconv.Quote(role)))
+		}
+	}
+
+	if settings.TenantMaxConfigs < 0 {
+		errs = append(errs, errors.New("tenant_max_configs must not be negative"))
+	}
+	for tenantName, limit := range settings.TenantQuotas {
+		if !tenant.Valid(tenantName) {
+			errs = append(errs, fmt.Errorf("tenant_quotas: invalid tenant %s", strconv.Quote(tenantName)))
+		}
+		if limit < 0 {
+			errs = append(errs, fmt.Errorf("tenant_quotas: quota of %s must not be negative", strconv.Quote(tenantName)))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, name := range settings.Environments {
+		if !environment.Valid(name) {
+			errs = append(errs, fmt.Errorf("environments: invalid environment %s", strconv.Quote(name)))
+		}
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("environments: %s is listed twice", strconv.Quote(name)))
+		}
+		seen[name] = true
+	}
+
+	if _, err := secret.NewKeyring(settings.SecretKeys, settings.SecretKeyID); err != nil {
+		errs = append(errs, fmt.Errorf("secret_keys: %w", err))
+	}
+
+	for schema, approvals := range settings.RequiredApprovals {
+		if approvals < 1 {
+			errs = append(errs, fmt.Errorf("required_approvals: approvals of %s must be positive", strconv.Quote(schema)))
+		}
+	}
+
+	return errors.Join(errs...)
+}
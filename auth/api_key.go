@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// APIKeyHeader carries API keys on HTTP requests and gRPC metadata.
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix = "csk_"
+)
+
+// NewAPIKey generates a random API key and its display prefix.
+func NewAPIKey() (key string, prefix string) {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	key = apiKeyPrefix + hex.EncodeToString(secret)
+	return key, key[:len(apiKeyPrefix)+8]
+}
+
+// NewKeyID generates the public identifier of an API key.
+func NewKeyID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// HashAPIKey returns the hash stored in place of an API key. Keys carry 256
+// bits of entropy, so a plain SHA-256 is enough and allows lookups by hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
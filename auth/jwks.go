@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is how long fetched keys are used before the set
+	// is loaded again.
+	jwksRefreshInterval = 5 * time.Minute
+
+	// jwksMinRefreshInterval limits reloads triggered by unknown key IDs,
+	// so that forged tokens cannot make the service hammer the JWKS source.
+	jwksMinRefreshInterval = 30 * time.Second
+)
+
+// KeySet is a JSON Web Key Set loaded from a file or an http(s) URL. It is
+// reloaded periodically and when a token names an unknown key, so that key
+// rotations of the issuer are picked up.
+type KeySet struct {
+	source     string
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LoadKeySet loads the key set at source, which is either a file path or an
+// http(s) URL.
+func LoadKeySet(ctx context.Context, source string) (*KeySet, error) {
+	keySet := &KeySet{
+		source:     source,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if err := keySet.load(ctx); err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+// Key returns the public key with the given key ID. An empty kid matches the
+// only key of a single-key set.
+func (keySet *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	keySet.mu.Lock()
+	defer keySet.mu.Unlock()
+
+	age := time.Since(keySet.loadedAt)
+	_, known := keySet.lookup(kid)
+	if age >= jwksRefreshInterval || (!known && age >= jwksMinRefreshInterval) {
+		// Keep serving the previous keys when the source is unavailable
+		_ = keySet.load(ctx)
+	}
+
+	key, ok := keySet.lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (keySet *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keySet.keys) == 1 {
+		for _, key := range keySet.keys {
+			return key, true
+		}
+	}
+	key, ok := keySet.keys[kid]
+	return key, ok
+}
+
+func (keySet *KeySet) load(ctx context.Context) error {
+	keySet.loadedAt = time.Now()
+
+	content, err := keySet.read(ctx)
+	if err != nil {
+		return fmt.Errorf("load jwks %s: %w", keySet.source, err)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("parse jwks %s: %w", keySet.source, err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return fmt.Errorf("parse jwks %s: key %q: %w", keySet.source, jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("jwks %s contains no signing keys", keySet.source)
+	}
+
+	keySet.keys = keys
+	return nil
+}
+
+func (keySet *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(keySet.source, "http://") && !strings.HasPrefix(keySet.source, "https://") {
+		return os.ReadFile(keySet.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, keySet.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := keySet.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, 1<<20))
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
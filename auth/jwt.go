@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"config-service/tenant"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwtLeeway tolerates clock skew between the issuer and the service.
+const jwtLeeway = 30 * time.Second
+
+// ErrNoRoles is returned for valid tokens whose claims map to no role.
+var ErrNoRoles = errors.New("token grants no roles")
+
+// JWTConfig configures the validation of bearer tokens.
+type JWTConfig struct {
+	Issuer    string
+	Audience  string
+	RoleClaim string
+	// RoleMapping maps values of RoleClaim to roles. When empty, claim
+	// values that are role names are used as they are.
+	RoleMapping map[string]string
+	// TenantClaim binds tokens carrying this claim to the tenant it names
+	TenantClaim string
+}
+
+// JWTVerifier validates bearer tokens against a key set.
+type JWTVerifier struct {
+	keySet *KeySet
+	config JWTConfig
+	parser *jwt.Parser
+}
+
+func NewJWTVerifier(keySet *KeySet, config JWTConfig) *JWTVerifier {
+	return &JWTVerifier{
+		keySet: keySet,
+		config: config,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+			jwt.WithIssuer(config.Issuer),
+			jwt.WithAudience(config.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(jwtLeeway),
+		),
+	}
+}
+
+// Verify validates token and returns the principal it identifies.
+func (verifier *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := verifier.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return verifier.keySet.Key(ctx, kid)
+	})
+	if err != nil {
+		return Principal{}, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Principal{}, errors.New("token has no subject")
+	}
+
+	roles := verifier.roles(claims[verifier.config.RoleClaim])
+	if len(roles) == 0 {
+		return Principal{}, ErrNoRoles
+	}
+
+	principal := Principal{
+		Subject: "jwt:" + subject,
+		Method:  MethodJWT,
+		Roles:   roles,
+	}
+
+	if claim, ok := claims[verifier.config.TenantClaim]; ok && verifier.config.TenantClaim != "" {
+		tenantName, _ := claim.(string)
+		if !tenant.Valid(tenantName) {
+			return Principal{}, errors.New("token has an invalid tenant claim")
+		}
+		principal.Tenant = tenantName
+	}
+
+	return principal, nil
+}
+
+// roles maps the role claim, either a single string or a list of strings,
+// to known roles.
+func (verifier *JWTVerifier) roles(claim interface{}) []string {
+	var values []string
+	switch claim := claim.(type) {
+	case string:
+		values = []string{claim}
+	case []interface{}:
+		for _, value := range claim {
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+
+	roles := []string{}
+	for _, value := range values {
+		role := value
+		if len(verifier.config.RoleMapping) > 0 {
+			role = verifier.config.RoleMapping[value]
+		}
+		if ValidRole(role) && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
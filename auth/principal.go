@@ -0,0 +1,93 @@
+// Package auth holds the identity of the caller of a request.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// Roles, from the least to the most privileged.
+const (
+	RoleReader   = "reader"
+	RoleWriter   = "writer"
+	RoleApprover = "approver"
+	RoleAdmin    = "admin"
+)
+
+// Roles lists every known role.
+var Roles = []string{RoleReader, RoleWriter, RoleApprover, RoleAdmin}
+
+// ValidRole reports whether role is a known role.
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// Grants reports whether granted includes the permissions of required. Every
+// role includes the roles listed before it in Roles.
+func Grants(granted, required string) bool {
+	grantedIndex := slices.Index(Roles, granted)
+	requiredIndex := slices.Index(Roles, required)
+	return grantedIndex >= 0 && requiredIndex >= 0 && grantedIndex >= requiredIndex
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller, e.g. "apikey:deployer", and is recorded
+	// as the author of the config versions it creates.
+	Subject string
+	Method  string
+	Roles   []string
+	// Tenant restricts the principal to one tenant, empty principals may
+	// use every tenant
+	Tenant string
+}
+
+// HasRole reports whether the global roles of the principal include role.
+func (p Principal) HasRole(role string) bool {
+	for _, granted := range p.Roles {
+		if Grants(granted, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+type sourceKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of ctx, if the request was authenticated.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Subject returns the subject of the principal of ctx, or "" for anonymous
+// requests.
+func Subject(ctx context.Context) string {
+	principal, _ := FromContext(ctx)
+	return principal.Subject
+}
+
+// WithSource returns a copy of ctx carrying the network address the request
+// came from.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// Source returns the address the request of ctx came from, or "" for
+// requests that did not come over the network, e.g. admin commands.
+func Source(ctx context.Context) string {
+	source, _ := ctx.Value(sourceKey{}).(string)
+	return source
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type cacheEntry struct {
+	etag string
+	body []byte
+}
+
+// etagCache remembers fetched bodies by path so unchanged configs can be
+// revalidated with If-None-Match instead of being downloaded again.
+type etagCache struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+func newETagCache() *etagCache {
+	return &etagCache{entries: map[string]cacheEntry{}}
+}
+
+func (cache *etagCache) get(path string) (cacheEntry, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	entry, ok := cache.entries[path]
+	return entry, ok
+}
+
+func (cache *etagCache) put(path string, entry cacheEntry) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.entries[path] = entry
+}
+
+// fallbackFile persists the last successful response of every fetched path
+// as a JSON object on disk.
+type fallbackFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newFallbackFile(path string) *fallbackFile {
+	return &fallbackFile{path: path}
+}
+
+func (fallback *fallbackFile) read() map[string]json.RawMessage {
+	entries := map[string]json.RawMessage{}
+
+	content, err := os.ReadFile(fallback.path)
+	if err != nil {
+		return entries
+	}
+
+	_ = json.Unmarshal(content, &entries)
+	return entries
+}
+
+func (fallback *fallbackFile) load(path string) ([]byte, bool) {
+	fallback.mu.Lock()
+	defer fallback.mu.Unlock()
+
+	data, ok := fallback.read()[path]
+	return data, ok
+}
+
+// store writes through a temporary file so a crash never leaves a
+// truncated fallback behind. Failures are ignored, the fallback is best effort.
+func (fallback *fallbackFile) store(path string, data []byte) {
+	if !json.Valid(data) {
+		return
+	}
+
+	fallback.mu.Lock()
+	defer fallback.mu.Unlock()
+
+	entries := fallback.read()
+	entries[path] = data
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fallback.path), filepath.Base(fallback.path)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+
+	_ = os.Rename(tmp.Name(), fallback.path)
+}
@@ -0,0 +1,301 @@
+// Package client is the Go SDK for the configuration management service.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultRetries    = 3
+	defaultRetryDelay = 100 * time.Millisecond
+)
+
+// Client talks to the REST API of the configuration service.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	retries     int
+	retryDelay  time.Duration
+	cache       *etagCache
+	fallback    *fallbackFile
+	apiKey      string
+	token       string
+	tenant      string
+	environment string
+	strict      bool
+	override    string
+}
+
+// Option customizes a Client created with New.
+type Option func(*Client)
+
+// WithHTTPClient replaces the underlying HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of every single HTTP attempt.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetries sets how many times a failed read is retried and the initial
+// delay between attempts, which doubles after every attempt.
+func WithRetries(retries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryDelay = delay
+	}
+}
+
+// WithoutCache disables the ETag cache used for fetches.
+func WithoutCache() Option {
+	return func(c *Client) {
+		c.cache = nil
+	}
+}
+
+// WithFallbackFile keeps the last successful fetch of every config in path
+// and serves it when the service is unreachable.
+func WithFallbackFile(path string) Option {
+	return func(c *Client) {
+		c.fallback = newFallbackFile(path)
+	}
+}
+
+// WithAPIKey authenticates every request with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithBearerToken authenticates every request with a bearer token issued by
+// the identity provider of the service.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithTenant sends every request to the configs and schemas of tenant,
+// instead of the tenant of the credentials or the default tenant.
+func WithTenant(tenant string) Option {
+	return func(c *Client) {
+		c.tenant = tenant
+	}
+}
+
+// WithEnvironment reads and writes the configs, variables and drafts of
+// environment, e.g. "prod", instead of the default environment.
+func WithEnvironment(environment string) Option {
+	return func(c *Client) {
+		c.environment = environment
+	}
+}
+
+// WithStrictVariables fails fetches of configs whose placeholders use
+// undefined variables instead of returning them with the placeholders left.
+func WithStrictVariables() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithFreezeOverride makes every change despite the freezes locking the
+// config, recording reason with it. Overriding freezes requires the
+// approver role.
+func WithFreezeOverride(reason string) Option {
+	return func(c *Client) {
+		c.override = reason
+	}
+}
+
+// New creates a client for the service listening at baseURL,
+// e.g. "http://localhost:3000".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		retryDelay: defaultRetryDelay,
+		cache:      newETagCache(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func configPath(schema, name string, suffix ...string) string {
+	segments := append([]string{"configs", url.PathEscape(schema), url.PathEscape(name)}, suffix...)
+	return "/" + strings.Join(segments, "/")
+}
+
+// do sends a request and decodes a successful JSON response into out.
+// Reads are retried on network errors and retryable status codes, writes
+// are sent once because retrying them could create duplicate versions.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	// Scoping the path also keeps the cached responses of tenants and
+	// environments apart
+	if c.environment != "" && (strings.HasPrefix(path, "/configs") || strings.HasPrefix(path, "/variables") || strings.HasPrefix(path, "/drafts")) {
+		path = withQuery(path, "env="+url.QueryEscape(c.environment))
+	}
+	if c.override != "" && method != http.MethodGet && (strings.HasPrefix(path, "/configs") || strings.HasPrefix(path, "/drafts")) {
+		path = withQuery(path, "override="+url.QueryEscape(c.override))
+	}
+	if c.tenant != "" {
+		path = "/tenants/" + url.PathEscape(c.tenant) + path
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	attempts := 1
+	if method == http.MethodGet {
+		attempts += c.retries
+	}
+
+	var lastErr error
+	delay := c.retryDelay
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		data, err := c.send(ctx, method, path, payload)
+		if err == nil {
+			if c.fallback != nil && method == http.MethodGet {
+				c.fallback.store(path, data)
+			}
+			return decode(data, out)
+		}
+
+		lastErr = err
+		if !retryable(err) {
+			return err
+		}
+	}
+
+	if c.fallback != nil && method == http.MethodGet {
+		if data, ok := c.fallback.load(path); ok {
+			return decode(data, out)
+		}
+	}
+
+	return lastErr
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	var cached cacheEntry
+	var isCached bool
+	if c.cache != nil && method == http.MethodGet {
+		cached, isCached = c.cache.get(path)
+		if isCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &UnavailableError{Err: err}
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, &UnavailableError{Err: err}
+	}
+
+	if res.StatusCode == http.StatusNotModified && isCached {
+		return cached.body, nil
+	}
+
+	if res.StatusCode >= 300 {
+		return nil, newAPIError(res.StatusCode, data)
+	}
+
+	if c.cache != nil && method == http.MethodGet {
+		if etag := res.Header.Get("ETag"); etag != "" {
+			c.cache.put(path, cacheEntry{etag: etag, body: data})
+		}
+	}
+
+	return data, nil
+}
+
+func decode(data []byte, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+func retryable(err error) bool {
+	var unavailable *UnavailableError
+	if errors.As(err, &unavailable) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+package client
+
+import (
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is a config version whose data is decoded into T.
+type Config[T any] struct {
+	Environment string    `json:"environment,omitempty"`
+	Schema      string    `json:"schema"`
+	Name        string    `json:"name"`
+	Version     int       `json:"version"`
+	Data        T         `json:"data"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateConfig stores the first version of a config. Data can be a map or
+// any struct that encodes to a document matching the schema.
+func (c *Client) CreateConfig(ctx context.Context, schema, name string, data interface{}) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodPost, configPath(schema, name), data, &result)
+	return result, err
+}
+
+// UpdateConfig stores data as a new version of an existing config.
+func (c *Client) UpdateConfig(ctx context.Context, schema, name string, data interface{}) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodPut, configPath(schema, name), data, &result)
+	return result, err
+}
+
+// BatchUpdateConfigs stores a new version of every item in one transaction,
+// creating the configs that do not exist yet. When any item fails nothing
+// is stored and the error names the item.
+func (c *Client) BatchUpdateConfigs(ctx context.Context, items []web.ConfigBatchItem) (web.ConfigBatchResponse, error) {
+	var result web.ConfigBatchResponse
+	err := c.do(ctx, http.MethodPost, "/configs", web.ConfigBatchRequest{Items: items}, &result)
+	return result, err
+}
+
+// CreateConfigAt stores the first version of a config, becoming active at
+// the given time.
+func (c *Client) CreateConfigAt(ctx context.Context, schema, name string, data interface{}, effectiveAt time.Time) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodPost, scheduledPath(schema, name, effectiveAt), data, &result)
+	return result, err
+}
+
+// UpdateConfigAt stores data as a new version of an existing config,
+// becoming active at the given time. Fetches return the previous version
+// until then.
+func (c *Client) UpdateConfigAt(ctx context.Context, schema, name string, data interface{}, effectiveAt time.Time) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodPut, scheduledPath(schema, name, effectiveAt), data, &result)
+	return result, err
+}
+
+// CancelConfig cancels a version scheduled to become active later.
+func (c *Client) CancelConfig(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	req := web.ConfigCancelRequest{Version: version}
+	err := c.do(ctx, http.MethodPost, configPath(schema, name, "cancel"), req, &result)
+	return result, err
+}
+
+// RollbackConfig creates a new version with the data of the given version.
+func (c *Client) RollbackConfig(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	req := web.ConfigRollbackRequest{Version: version}
+	err := c.do(ctx, http.MethodPost, configPath(schema, name, "rollback"), req, &result)
+	return result, err
+}
+
+// PromoteConfig copies a version of a config from one environment to
+// another. Dry runs only report the changes the promotion would make.
+func (c *Client) PromoteConfig(ctx context.Context, schema, name string, req web.ConfigPromoteRequest) (web.ConfigPromoteResponse, error) {
+	var result web.ConfigPromoteResponse
+	err := c.do(ctx, http.MethodPost, configPath(schema, name, "promote"), req, &result)
+	return result, err
+}
+
+// FetchConfig returns the given version of a config, or the latest one when
+// version is 0.
+func (c *Client) FetchConfig(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodGet, c.fetchPath(schema, name, version), nil, &result)
+	return result, err
+}
+
+// BulkFetch returns the configs of many schema/name, schema/name@version or
+// schema/name@label selectors from one consistent snapshot. Selectors that
+// failed have the error and the status code of a single fetch in their
+// result.
+func (c *Client) BulkFetch(ctx context.Context, selectors ...string) (web.ConfigBulkResponse, error) {
+	query := url.Values{"select": selectors}
+	if c.strict {
+		query.Set("strict", "true")
+	}
+
+	var result web.ConfigBulkResponse
+	err := c.do(ctx, http.MethodGet, "/configs?"+query.Encode(), nil, &result)
+	return result, err
+}
+
+// FetchLayers is FetchConfig also returning the config and its ancestors as
+// stored, nearest first, in the Layers of the result.
+func (c *Client) FetchLayers(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodGet, withQuery(c.fetchPath(schema, name, version), "layers=true"), nil, &result)
+	return result, err
+}
+
+// FetchUnresolved is FetchConfig keeping the references to other configs
+// instead of their values.
+func (c *Client) FetchUnresolved(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodGet, withQuery(c.fetchPath(schema, name, version), "resolve=false"), nil, &result)
+	return result, err
+}
+
+// FetchSecrets is FetchConfig returning the values marked secret by the
+// schema unmasked, which requires the approver role.
+func (c *Client) FetchSecrets(ctx context.Context, schema, name string, version int) (web.ConfigResponse, error) {
+	var result web.ConfigResponse
+	err := c.do(ctx, http.MethodGet, withQuery(c.fetchPath(schema, name, version), "reveal=true"), nil, &result)
+	return result, err
+}
+
+// ListReferrers returns the references of the latest versions of configs to
+// a config.
+func (c *Client) ListReferrers(ctx context.Context, schema, name string) ([]web.ConfigReferrerResponse, error) {
+	var result []web.ConfigReferrerResponse
+	err := c.do(ctx, http.MethodGet, configPath(schema, name, "referrers"), nil, &result)
+	return result, err
+}
+
+// ListLabels returns the labels naming versions of a config.
+func (c *Client) ListLabels(ctx context.Context, schema, name string) ([]web.ConfigLabelResponse, error) {
+	var result []web.ConfigLabelResponse
+	err := c.do(ctx, http.MethodGet, configPath(schema, name, "labels"), nil, &result)
+	return result, err
+}
+
+// SetLabel names a version of a config by label, moving the label when it
+// named another version. Fetches and promotions can then select the version
+// by label.
+func (c *Client) SetLabel(ctx context.Context, schema, name, label string, version int) (web.ConfigLabelResponse, error) {
+	var result web.ConfigLabelResponse
+	err := c.do(ctx, http.MethodPut, configPath(schema, name, "labels", url.PathEscape(label)), web.ConfigLabelRequest{Version: version}, &result)
+	return result, err
+}
+
+// DeleteLabel removes a label of a config.
+func (c *Client) DeleteLabel(ctx context.Context, schema, name, label string) error {
+	return c.do(ctx, http.MethodDelete, configPath(schema, name, "labels", url.PathEscape(label)), nil, nil)
+}
+
+// ListVersions returns every version of a config, oldest first.
+func (c *Client) ListVersions(ctx context.Context, schema, name string) (web.ConfigResponses, error) {
+	var result web.ConfigResponses
+	err := c.do(ctx, http.MethodGet, configPath(schema, name, "versions"), nil, &result)
+	return result, err
+}
+
+// ListConfigEnvironments returns the latest version of a config in every
+// environment having one.
+func (c *Client) ListConfigEnvironments(ctx context.Context, schema, name string) ([]web.ConfigResponse, error) {
+	var result []web.ConfigResponse
+	err := c.do(ctx, http.MethodGet, configPath(schema, name, "environments"), nil, &result)
+	return result, err
+}
+
+// ListEnvironments returns the environments of the service with their
+// number of configs.
+func (c *Client) ListEnvironments(ctx context.Context) ([]web.EnvironmentResponse, error) {
+	var result []web.EnvironmentResponse
+	err := c.do(ctx, http.MethodGet, "/environments", nil, &result)
+	return result, err
+}
+
+// ListSchemas returns the schemas loaded by the service.
+func (c *Client) ListSchemas(ctx context.Context) ([]web.SchemaResponse, error) {
+	var result []web.SchemaResponse
+	err := c.do(ctx, http.MethodGet, "/schemas/", nil, &result)
+	return result, err
+}
+
+// GetSchema returns the raw JSON schema stored in the given file,
+// e.g. "payment_config.json".
+func (c *Client) GetSchema(ctx context.Context, file string) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.do(ctx, http.MethodGet, "/schemas/"+url.PathEscape(file), nil, &result)
+	return result, err
+}
+
+// Fetch is FetchConfig decoding the config data into T.
+func Fetch[T any](ctx context.Context, c *Client, schema, name string, version int) (Config[T], error) {
+	var result Config[T]
+	err := c.do(ctx, http.MethodGet, c.fetchPath(schema, name, version), nil, &result)
+	return result, err
+}
+
+func (c *Client) fetchPath(schema, name string, version int) string {
+	path := configPath(schema, name)
+	if version > 0 {
+		path += "?version=" + strconv.Itoa(version)
+	}
+	if c.strict {
+		path = withQuery(path, "strict=true")
+	}
+	return path
+}
+
+func scheduledPath(schema, name string, effectiveAt time.Time) string {
+	return withQuery(configPath(schema, name), "effective_at="+url.QueryEscape(effectiveAt.Format(time.RFC3339)))
+}
+
+func withQuery(path, query string) string {
+	if strings.Contains(path, "?") {
+		return path + "&" + query
+	}
+	return path + "?" + query
+}
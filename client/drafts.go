@@ -0,0 +1,60 @@
+package client
+
+import (
+	"config-service/model/web"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ProposeConfig stores a draft of the next version of a config, published
+// once enough reviewers approve it.
+func (c *Client) ProposeConfig(ctx context.Context, schema, name string, data map[string]interface{}, comment string) (web.DraftResponse, error) {
+	var result web.DraftResponse
+	err := c.do(ctx, http.MethodPost, configPath(schema, name, "drafts"), web.DraftCreateRequest{Data: data, Comment: comment}, &result)
+	return result, err
+}
+
+// ListDrafts returns the drafts with status, pending when empty, of the
+// environment of the client.
+func (c *Client) ListDrafts(ctx context.Context, status string) ([]web.DraftResponse, error) {
+	path := "/drafts"
+	if status != "" {
+		path = withQuery(path, "status="+url.QueryEscape(status))
+	}
+
+	var result []web.DraftResponse
+	err := c.do(ctx, http.MethodGet, path, nil, &result)
+	return result, err
+}
+
+// GetDraft returns a draft with its changes to the version it was proposed
+// on.
+func (c *Client) GetDraft(ctx context.Context, id int64) (web.DraftResponse, error) {
+	var result web.DraftResponse
+	err := c.do(ctx, http.MethodGet, draftPath(id, ""), nil, &result)
+	return result, err
+}
+
+// ApproveDraft approves a draft, publishing it when it has enough approvals.
+func (c *Client) ApproveDraft(ctx context.Context, id int64, comment string) (web.DraftResponse, error) {
+	var result web.DraftResponse
+	err := c.do(ctx, http.MethodPost, draftPath(id, "approve"), web.DraftReviewRequest{Comment: comment}, &result)
+	return result, err
+}
+
+// RejectDraft rejects a draft, comment tells its author why.
+func (c *Client) RejectDraft(ctx context.Context, id int64, comment string) (web.DraftResponse, error) {
+	var result web.DraftResponse
+	err := c.do(ctx, http.MethodPost, draftPath(id, "reject"), web.DraftReviewRequest{Comment: comment}, &result)
+	return result, err
+}
+
+func draftPath(id int64, action string) string {
+	path := "/drafts/" + strconv.FormatInt(id, 10)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when the service answers with a non-success status.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("config service returned %d %s: %s", e.StatusCode, e.Status, e.Message)
+}
+
+// UnavailableError is returned when the service could not be reached.
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return "config service unavailable: " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Message:    string(body),
+	}
+
+	// Config routes answer with web.WebResponse, schema routes with {"error": ...}
+	var payload struct {
+		Status string      `json:"status"`
+		Data   interface{} `json:"data"`
+		Error  string      `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		if payload.Status != "" {
+			apiErr.Status = payload.Status
+		}
+		if payload.Data != nil {
+			apiErr.Message = fmt.Sprintf("%v", payload.Data)
+		} else if payload.Error != "" {
+			apiErr.Message = payload.Error
+		}
+	}
+
+	return apiErr
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether the config or version does not exist.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether the request conflicts with the stored state,
+// e.g. when creating a config that already exists.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsValidationError reports whether the request was rejected as invalid,
+// e.g. because the data does not match its schema.
+func IsValidationError(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsUnauthorized reports whether the request lacked valid credentials.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether the caller is not allowed to make the request.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsLocked reports whether a freeze locks the config against the change,
+// see WithFreezeOverride.
+func IsLocked(err error) bool {
+	return hasStatus(err, http.StatusLocked)
+}
+
+// IsUnavailable reports whether the service could not be reached.
+func IsUnavailable(err error) bool {
+	var unavailable *UnavailableError
+	return errors.As(err, &unavailable)
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"config-service/model/web"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListVariables returns the variables of the environment of the client.
+func (c *Client) ListVariables(ctx context.Context) ([]web.VariableResponse, error) {
+	var result []web.VariableResponse
+	err := c.do(ctx, http.MethodGet, "/variables", nil, &result)
+	return result, err
+}
+
+// SetVariable creates a variable of the environment of the client or
+// replaces its value, which can be any JSON value.
+func (c *Client) SetVariable(ctx context.Context, name string, value interface{}) (web.VariableResponse, error) {
+	var result web.VariableResponse
+	err := c.do(ctx, http.MethodPut, "/variables/"+url.PathEscape(name), web.VariableSetRequest{Value: value}, &result)
+	return result, err
+}
+
+// DeleteVariable deletes a variable of the environment of the client.
+func (c *Client) DeleteVariable(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/variables/"+url.PathEscape(name), nil, nil)
+}
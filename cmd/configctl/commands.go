@@ -0,0 +1,521 @@
+package main
+
+import (
+	"config-service/client"
+	"config-service/diff"
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments and checks the number of positional arguments.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	flags.SetOutput(io.Discard)
+
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, usageError{msg: fmt.Sprintf("%s: %v", flags.Name(), err)}
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if len(positional) < min || len(positional) > max {
+		return nil, usageError{msg: fmt.Sprintf("%s: wrong number of arguments", flags.Name())}
+	}
+
+	return positional, nil
+}
+
+func (c *cli) get(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	version := flags.Int("version", 0, "version to fetch, latest when 0")
+	layers := flags.Bool("layers", false, "also print the layers of an inheriting config")
+	resolve := flags.Bool("resolve", true, "replace references to other configs by their values")
+	reveal := flags.Bool("reveal", false, "print secret values unmasked")
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	fetch := c.client.FetchConfig
+	switch {
+	case *reveal && (*layers || !*resolve):
+		return usageError{msg: "get: -reveal can't be combined with -layers or -resolve=false"}
+	case *layers && !*resolve:
+		return usageError{msg: "get: -layers and -resolve=false can't be combined"}
+	case *reveal:
+		fetch = c.client.FetchSecrets
+	case *layers:
+		fetch = c.client.FetchLayers
+	case !*resolve:
+		fetch = c.client.FetchUnresolved
+	}
+	config, err := fetch(ctx, positional[0], positional[1], *version)
+	if err != nil {
+		return err
+	}
+
+	return c.printConfig(config)
+}
+
+func (c *cli) fetch(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+
+	// The service takes up to 100 selectors per request
+	selectors, err := parseArgs(flags, args, 1, 100)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.client.BulkFetch(ctx, selectors...)
+	if err != nil {
+		return err
+	}
+	if err := c.printBulk(result); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, item := range result.Results {
+		if item.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d selectors failed", failed, len(result.Results))
+	}
+	return nil
+}
+
+func (c *cli) put(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("put", flag.ContinueOnError)
+	file := flags.String("f", "-", "JSON or YAML file with the config data, - for stdin")
+	format := flags.String("format", "auto", "input format: auto, json or yaml")
+	create := flags.Bool("create", false, "only create, fail when the config already exists")
+	draft := flags.Bool("draft", false, "propose the data as a draft to be approved")
+	comment := flags.String("m", "", "comment of the draft")
+	at := flags.String("at", "", "RFC 3339 time the new version becomes active, e.g. 2024-01-02T02:00:00Z")
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if *draft && *create {
+		return usageError{msg: "put: -draft and -create can't be combined"}
+	}
+	var effectiveAt time.Time
+	if *at != "" {
+		if *draft {
+			return usageError{msg: "put: -draft and -at can't be combined"}
+		}
+		effectiveAt, err = time.Parse(time.RFC3339, *at)
+		if err != nil {
+			return usageError{msg: "put: -at must be an RFC 3339 time, e.g. 2024-01-02T02:00:00Z"}
+		}
+	}
+
+	data, err := readData(*file, *format, c.stdin)
+	if err != nil {
+		return err
+	}
+
+	schema, name := positional[0], positional[1]
+
+	if *draft {
+		proposed, err := c.client.ProposeConfig(ctx, schema, name, data, *comment)
+		if err != nil {
+			return err
+		}
+		return c.printDraft(proposed)
+	}
+
+	// Versions with -at become active at that time
+	createConfig := func() (web.ConfigResponse, error) {
+		if effectiveAt.IsZero() {
+			return c.client.CreateConfig(ctx, schema, name, data)
+		}
+		return c.client.CreateConfigAt(ctx, schema, name, data, effectiveAt)
+	}
+	updateConfig := func() (web.ConfigResponse, error) {
+		if effectiveAt.IsZero() {
+			return c.client.UpdateConfig(ctx, schema, name, data)
+		}
+		return c.client.UpdateConfigAt(ctx, schema, name, data, effectiveAt)
+	}
+
+	var config web.ConfigResponse
+	if *create {
+		config, err = createConfig()
+	} else {
+		config, err = updateConfig()
+		if client.IsNotFound(err) {
+			config, err = createConfig()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.printConfig(config)
+}
+
+func (c *cli) apply(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	file := flags.String("f", "-", "JSON or YAML file with the items of the batch, - for stdin")
+	format := flags.String("format", "auto", "input format: auto, json or yaml")
+
+	if _, err := parseArgs(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	data, err := readData(*file, *format, c.stdin)
+	if err != nil {
+		return err
+	}
+
+	// The items share the schema of the request body of the batch endpoint
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var batch web.ConfigBatchRequest
+	if err := json.Unmarshal(content, &batch); err != nil {
+		return fmt.Errorf("parse batch items: %w", err)
+	}
+	if len(batch.Items) == 0 {
+		return fmt.Errorf("the batch has no items")
+	}
+
+	result, err := c.client.BatchUpdateConfigs(ctx, batch.Items)
+	if err != nil {
+		return err
+	}
+
+	return c.printBatch(result)
+}
+
+func (c *cli) diff(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	from := flags.Int("from", 0, "base version, latest when 0")
+	to := flags.Int("to", 0, "target version, latest when 0")
+	file := flags.String("f", "", "compare the base version against this JSON or YAML file, - for stdin")
+	format := flags.String("format", "auto", "input format of -f: auto, json or yaml")
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	schema, name := positional[0], positional[1]
+
+	base, err := c.client.FetchConfig(ctx, schema, name, *from)
+	if err != nil {
+		return err
+	}
+
+	var target map[string]interface{}
+	if *file != "" {
+		target, err = readData(*file, *format, c.stdin)
+	} else {
+		var config web.ConfigResponse
+		config, err = c.client.FetchConfig(ctx, schema, name, *to)
+		target = config.Data
+	}
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(base.Data, normalize(target))
+	if err := c.printChanges(changes); err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		return errDiffFound
+	}
+	return nil
+}
+
+func (c *cli) rollback(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("rollback", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	version, err := strconv.Atoi(positional[2])
+	if err != nil || version <= 0 {
+		return usageError{msg: "rollback: version must be a positive integer"}
+	}
+
+	config, err := c.client.RollbackConfig(ctx, positional[0], positional[1], version)
+	if err != nil {
+		return err
+	}
+
+	return c.printConfig(config)
+}
+
+func (c *cli) cancel(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("cancel", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	version, err := strconv.Atoi(positional[2])
+	if err != nil || version <= 0 {
+		return usageError{msg: "cancel: version must be a positive integer"}
+	}
+
+	config, err := c.client.CancelConfig(ctx, positional[0], positional[1], version)
+	if err != nil {
+		return err
+	}
+
+	return c.printConfig(config)
+}
+
+func (c *cli) promote(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("promote", flag.ContinueOnError)
+	from := flags.String("from", "", "source environment")
+	to := flags.String("to", "", "target environment")
+	version := flags.Int("version", 0, "source version, latest when 0")
+	label := flags.String("label", "", "label naming the source version instead of -version")
+	dryRun := flags.Bool("dry-run", false, "only print the changes the promotion would make")
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return usageError{msg: "promote: -from and -to are required"}
+	}
+	if *label != "" && *version != 0 {
+		return usageError{msg: "promote: -label and -version can't be combined"}
+	}
+
+	result, err := c.client.PromoteConfig(ctx, positional[0], positional[1], web.ConfigPromoteRequest{
+		From:    *from,
+		Version: *version,
+		Label:   *label,
+		To:      *to,
+		DryRun:  *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		return c.printChanges(result.Changes)
+	}
+	return c.printConfig(result.Config)
+}
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	configs, err := c.client.ListVersions(ctx, positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	return c.printVersions(configs)
+}
+
+func (c *cli) schemas(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("schemas", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 1 {
+		schema, err := c.client.GetSchema(ctx, positional[0])
+		if err != nil {
+			return err
+		}
+		return printJSON(c.stdout, schema)
+	}
+
+	schemas, err := c.client.ListSchemas(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.printSchemas(schemas)
+}
+
+func (c *cli) envs(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("envs", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 0, 2)
+	if err != nil {
+		return err
+	}
+
+	switch len(positional) {
+	case 0:
+		environments, err := c.client.ListEnvironments(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printEnvironments(environments)
+	case 2:
+		configs, err := c.client.ListConfigEnvironments(ctx, positional[0], positional[1])
+		if err != nil {
+			return err
+		}
+		return c.printConfigEnvironments(configs)
+	default:
+		return usageError{msg: "envs: wrong number of arguments"}
+	}
+}
+
+func (c *cli) referrers(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("referrers", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	referrers, err := c.client.ListReferrers(ctx, positional[0], positional[1])
+	if err != nil {
+		return err
+	}
+
+	return c.printReferrers(referrers)
+}
+
+func (c *cli) labels(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("labels", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 2, 5)
+	if err != nil {
+		return err
+	}
+	schema, name := positional[0], positional[1]
+
+	switch {
+	case len(positional) == 2:
+		labels, err := c.client.ListLabels(ctx, schema, name)
+		if err != nil {
+			return err
+		}
+		return c.printLabels(labels)
+	case len(positional) == 5 && positional[2] == "set":
+		version, err := strconv.Atoi(positional[4])
+		if err != nil || version <= 0 {
+			return usageError{msg: fmt.Sprintf("labels: invalid version %q", positional[4])}
+		}
+		label, err := c.client.SetLabel(ctx, schema, name, positional[3], version)
+		if err != nil {
+			return err
+		}
+		return c.printLabels([]web.ConfigLabelResponse{label})
+	case len(positional) == 4 && positional[2] == "delete":
+		return c.client.DeleteLabel(ctx, schema, name, positional[3])
+	default:
+		return usageError{msg: "labels: expected <schema> <name>, optionally followed by set <label> <N> or delete <label>"}
+	}
+}
+
+func (c *cli) vars(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("vars", flag.ContinueOnError)
+
+	positional, err := parseArgs(flags, args, 0, 3)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(positional) == 0:
+		variables, err := c.client.ListVariables(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printVariables(variables)
+	case len(positional) == 3 && positional[0] == "set":
+		// Values are JSON, anything else is taken as a string
+		var value interface{}
+		if err := json.Unmarshal([]byte(positional[2]), &value); err != nil {
+			value = positional[2]
+		}
+		variable, err := c.client.SetVariable(ctx, positional[1], value)
+		if err != nil {
+			return err
+		}
+		return c.printVariables([]web.VariableResponse{variable})
+	case len(positional) == 2 && positional[0] == "delete":
+		return c.client.DeleteVariable(ctx, positional[1])
+	default:
+		return usageError{msg: "vars: expected no arguments, set <name> <value> or delete <name>"}
+	}
+}
+
+func (c *cli) drafts(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("drafts", flag.ContinueOnError)
+	status := flags.String("status", "", "list drafts with this status: pending (default), published, rejected or superseded")
+	comment := flags.String("m", "", "comment of the review, required to reject")
+
+	positional, err := parseArgs(flags, args, 0, 2)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		drafts, err := c.client.ListDrafts(ctx, *status)
+		if err != nil {
+			return err
+		}
+		return c.printDrafts(drafts)
+	}
+
+	if len(positional) != 2 {
+		return usageError{msg: "drafts: expected no arguments, show <id>, approve <id> or reject <id>"}
+	}
+	id, err := strconv.ParseInt(positional[1], 10, 64)
+	if err != nil {
+		return usageError{msg: "drafts: the draft ID must be an integer"}
+	}
+
+	var draft web.DraftResponse
+	switch positional[0] {
+	case "show":
+		draft, err = c.client.GetDraft(ctx, id)
+	case "approve":
+		draft, err = c.client.ApproveDraft(ctx, id, *comment)
+	case "reject":
+		draft, err = c.client.RejectDraft(ctx, id, *comment)
+	default:
+		return usageError{msg: "drafts: expected no arguments, show <id>, approve <id> or reject <id>"}
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.printDraft(draft)
+}
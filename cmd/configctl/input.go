@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readData reads a config document from a file or from stdin when file is "-".
+func readData(file, format string, stdin io.Reader) (map[string]interface{}, error) {
+	var content []byte
+	var err error
+	if file == "-" {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config data: %w", err)
+	}
+
+	if format == "auto" {
+		format = detectFormat(file, content)
+	}
+
+	var data map[string]interface{}
+	switch format {
+	case "json":
+		err = json.Unmarshal(content, &data)
+	case "yaml":
+		err = yaml.Unmarshal(content, &data)
+	default:
+		return nil, usageError{msg: fmt.Sprintf("unknown input format %q", format)}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s config data: %w", format, err)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("config data must be an object")
+	}
+
+	return normalize(data), nil
+}
+
+func detectFormat(file string, content []byte) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+
+	if json.Valid(content) {
+		return "json"
+	}
+	return "yaml"
+}
+
+// normalize round-trips data through JSON so documents read from YAML and
+// documents returned by the service use the same Go types.
+func normalize(data map[string]interface{}) map[string]interface{} {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(content, &normalized); err != nil {
+		return data
+	}
+	return normalized
+}
@@ -0,0 +1,197 @@
+// Command configctl manages configs and schemas of the configuration service
+// from the terminal and CI pipelines.
+package main
+
+import (
+	"config-service/client"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Exit codes returned by configctl.
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitValidation  = 3
+	exitConflict    = 4
+	exitNotFound    = 5
+	exitUnavailable = 6
+	exitDiff        = 7
+	exitLocked      = 8
+)
+
+const usage = `Usage: configctl [global flags] <command> [flags] [args]
+
+Commands:
+  get <schema> <name>            Print a config (-version N, -layers, -resolve=false, -reveal)
+  fetch <schema>/<name>[@N|@label]...
+                                 Print many configs fetched in one request
+  put <schema> <name>            Create or update a config from -f FILE or stdin (-draft -m COMMENT to propose it)
+  apply                          Create or update several configs at once from the items of -f FILE or stdin
+  diff <schema> <name>           Compare two versions, or a file against the latest
+  rollback <schema> <name> <N>   Roll a config back to version N
+  cancel <schema> <name> <N>     Cancel version N scheduled with put -at
+  promote <schema> <name>        Copy a version (-version N or -label) -from one environment -to another (-dry-run to diff)
+  list <schema> <name>           List all versions of a config
+  schemas [file]                 List schemas, or print one schema file
+  envs [<schema> <name>]         List environments, or the latest version of a config in each
+  referrers <schema> <name>      List the configs referencing a config
+  vars [set <name> <value>|delete <name>]
+                                 List, set or delete the variables of the environment
+  drafts [show|approve|reject <id>]
+                                 List pending drafts (-status), show or review one (-m COMMENT)
+  labels <schema> <name> [set <label> <N>|delete <label>]
+                                 List, set or delete the labels naming versions of a config
+
+Global flags:
+`
+
+// usageError signals wrong command line usage.
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// errDiffFound signals that diff found differences, like diff(1) exiting with 1.
+var errDiffFound = errors.New("differences found")
+
+type cli struct {
+	client *client.Client
+	output string
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("configctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	server := flags.String("server", envOrDefault("CONFIGCTL_SERVER", "http://localhost:3000"), "config service URL (env CONFIGCTL_SERVER)")
+	output := flags.String("o", "table", "output format: table or json")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of each request")
+	apiKey := flags.String("api-key", os.Getenv("CONFIGCTL_API_KEY"), "API key sent with every request (env CONFIGCTL_API_KEY)")
+	token := flags.String("token", os.Getenv("CONFIGCTL_TOKEN"), "bearer token sent with every request (env CONFIGCTL_TOKEN)")
+	tenant := flags.String("tenant", os.Getenv("CONFIGCTL_TENANT"), "tenant to operate on, defaults to the tenant of the credentials (env CONFIGCTL_TENANT)")
+	env := flags.String("env", os.Getenv("CONFIGCTL_ENV"), "environment to operate on, e.g. prod (env CONFIGCTL_ENV)")
+	strict := flags.Bool("strict", false, "fail to fetch configs using undefined variables")
+	override := flags.String("override", "", "reason for changing configs despite active freezes, requires the approver role")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "configctl: unknown output format %q\n", *output)
+		return exitUsage
+	}
+
+	options := []client.Option{client.WithTimeout(*timeout), client.WithAPIKey(*apiKey), client.WithBearerToken(*token), client.WithTenant(*tenant), client.WithEnvironment(*env)}
+	if *strict {
+		options = append(options, client.WithStrictVariables())
+	}
+	if *override != "" {
+		options = append(options, client.WithFreezeOverride(*override))
+	}
+
+	c := &cli{
+		client: client.New(*server, options...),
+		output: *output,
+		stdin:  stdin,
+		stdout: stdout,
+	}
+
+	err := c.dispatch(context.Background(), flags.Arg(0), flags.Args()[1:])
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, errDiffFound) {
+		return exitDiff
+	}
+
+	fmt.Fprintf(stderr, "configctl: %v\n", err)
+	return exitCode(err)
+}
+
+func (c *cli) dispatch(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "get":
+		return c.get(ctx, args)
+	case "fetch":
+		return c.fetch(ctx, args)
+	case "put":
+		return c.put(ctx, args)
+	case "apply":
+		return c.apply(ctx, args)
+	case "diff":
+		return c.diff(ctx, args)
+	case "cancel":
+		return c.cancel(ctx, args)
+	case "rollback":
+		return c.rollback(ctx, args)
+	case "promote":
+		return c.promote(ctx, args)
+	case "list":
+		return c.list(ctx, args)
+	case "schemas":
+		return c.schemas(ctx, args)
+	case "envs":
+		return c.envs(ctx, args)
+	case "referrers":
+		return c.referrers(ctx, args)
+	case "vars":
+		return c.vars(ctx, args)
+	case "drafts":
+		return c.drafts(ctx, args)
+	case "labels":
+		return c.labels(ctx, args)
+	default:
+		return usageError{msg: fmt.Sprintf("unknown command %q", command)}
+	}
+}
+
+func exitCode(err error) int {
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case client.IsValidationError(err):
+		return exitValidation
+	case client.IsConflict(err):
+		return exitConflict
+	case client.IsNotFound(err):
+		return exitNotFound
+	case client.IsLocked(err):
+		return exitLocked
+	case client.IsUnavailable(err):
+		return exitUnavailable
+	default:
+		return exitError
+	}
+}
+
+func envOrDefault(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"config-service/diff"
+	"config-service/model/web"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	content, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(content)
+}
+
+func (c *cli) printConfig(config web.ConfigResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, config)
+	}
+
+	fmt.Fprintf(c.stdout, "%s/%s version %d", config.Schema, config.Name, config.Version)
+	if !config.CreatedAt.IsZero() {
+		fmt.Fprintf(c.stdout, " (created %s)", config.CreatedAt.Format(time.RFC3339))
+	}
+	if activation := activation(config); activation != "" {
+		fmt.Fprintf(c.stdout, ", %s", activation)
+	}
+	fmt.Fprint(c.stdout, "\n\n")
+
+	values := map[string]interface{}{}
+	diff.Flatten("", config.Data, values)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tVALUE")
+	for _, key := range keys {
+		fmt.Fprintf(table, "%s\t%s\n", key, formatValue(values[key]))
+	}
+
+	if len(config.Layers) > 0 {
+		fmt.Fprintln(table, "\nLAYER\tVERSION\tDATA")
+		for _, layer := range config.Layers {
+			fmt.Fprintf(table, "%s\t%d\t%s\n", layer.Name, layer.Version, formatValue(layer.Data))
+		}
+	}
+	return table.Flush()
+}
+
+func (c *cli) printVersions(configs web.ConfigResponses) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, configs)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "VERSION\tCREATED\tACTIVATION\tDATA")
+	for _, config := range configs.ConfigVersions {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", config.Version, config.CreatedAt.Format(time.RFC3339), activation(config), formatValue(config.Data))
+	}
+	return table.Flush()
+}
+
+// activation describes when a scheduled version becomes active, empty for
+// versions active once created.
+func activation(config web.ConfigResponse) string {
+	switch {
+	case config.CancelledAt != nil:
+		return "cancelled " + config.CancelledAt.Format(time.RFC3339)
+	case config.EffectiveAt != nil:
+		return "effective " + config.EffectiveAt.Format(time.RFC3339)
+	}
+	return ""
+}
+
+func (c *cli) printSchemas(schemas []web.SchemaResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, schemas)
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tFILE")
+	for _, schema := range schemas {
+		fmt.Fprintf(table, "%s\t%s\n", schema.Name, schema.Path)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printEnvironments(environments []web.EnvironmentResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, environments)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ENVIRONMENT\tCONFIGS")
+	for _, environment := range environments {
+		fmt.Fprintf(table, "%s\t%d\n", environment.Name, environment.Configs)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printConfigEnvironments(configs []web.ConfigResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, configs)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ENVIRONMENT\tVERSION\tCREATED\tDATA")
+	for _, config := range configs {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", config.Environment, config.Version, config.CreatedAt.Format(time.RFC3339), formatValue(config.Data))
+	}
+	return table.Flush()
+}
+
+func (c *cli) printBatch(batch web.ConfigBatchResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, batch)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "SCHEMA\tNAME\tVERSION\tCREATED")
+	for _, config := range batch.Configs {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\n", config.Schema, config.Name, config.Version, config.CreatedAt.Format(time.RFC3339))
+	}
+	return table.Flush()
+}
+
+func (c *cli) printBulk(bulk web.ConfigBulkResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, bulk)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "SELECTOR\tVERSION\tDATA")
+	for _, result := range bulk.Results {
+		if result.Config == nil {
+			fmt.Fprintf(table, "%s\t-\terror %d: %s\n", result.Selector, result.Code, result.Error)
+			continue
+		}
+		fmt.Fprintf(table, "%s\t%d\t%s\n", result.Selector, result.Config.Version, formatValue(result.Config.Data))
+	}
+	return table.Flush()
+}
+
+func (c *cli) printReferrers(referrers []web.ConfigReferrerResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, referrers)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "SCHEMA\tNAME\tVERSION\tPATH\tREF")
+	for _, referrer := range referrers {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", referrer.Schema, referrer.Name, referrer.Version, referrer.Path, referrer.Ref)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printLabels(labels []web.ConfigLabelResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, labels)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "LABEL\tVERSION\tUPDATED\tBY")
+	for _, label := range labels {
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\n", label.Label, label.Version, label.UpdatedAt.Format(time.RFC3339), label.UpdatedBy)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printVariables(variables []web.VariableResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, variables)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tVALUE\tUPDATED\tBY")
+	for _, variable := range variables {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", variable.Name, formatValue(variable.Value), variable.UpdatedAt.Format(time.RFC3339), variable.UpdatedBy)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printChanges(changes []diff.Change) error {
+	if c.output == "json" {
+		if changes == nil {
+			changes = []diff.Change{}
+		}
+		return printJSON(c.stdout, changes)
+	}
+
+	for _, ch := range changes {
+		switch ch.Op {
+		case "add":
+			fmt.Fprintf(c.stdout, "+ %s: %s\n", ch.Path, formatValue(ch.New))
+		case "remove":
+			fmt.Fprintf(c.stdout, "- %s: %s\n", ch.Path, formatValue(ch.Old))
+		default:
+			fmt.Fprintf(c.stdout, "~ %s: %s -> %s\n", ch.Path, formatValue(ch.Old), formatValue(ch.New))
+		}
+	}
+	return nil
+}
+
+func (c *cli) printDrafts(drafts []web.DraftResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, drafts)
+	}
+
+	table := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tCONFIG\tBASE\tSTATUS\tAPPROVALS\tBY\tCOMMENT")
+	for _, draft := range drafts {
+		fmt.Fprintf(table, "%d\t%s/%s\t%d\t%s\t%d/%d\t%s\t%s\n", draft.ID, draft.Schema, draft.Name, draft.BaseVersion, draft.Status,
+			draft.Approvals, draft.RequiredApprovals, draft.CreatedBy, draft.Comment)
+	}
+	return table.Flush()
+}
+
+func (c *cli) printDraft(draft web.DraftResponse) error {
+	if c.output == "json" {
+		return printJSON(c.stdout, draft)
+	}
+
+	fmt.Fprintf(c.stdout, "draft %d of %s/%s on version %d: %s, %d of %d approval(s)\n", draft.ID, draft.Schema, draft.Name,
+		draft.BaseVersion, draft.Status, draft.Approvals, draft.RequiredApprovals)
+	if draft.PublishedVersion > 0 {
+		fmt.Fprintf(c.stdout, "published as version %d\n", draft.PublishedVersion)
+	}
+	if draft.Comment != "" {
+		fmt.Fprintf(c.stdout, "%s\n", draft.Comment)
+	}
+	for _, review := range draft.Reviews {
+		fmt.Fprintf(c.stdout, "  %s by %s: %s\n", review.Decision, review.Reviewer, review.Comment)
+	}
+	if len(draft.Changes) > 0 {
+		fmt.Fprintln(c.stdout)
+		return c.printChanges(draft.Changes)
+	}
+	return nil
+}
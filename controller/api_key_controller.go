@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type APIKeyController interface {
+	CreateKey(ctx *gin.Context)
+	ListKeys(ctx *gin.Context)
+	RevokeKey(ctx *gin.Context)
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyControllerImpl struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyController(apiKeyService service.APIKeyService) APIKeyController {
+	return &APIKeyControllerImpl{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateKey godoc
+// @Summary Create an API key
+// @Description The key is only returned by this call, the service stores its hash
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param request body web.APIKeyCreateRequest true "Key name and admin flag"
+// @Success 201 {object} web.APIKeyCreateResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Router /admin/api-keys [post]
+func (c *APIKeyControllerImpl) CreateKey(ctx *gin.Context) {
+	var req web.APIKeyCreateRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+
+	result := c.apiKeyService.CreateKey(ctx.Request.Context(), req)
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// ListKeys godoc
+// @Summary List API keys
+// @Description Lists every key, including revoked ones, with its last use
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} web.APIKeyResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/api-keys [get]
+func (c *APIKeyControllerImpl) ListKeys(ctx *gin.Context) {
+	result := c.apiKeyService.ListKeys(ctx.Request.Context())
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RevokeKey godoc
+// @Summary Revoke an API key
+// @Tags admin
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Key ID"
+// @Success 204
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Router /admin/api-keys/{id} [delete]
+func (c *APIKeyControllerImpl) RevokeKey(ctx *gin.Context) {
+	c.apiKeyService.RevokeKey(ctx.Request.Context(), ctx.Param("id"))
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type AuditController interface {
+	ListEvents(ctx *gin.Context)
+	Verify(ctx *gin.Context)
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditControllerImpl struct {
+	auditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &AuditControllerImpl{
+		auditService: auditService,
+	}
+}
+
+func queryTime(ctx *gin.Context, key string) time.Time {
+	value := ctx.Query(key)
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: key + " must be an RFC 3339 timestamp"})
+	}
+	return parsed
+}
+
+func queryInt(ctx *gin.Context, key string) int64 {
+	value := ctx.Query(key)
+	if value == "" {
+		return 0
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed < 0 {
+		helper.PanicIfError(helper.ValidationError{Msg: key + " must be a positive integer"})
+	}
+	return parsed
+}
+
+// ListEvents godoc
+// @Summary List audit events
+// @Description Lists audit events newest first. Pass the smallest returned id as before_id to fetch the next page.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param actor query string false "Principal that caused the event"
+// @Param action query string false "Action, e.g. config.updated or request.denied"
+// @Param outcome query string false "success or denied"
+// @Param tenant query string false "Tenant"
+// @Param environment query string false "Environment"
+// @Param schema query string false "Schema name"
+// @Param name query string false "Config name"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Param before_id query int false "Only events with a smaller id"
+// @Param limit query int false "Maximum number of events, 100 by default"
+// @Success 200 {array} web.AuditEventResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/audit [get]
+func (c *AuditControllerImpl) ListEvents(ctx *gin.Context) {
+	filter := domain.AuditFilter{
+		Actor:       ctx.Query("actor"),
+		Action:      ctx.Query("action"),
+		Outcome:     ctx.Query("outcome"),
+		Tenant:      ctx.Query("tenant"),
+		Environment: ctx.Query("environment"),
+		Schema:      ctx.Query("schema"),
+		Name:        ctx.Query("name"),
+		Since:       queryTime(ctx, "since"),
+		Until:       queryTime(ctx, "until"),
+		BeforeID:    queryInt(ctx, "before_id"),
+		Limit:       int(queryInt(ctx, "limit")),
+	}
+
+	result := c.auditService.ListEvents(ctx.Request.Context(), filter)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Verify godoc
+// @Summary Verify the audit log
+// @Description Recomputes the hash chain of the audit log and reports the first entry that was changed or whose predecessor was removed
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} web.AuditVerifyResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/audit/verify [get]
+func (c *AuditControllerImpl) Verify(ctx *gin.Context) {
+	result := c.auditService.Verify(ctx.Request.Context())
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -7,7 +7,17 @@ import (
 type ConfigController interface {
 	CreateConfig(ctx *gin.Context)
 	UpdateConfig(ctx *gin.Context)
+	BatchUpdateConfigs(ctx *gin.Context)
 	RollbackConfig(ctx *gin.Context)
+	CancelConfig(ctx *gin.Context)
+	PromoteConfig(ctx *gin.Context)
 	FetchConfig(ctx *gin.Context)
+	BulkFetchConfigs(ctx *gin.Context)
 	ListVersions(ctx *gin.Context)
+	ListEnvironments(ctx *gin.Context)
+	ListConfigEnvironments(ctx *gin.Context)
+	ListReferrers(ctx *gin.Context)
+	ListLabels(ctx *gin.Context)
+	SetLabel(ctx *gin.Context)
+	DeleteLabel(ctx *gin.Context)
 }
@@ -2,9 +2,12 @@ package controller
 
 import (
 	"config-service/helper"
+	"config-service/metrics"
 	"config-service/model/web"
 	"config-service/service"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,11 +28,19 @@ func NewConfigController(configService service.ConfigService) ConfigController {
 // @Tags configs
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
 // @Param schema path string true "Schema name"
 // @Param name path string true "Configuration name"
+// @Param effective_at query string false "RFC 3339 time the version becomes active, now when omitted"
 // @Param request body web.ConfigCreateRequest true "Config data"
 // @Success 201 {object} web.ConfigResponse
 // @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
 // @Router /configs/{schema}/{name} [post]
 func (c *ConfigControllerImpl) CreateConfig(ctx *gin.Context) {
 	schema := ctx.Param("schema")
@@ -40,7 +51,8 @@ func (c *ConfigControllerImpl) CreateConfig(ctx *gin.Context) {
 	helper.PanicIfError(err)
 
 	req := web.ConfigCreateRequest{
-		Data: rawData,
+		Data:        rawData,
+		EffectiveAt: effectiveAt(ctx),
 	}
 
 	result := c.configService.CreateConfig(ctx.Request.Context(), schema, name, req)
@@ -54,12 +66,20 @@ func (c *ConfigControllerImpl) CreateConfig(ctx *gin.Context) {
 // @Tags configs
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
 // @Param schema path string true "Schema name"
 // @Param name path string true "Configuration name"
+// @Param effective_at query string false "RFC 3339 time the version becomes active, now when omitted"
+// @Param override query string false "Reason for changing the config despite active freezes, requires the approver role"
 // @Param request body web.ConfigUpdateRequest true "Config data"
 // @Success 200 {object} web.ConfigResponse
 // @Failure 400 {object} web.WebResponse
 // @Failure 404 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
 // @Router /configs/{schema}/{name} [put]
 func (c *ConfigControllerImpl) UpdateConfig(ctx *gin.Context) {
 	schema := ctx.Param("schema")
@@ -70,7 +90,9 @@ func (c *ConfigControllerImpl) UpdateConfig(ctx *gin.Context) {
 	helper.PanicIfError(err)
 
 	req := web.ConfigUpdateRequest{
-		Data: rawData,
+		Data:        rawData,
+		EffectiveAt: effectiveAt(ctx),
+		Override:    ctx.Query("override"),
 	}
 
 	result := c.configService.UpdateConfig(ctx.Request.Context(), schema, name, req)
@@ -78,16 +100,53 @@ func (c *ConfigControllerImpl) UpdateConfig(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+// BatchUpdateConfigs godoc
+// @Summary Update several configurations atomically
+// @Description Store a new version of every item in one transaction, creating the configurations that do not exist yet. Nothing is stored when any item fails, the error naming the item.
+// @Tags configs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param override query string false "Reason for changing the configs despite active freezes, requires the approver role"
+// @Param request body web.ConfigBatchRequest true "Items"
+// @Success 200 {object} web.ConfigBatchResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /configs [post]
+func (c *ConfigControllerImpl) BatchUpdateConfigs(ctx *gin.Context) {
+	var req web.ConfigBatchRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+	req.Override = ctx.Query("override")
+
+	result := c.configService.BatchUpdateConfigs(ctx.Request.Context(), req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 // RollbackConfig godoc
 // @Summary Rollback configuration to previous version
 // @Tags configs
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
 // @Param schema path string true "Schema name"
 // @Param name path string true "Configuration name"
+// @Param override query string false "Reason for changing the config despite active freezes, requires the approver role"
 // @Param request body web.ConfigRollbackRequest true "Config data"
 // @Success 200 {object} web.ConfigResponse
 // @Failure 500 {object} web.WebResponse
 // @Failure 404 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
 // @Router /configs/{schema}/{name}/rollback [post]
 func (c *ConfigControllerImpl) RollbackConfig(ctx *gin.Context) {
 	schema := ctx.Param("schema")
@@ -104,7 +163,8 @@ func (c *ConfigControllerImpl) RollbackConfig(ctx *gin.Context) {
 	}
 
 	req := web.ConfigRollbackRequest{
-		Version: version,
+		Version:  version,
+		Override: ctx.Query("override"),
 	}
 
 	result := c.configService.RollbackConfig(ctx.Request.Context(), schema, name, req)
@@ -112,15 +172,93 @@ func (c *ConfigControllerImpl) RollbackConfig(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, result)
 }
 
+// CancelConfig godoc
+// @Summary Cancel a scheduled configuration version
+// @Description Cancels a version scheduled with effective_at before it becomes active. Cancelled versions stay in the history but are never served.
+// @Tags configs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param override query string false "Reason for changing the config despite active freezes, requires the approver role"
+// @Param request body web.ConfigCancelRequest true "Scheduled version"
+// @Success 200 {object} web.ConfigResponse
+// @Failure 404 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/cancel [post]
+func (c *ConfigControllerImpl) CancelConfig(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	var req web.ConfigCancelRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+	req.Override = ctx.Query("override")
+
+	result := c.configService.CancelConfig(ctx.Request.Context(), schema, name, req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// PromoteConfig godoc
+// @Summary Promote configuration to another environment
+// @Description Copies a version of the configuration from one environment to another as a new version, recording the source in its metadata. Dry runs only report the changes.
+// @Tags configs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param override query string false "Reason for changing the target despite active freezes, requires the approver role"
+// @Param request body web.ConfigPromoteRequest true "Source and target"
+// @Success 200 {object} web.ConfigPromoteResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/promote [post]
+func (c *ConfigControllerImpl) PromoteConfig(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	var req web.ConfigPromoteRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+	req.Override = ctx.Query("override")
+
+	result := c.configService.PromoteConfig(ctx.Request.Context(), schema, name, req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 // FetchConfig godoc
 // @Summary Fetch configuration
 // @Tags configs
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
 // @Param schema path string true "Schema name"
 // @Param name path string true "Configuration name"
+// @Param version query int false "Config version, latest when omitted"
+// @Param layers query bool false "Add the unmerged layers of an inheriting config"
+// @Param resolve query bool false "Resolve references to other configs and render placeholders, true when omitted"
+// @Param strict query bool false "Fail with 409 when placeholders use undefined variables"
+// @Param reveal query bool false "Return secret values unmasked, requires the approver role"
 // @Param request body web.ConfigFetchRequest false "Config data"
 // @Success 200 {object} web.ConfigResponse
+// @Success 304 "Not modified when If-None-Match matches the ETag"
 // @Failure 404 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
 // @Router /configs/{schema}/{name} [get]
 func (c *ConfigControllerImpl) FetchConfig(ctx *gin.Context) {
 	schema := ctx.Param("schema")
@@ -128,8 +266,14 @@ func (c *ConfigControllerImpl) FetchConfig(ctx *gin.Context) {
 
 	var version int
 
-	// Check if request has a body
-	if ctx.Request.ContentLength > 0 {
+	// Version can be passed as query parameter or in the request body
+	if query := ctx.Query("version"); query != "" {
+		v, err := strconv.Atoi(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "version must be an integer"})
+		}
+		version = v
+	} else if ctx.Request.ContentLength > 0 {
 		var req web.ConfigFetchRequest
 		err := ctx.ShouldBindJSON(&req)
 		helper.PanicIfError(err)
@@ -147,8 +291,92 @@ func (c *ConfigControllerImpl) FetchConfig(ctx *gin.Context) {
 		Version: &version,
 	}
 
+	if query := ctx.Query("resolve"); query != "" {
+		resolve, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "resolve must be a boolean"})
+		}
+		req.Resolve = &resolve
+	}
+
+	if query := ctx.Query("layers"); query != "" {
+		layers, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "layers must be a boolean"})
+		}
+		req.Layers = layers
+	}
+
+	if query := ctx.Query("strict"); query != "" {
+		strict, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "strict must be a boolean"})
+		}
+		req.Strict = strict
+	}
+
+	if query := ctx.Query("reveal"); query != "" {
+		reveal, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "reveal must be a boolean"})
+		}
+		req.Reveal = reveal
+	}
+
 	result := c.configService.FetchConfig(ctx.Request.Context(), schema, name, req)
 
+	// The tag covers the merged data, which changes with the parents of a config
+	etag := helper.ConfigETag(result)
+	ctx.Header("ETag", etag)
+	if ctx.GetHeader("If-None-Match") == etag {
+		metrics.CacheRequests.WithLabelValues("hit").Inc()
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	metrics.CacheRequests.WithLabelValues("miss").Inc()
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// BulkFetchConfigs godoc
+// @Summary Fetch many configurations
+// @Description Fetch the configurations of all selectors from one consistent snapshot. Selectors failing to fetch get the error and status code of a single fetch in their result.
+// @Tags configs
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param select query []string true "schema/name for the latest version, schema/name@N for version N, schema/name@label for the version named by label" collectionFormat(multi)
+// @Param resolve query bool false "Resolve references to other configs and render placeholders, true when omitted"
+// @Param strict query bool false "Fail configs whose placeholders use undefined variables"
+// @Success 200 {object} web.ConfigBulkResponse
+// @Failure 400 {object} web.WebResponse
+// @Router /configs [get]
+func (c *ConfigControllerImpl) BulkFetchConfigs(ctx *gin.Context) {
+	req := web.ConfigBulkFetchRequest{
+		Selectors: ctx.QueryArray("select"),
+	}
+
+	if query := ctx.Query("resolve"); query != "" {
+		resolve, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "resolve must be a boolean"})
+		}
+		req.Resolve = &resolve
+	}
+
+	if query := ctx.Query("strict"); query != "" {
+		strict, err := strconv.ParseBool(query)
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: "strict must be a boolean"})
+		}
+		req.Strict = strict
+	}
+
+	result := c.configService.BulkFetchConfigs(ctx.Request.Context(), req)
+
 	ctx.JSON(http.StatusOK, result)
 }
 
@@ -156,6 +384,11 @@ func (c *ConfigControllerImpl) FetchConfig(ctx *gin.Context) {
 // @Summary List configuration versions
 // @Tags configs
 // @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
 // @Param schema path string true "Schema name"
 // @Param name path string true "Configuration name"
 // @Success 200 {array} web.ConfigResponses
@@ -169,3 +402,162 @@ func (c *ConfigControllerImpl) ListVersions(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, result)
 }
+
+// ListEnvironments godoc
+// @Summary List environments
+// @Description Lists the configured environments, and those still holding configs, with their number of configs
+// @Tags configs
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Success 200 {array} web.EnvironmentResponse
+// @Failure 401 {object} web.WebResponse
+// @Router /environments [get]
+func (c *ConfigControllerImpl) ListEnvironments(ctx *gin.Context) {
+	result := c.configService.ListEnvironments(ctx.Request.Context())
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListConfigEnvironments godoc
+// @Summary List the environments of a configuration
+// @Description Returns the latest version of the configuration in every environment having one
+// @Tags configs
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Success 200 {array} web.ConfigResponse
+// @Failure 400 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/environments [get]
+func (c *ConfigControllerImpl) ListConfigEnvironments(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	result := c.configService.ListConfigEnvironments(ctx.Request.Context(), schema, name)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListReferrers godoc
+// @Summary List the configurations referencing a configuration
+// @Description Returns every $configRef of the latest configuration versions to the configuration
+// @Tags configs
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Success 200 {array} web.ConfigReferrerResponse
+// @Failure 400 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/referrers [get]
+func (c *ConfigControllerImpl) ListReferrers(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	result := c.configService.ListReferrers(ctx.Request.Context(), schema, name)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListLabels godoc
+// @Summary List the labels of a configuration
+// @Description Returns the labels naming versions of the configuration, which fetches and promotions can select them by
+// @Tags configs
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Success 200 {array} web.ConfigLabelResponse
+// @Failure 400 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/labels [get]
+func (c *ConfigControllerImpl) ListLabels(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	result := c.configService.ListLabels(ctx.Request.Context(), schema, name)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// SetLabel godoc
+// @Summary Label a configuration version
+// @Description Names a version of the configuration by the label, moving the label when it named another version
+// @Tags configs
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param label path string true "Label, a lowercase letter followed by lowercase letters, digits, '.', '_' or '-'"
+// @Param override query string false "Reason for moving the label despite active freezes, requires the approver role"
+// @Param request body web.ConfigLabelRequest true "Labeled version"
+// @Success 200 {object} web.ConfigLabelResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/labels/{label} [put]
+func (c *ConfigControllerImpl) SetLabel(ctx *gin.Context) {
+	schema := ctx.Param("schema")
+	name := ctx.Param("name")
+
+	var req web.ConfigLabelRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+	req.Override = ctx.Query("override")
+
+	result := c.configService.SetLabel(ctx.Request.Context(), schema, name, ctx.Param("label"), req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteLabel godoc
+// @Summary Delete a configuration label
+// @Tags configs
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param label path string true "Label"
+// @Param override query string false "Reason for deleting the label despite active freezes, requires the approver role"
+// @Success 204
+// @Failure 404 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/labels/{label} [delete]
+func (c *ConfigControllerImpl) DeleteLabel(ctx *gin.Context) {
+	c.configService.DeleteLabel(ctx.Request.Context(), ctx.Param("schema"), ctx.Param("name"), ctx.Param("label"), ctx.Query("override"))
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// effectiveAt parses the optional effective_at query parameter.
+func effectiveAt(ctx *gin.Context) *time.Time {
+	value := ctx.Query("effective_at")
+	if value == "" {
+		return nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: "effective_at must be an RFC 3339 time, e.g. 2024-01-02T02:00:00Z"})
+	}
+	return &at
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/service"
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ConfigGRPCController struct {
+	configpb.UnimplementedConfigServiceServer
+	configService service.ConfigService
+}
+
+func NewConfigGRPCController(configService service.ConfigService) configpb.ConfigServiceServer {
+	return &ConfigGRPCController{
+		configService: configService,
+	}
+}
+
+func (c *ConfigGRPCController) CreateConfig(ctx context.Context, request *configpb.CreateConfigRequest) (*configpb.Config, error) {
+	req := web.ConfigCreateRequest{
+		Data:        request.GetData().AsMap(),
+		EffectiveAt: helper.FromProtoTime(request.GetEffectiveAt()),
+	}
+
+	result := c.configService.CreateConfig(ctx, request.GetSchema(), request.GetName(), req)
+
+	return helper.ToProtoConfig(result), nil
+}
+
+func (c *ConfigGRPCController) UpdateConfig(ctx context.Context, request *configpb.UpdateConfigRequest) (*configpb.Config, error) {
+	req := web.ConfigUpdateRequest{
+		Data:        request.GetData().AsMap(),
+		EffectiveAt: helper.FromProtoTime(request.GetEffectiveAt()),
+		Override:    request.GetOverride(),
+	}
+
+	result := c.configService.UpdateConfig(ctx, request.GetSchema(), request.GetName(), req)
+
+	return helper.ToProtoConfig(result), nil
+}
+
+func (c *ConfigGRPCController) RollbackConfig(ctx context.Context, request *configpb.RollbackConfigRequest) (*configpb.Config, error) {
+	req := web.ConfigRollbackRequest{
+		Version:  int(request.GetVersion()),
+		Override: request.GetOverride(),
+	}
+
+	result := c.configService.RollbackConfig(ctx, request.GetSchema(), request.GetName(), req)
+
+	return helper.ToProtoConfig(result), nil
+}
+
+func (c *ConfigGRPCController) CancelConfig(ctx context.Context, request *configpb.CancelConfigRequest) (*configpb.Config, error) {
+	req := web.ConfigCancelRequest{
+		Version:  int(request.GetVersion()),
+		Override: request.GetOverride(),
+	}
+
+	result := c.configService.CancelConfig(ctx, request.GetSchema(), request.GetName(), req)
+
+	return helper.ToProtoConfig(result), nil
+}
+
+func (c *ConfigGRPCController) FetchConfig(ctx context.Context, request *configpb.FetchConfigRequest) (*configpb.Config, error) {
+	version := int(request.GetVersion())
+	req := web.ConfigFetchRequest{
+		Version: &version,
+		Layers:  request.GetLayers(),
+		Resolve: request.Resolve,
+		Strict:  request.GetStrict(),
+		Reveal:  request.GetReveal(),
+	}
+
+	result := c.configService.FetchConfig(ctx, request.GetSchema(), request.GetName(), req)
+
+	return helper.ToProtoConfig(result), nil
+}
+
+func (c *ConfigGRPCController) ListVersions(ctx context.Context, request *configpb.ListVersionsRequest) (*configpb.ConfigVersions, error) {
+	result := c.configService.ListVersions(ctx, request.GetSchema(), request.GetName())
+
+	return helper.ToProtoConfigVersions(result), nil
+}
+
+func (c *ConfigGRPCController) Watch(request *configpb.WatchRequest, stream configpb.ConfigService_WatchServer) error {
+	updates, watchErr := c.configService.Watch(stream.Context(), request.GetSchema(), request.GetName())
+
+	for update := range updates {
+		err := stream.Send(helper.ToProtoConfig(update))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Clients falling behind are told to watch again instead of missing
+	// versions
+	if err := watchErr(); err != nil {
+		return status.Error(codes.Aborted, err.Error())
+	}
+
+	return stream.Context().Err()
+}
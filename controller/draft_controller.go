@@ -0,0 +1,12 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type DraftController interface {
+	ProposeConfig(ctx *gin.Context)
+	ListConfigDrafts(ctx *gin.Context)
+	ListDrafts(ctx *gin.Context)
+	GetDraft(ctx *gin.Context)
+	ApproveDraft(ctx *gin.Context)
+	RejectDraft(ctx *gin.Context)
+}
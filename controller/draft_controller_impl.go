@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DraftControllerImpl serves the drafts of the configs of a ConfigService.
+type DraftControllerImpl struct {
+	configService service.ConfigService
+}
+
+func NewDraftController(configService service.ConfigService) DraftController {
+	return &DraftControllerImpl{
+		configService: configService,
+	}
+}
+
+func draftID(ctx *gin.Context) int64 {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: "id must be an integer"})
+	}
+	return id
+}
+
+// ProposeConfig godoc
+// @Summary Propose a new configuration version
+// @Description Stores a draft of the next version, validated like an update, that is published once approved
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param request body web.DraftCreateRequest true "Proposed config data and comment"
+// @Success 201 {object} web.DraftResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/drafts [post]
+func (c *DraftControllerImpl) ProposeConfig(ctx *gin.Context) {
+	var req web.DraftCreateRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+
+	result := c.configService.ProposeConfig(ctx.Request.Context(), ctx.Param("schema"), ctx.Param("name"), req)
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// ListConfigDrafts godoc
+// @Summary List the drafts of a configuration
+// @Tags drafts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param schema path string true "Schema name"
+// @Param name path string true "Configuration name"
+// @Param status query string false "pending (default), published or rejected"
+// @Success 200 {array} web.DraftResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /configs/{schema}/{name}/drafts [get]
+func (c *DraftControllerImpl) ListConfigDrafts(ctx *gin.Context) {
+	result := c.configService.ListDrafts(ctx.Request.Context(), web.DraftListRequest{
+		Schema: ctx.Param("schema"),
+		Name:   ctx.Param("name"),
+		Status: ctx.Query("status"),
+	})
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ListDrafts godoc
+// @Summary List the drafts of an environment
+// @Description Lists the drafts of the configurations the caller can read, the pending ones by default
+// @Tags drafts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param status query string false "pending (default), published or rejected"
+// @Success 200 {array} web.DraftResponse
+// @Failure 400 {object} web.WebResponse
+// @Router /drafts [get]
+func (c *DraftControllerImpl) ListDrafts(ctx *gin.Context) {
+	result := c.configService.ListDrafts(ctx.Request.Context(), web.DraftListRequest{
+		Status: ctx.Query("status"),
+	})
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetDraft godoc
+// @Summary Get a draft
+// @Description Returns a draft with its reviews and its changes to the version it was proposed on
+// @Tags drafts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param id path int true "Draft ID"
+// @Success 200 {object} web.DraftResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Router /drafts/{id} [get]
+func (c *DraftControllerImpl) GetDraft(ctx *gin.Context) {
+	result := c.configService.GetDraft(ctx.Request.Context(), draftID(ctx))
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ApproveDraft godoc
+// @Summary Approve a draft
+// @Description Records the approval of the caller, the draft is published as a new version once it has the approvals its schema requires
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param id path int true "Draft ID"
+// @Param override query string false "Reason for publishing the draft despite active freezes"
+// @Param request body web.DraftReviewRequest false "Review comment"
+// @Success 200 {object} web.DraftResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Failure 423 {object} web.WebResponse
+// @Router /drafts/{id}/approve [post]
+func (c *DraftControllerImpl) ApproveDraft(ctx *gin.Context) {
+	req := bindReview(ctx)
+	req.Override = ctx.Query("override")
+
+	result := c.configService.ApproveDraft(ctx.Request.Context(), draftID(ctx), req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RejectDraft godoc
+// @Summary Reject a draft
+// @Tags drafts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param id path int true "Draft ID"
+// @Param request body web.DraftReviewRequest true "Reason of the rejection"
+// @Success 200 {object} web.DraftResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Router /drafts/{id}/reject [post]
+func (c *DraftControllerImpl) RejectDraft(ctx *gin.Context) {
+	result := c.configService.RejectDraft(ctx.Request.Context(), draftID(ctx), bindReview(ctx))
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// bindReview binds the optional body of a review.
+func bindReview(ctx *gin.Context) web.DraftReviewRequest {
+	var req web.DraftReviewRequest
+	if ctx.Request.ContentLength > 0 {
+		err := ctx.ShouldBindJSON(&req)
+		helper.PanicIfError(err)
+	}
+	return req
+}
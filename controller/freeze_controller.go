@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type FreezeController interface {
+	CreateFreeze(ctx *gin.Context)
+	ListFreezes(ctx *gin.Context)
+	DeleteFreeze(ctx *gin.Context)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FreezeControllerImpl struct {
+	freezeService service.FreezeService
+}
+
+func NewFreezeController(freezeService service.FreezeService) FreezeController {
+	return &FreezeControllerImpl{
+		freezeService: freezeService,
+	}
+}
+
+// CreateFreeze godoc
+// @Summary Create a freeze
+// @Description Locks the configs matching tenant, environment, schema and name globs against updates, rollbacks, cancellations, promotions and draft publications, until the freeze is deleted or during a weekly window. Changes overriding it need a reason and the approver role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param request body web.FreezeCreateRequest true "Scope, reason and window"
+// @Success 201 {object} web.FreezeResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/freezes [post]
+func (c *FreezeControllerImpl) CreateFreeze(ctx *gin.Context) {
+	var req web.FreezeCreateRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+
+	result := c.freezeService.CreateFreeze(ctx.Request.Context(), req)
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// ListFreezes godoc
+// @Summary List freezes
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} web.FreezeResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/freezes [get]
+func (c *FreezeControllerImpl) ListFreezes(ctx *gin.Context) {
+	result := c.freezeService.ListFreezes(ctx.Request.Context())
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteFreeze godoc
+// @Summary Delete a freeze
+// @Tags admin
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path int true "Freeze ID"
+// @Success 204
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Router /admin/freezes/{id} [delete]
+func (c *FreezeControllerImpl) DeleteFreeze(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: "id must be an integer"})
+	}
+
+	c.freezeService.DeleteFreeze(ctx.Request.Context(), id)
+
+	ctx.Status(http.StatusNoContent)
+}
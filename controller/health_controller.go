@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type HealthController interface {
+	Healthz(ctx *gin.Context)
+	Readyz(ctx *gin.Context)
+	Status(ctx *gin.Context)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthControllerImpl struct {
+	healthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &HealthControllerImpl{
+		healthService: healthService,
+	}
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Reports that the process is up, without checking dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} web.HealthResponse
+// @Router /healthz [get]
+func (c *HealthControllerImpl) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, web.HealthResponse{Status: "UP"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Reports whether the database answers, schemas are loaded and migrations are current
+// @Tags health
+// @Produce json
+// @Success 200 {object} web.ReadinessResponse
+// @Failure 503 {object} web.ReadinessResponse
+// @Router /readyz [get]
+func (c *HealthControllerImpl) Readyz(ctx *gin.Context) {
+	result := c.healthService.Readiness(ctx.Request.Context())
+
+	ctx.JSON(statusCode(result.Status), result)
+}
+
+// Status godoc
+// @Summary Detailed service status
+// @Description Reports database statistics, loaded schemas and build information
+// @Tags health
+// @Produce json
+// @Success 200 {object} web.StatusResponse
+// @Failure 503 {object} web.StatusResponse
+// @Router /status [get]
+func (c *HealthControllerImpl) Status(ctx *gin.Context) {
+	result := c.healthService.Status(ctx.Request.Context())
+
+	ctx.JSON(statusCode(result.Status), result)
+}
+
+func statusCode(status string) int {
+	if status == "UP" {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type RoleBindingController interface {
+	CreateBinding(ctx *gin.Context)
+	ListBindings(ctx *gin.Context)
+	DeleteBinding(ctx *gin.Context)
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleBindingControllerImpl struct {
+	roleBindingService service.RoleBindingService
+}
+
+func NewRoleBindingController(roleBindingService service.RoleBindingService) RoleBindingController {
+	return &RoleBindingControllerImpl{
+		roleBindingService: roleBindingService,
+	}
+}
+
+// CreateBinding godoc
+// @Summary Create a role binding
+// @Description Grants a role to a principal on the configs of a tenant matching a schema and name glob
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant of the bindings, defaults to the default tenant"
+// @Param request body web.RoleBindingCreateRequest true "Principal, role and scope"
+// @Success 201 {object} web.RoleBindingResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 409 {object} web.WebResponse
+// @Router /admin/role-bindings [post]
+func (c *RoleBindingControllerImpl) CreateBinding(ctx *gin.Context) {
+	var req web.RoleBindingCreateRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+
+	result := c.roleBindingService.CreateBinding(ctx.Request.Context(), req)
+
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// ListBindings godoc
+// @Summary List role bindings
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant of the bindings, defaults to the default tenant"
+// @Param principal query string false "Only list the bindings of this principal"
+// @Success 200 {array} web.RoleBindingResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /admin/role-bindings [get]
+func (c *RoleBindingControllerImpl) ListBindings(ctx *gin.Context) {
+	result := c.roleBindingService.ListBindings(ctx.Request.Context(), ctx.Query("principal"))
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteBinding godoc
+// @Summary Delete a role binding
+// @Tags admin
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant of the bindings, defaults to the default tenant"
+// @Param id path int true "Binding ID"
+// @Success 204
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Router /admin/role-bindings/{id} [delete]
+func (c *RoleBindingControllerImpl) DeleteBinding(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: "id must be an integer"})
+	}
+
+	c.roleBindingService.DeleteBinding(ctx.Request.Context(), id)
+
+	ctx.Status(http.StatusNoContent)
+}
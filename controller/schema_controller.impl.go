@@ -3,6 +3,7 @@ package controller
 import (
 	"config-service/model/domain"
 	"config-service/model/web"
+	"config-service/tenant"
 	"fmt"
 	"net/http"
 	"os"
@@ -22,12 +23,13 @@ func NewSchemaController() SchemaController {
 // @Tags schemas
 // @Produce json
 // @Param name path string true "Schema Name"
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
 // @Success 200 {object} map[string]interface{}
 // @Failure 404 {string} string "Schema not found"
 // @Router /schemas/{name} [get]
 func (controller *SchemaControllerImpl) GetSchema(c *gin.Context) {
 	name := c.Param("name")
-	filePath := fmt.Sprintf("%s/%s", "schemas", name)
+	filePath := domain.SchemaPath(tenant.FromContext(c.Request.Context()), name)
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
@@ -46,16 +48,19 @@ func (controller *SchemaControllerImpl) GetSchema(c *gin.Context) {
 // @Description Returns schema list from loaded set
 // @Tags schemas
 // @Produce json
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
 // @Success 200 {object} web.SchemaResponse
 // @Router /schemas [get]
 func (controller *SchemaControllerImpl) ListSchemas(c *gin.Context) {
 
+	tenantName := tenant.FromContext(c.Request.Context())
+
 	var schemas []web.SchemaResponse
-	for schema := range domain.Schemas {
+	for _, schema := range domain.SchemaNames(tenantName) {
 		SchemaResponse := web.SchemaResponse{
 			Name:      schema,
 			Path:      schema + ".json",
-			Directory: domain.SchemaDir + "/" + schema + ".json",
+			Directory: domain.SchemaPath(tenantName, schema+".json"),
 		}
 		schemas = append(schemas, SchemaResponse)
 	}
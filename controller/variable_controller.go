@@ -0,0 +1,9 @@
+package controller
+
+import "github.com/gin-gonic/gin"
+
+type VariableController interface {
+	ListVariables(ctx *gin.Context)
+	SetVariable(ctx *gin.Context)
+	DeleteVariable(ctx *gin.Context)
+}
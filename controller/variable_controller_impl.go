@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"config-service/helper"
+	"config-service/model/web"
+	"config-service/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VariableControllerImpl struct {
+	variableService service.VariableService
+}
+
+func NewVariableController(variableService service.VariableService) VariableController {
+	return &VariableControllerImpl{
+		variableService: variableService,
+	}
+}
+
+// ListVariables godoc
+// @Summary List the variables of an environment
+// @Tags variables
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Success 200 {array} web.VariableResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /variables [get]
+func (c *VariableControllerImpl) ListVariables(ctx *gin.Context) {
+	result := c.variableService.ListVariables(ctx.Request.Context())
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// SetVariable godoc
+// @Summary Set a variable
+// @Description Creates or replaces a variable that ${name} placeholders in the configs of the environment are rendered with
+// @Tags variables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param name path string true "Variable name"
+// @Param request body web.VariableSetRequest true "Variable value, any JSON value"
+// @Success 200 {object} web.VariableResponse
+// @Failure 400 {object} web.WebResponse
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Router /variables/{name} [put]
+func (c *VariableControllerImpl) SetVariable(ctx *gin.Context) {
+	var req web.VariableSetRequest
+	err := ctx.ShouldBindJSON(&req)
+	helper.PanicIfError(err)
+
+	result := c.variableService.SetVariable(ctx.Request.Context(), ctx.Param("name"), req)
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteVariable godoc
+// @Summary Delete a variable
+// @Tags variables
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param X-Tenant header string false "Tenant, defaults to the tenant of the caller"
+// @Param X-Environment header string false "Environment, default when omitted"
+// @Param env query string false "Environment, takes precedence over X-Environment"
+// @Param name path string true "Variable name"
+// @Success 204
+// @Failure 401 {object} web.WebResponse
+// @Failure 403 {object} web.WebResponse
+// @Failure 404 {object} web.WebResponse
+// @Router /variables/{name} [delete]
+func (c *VariableControllerImpl) DeleteVariable(ctx *gin.Context) {
+	c.variableService.DeleteVariable(ctx.Request.Context(), ctx.Param("name"))
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+// Package diff compares config documents value by value.
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change describes a single difference between two config documents. Op is
+// "add", "remove" or "change".
+type Change struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Compare compares two documents leaf by leaf, addressing values with
+// dotted paths such as "limits.daily" or "hosts[0]".
+func Compare(base, target map[string]interface{}) []Change {
+	before := map[string]interface{}{}
+	after := map[string]interface{}{}
+	Flatten("", base, before)
+	Flatten("", target, after)
+
+	var changes []Change
+	for path, old := range before {
+		updated, ok := after[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Op: "remove", Old: old})
+		case !reflect.DeepEqual(old, updated):
+			changes = append(changes, Change{Path: path, Op: "change", Old: old, New: updated})
+		}
+	}
+	for path, updated := range after {
+		if _, ok := before[path]; !ok {
+			changes = append(changes, Change{Path: path, Op: "add", New: updated})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// Flatten stores every leaf of value in out, keyed by its path below prefix.
+func Flatten(prefix string, value interface{}, out map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			out[prefix] = v
+		}
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			Flatten(path, child, out)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[prefix] = v
+		}
+		for i, child := range v {
+			Flatten(prefix+"["+strconv.Itoa(i)+"]", child, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
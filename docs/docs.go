@@ -15,106 +15,42 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/configs/{schema}/{name}": {
+        "/admin/api-keys": {
             "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "configs"
-                ],
-                "summary": "Fetch configuration",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Schema name",
-                        "name": "schema",
-                        "in": "path",
-                        "required": true
-                    },
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Configuration name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "ApiKeyAuth": []
                     },
                     {
-                        "description": "Config data",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/web.ConfigFetchRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/web.ConfigResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/web.WebResponse"
-                        }
+                        "BearerAuth": []
                     }
-                }
-            },
-            "put": {
-                "description": "Update configuration with given schema and name",
-                "consumes": [
-                    "application/json"
                 ],
+                "description": "Lists every key, including revoked ones, with its last use",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configs"
-                ],
-                "summary": "Update configuration",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Schema name",
-                        "name": "schema",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Configuration name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Config data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/web.ConfigUpdateRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.ConfigResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.APIKeyResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
@@ -122,7 +58,15 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create configuration with given schema and name",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The key is only returned by this call, the service stores its hash",
                 "consumes": [
                     "application/json"
                 ],
@@ -130,31 +74,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "configs"
+                    "admin"
                 ],
-                "summary": "Create a new configuration",
+                "summary": "Create an API key",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Schema name",
-                        "name": "schema",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Configuration name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Config data",
+                        "description": "Key name and admin flag",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.ConfigCreateRequest"
+                            "$ref": "#/definitions/web.APIKeyCreateRequest"
                         }
                     }
                 ],
@@ -162,7 +92,7 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.ConfigResponse"
+                            "$ref": "#/definitions/web.APIKeyCreateResponse"
                         }
                     },
                     "400": {
@@ -170,59 +100,69 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
                     }
                 }
             }
         },
-        "/configs/{schema}/{name}/rollback": {
-            "post": {
-                "produces": [
-                    "application/json"
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "tags": [
-                    "configs"
+                    "admin"
                 ],
-                "summary": "Rollback configuration to previous version",
+                "summary": "Revoke an API key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Schema name",
-                        "name": "schema",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Configuration name",
-                        "name": "name",
+                        "description": "Key ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Config data",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/web.ConfigRollbackRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/web.ConfigResponse"
+                            "$ref": "#/definitions/web.WebResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
@@ -230,29 +170,90 @@ const docTemplate = `{
                 }
             }
         },
-        "/configs/{schema}/{name}/versions": {
+        "/admin/audit": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists audit events newest first. Pass the smallest returned id as before_id to fetch the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "configs"
+                    "admin"
                 ],
-                "summary": "List configuration versions",
+                "summary": "List audit events",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Principal that caused the event",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. config.updated or request.denied",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "success or denied",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant",
+                        "name": "tenant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "Schema name",
                         "name": "schema",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Configuration name",
+                        "description": "Config name",
                         "name": "name",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this RFC 3339 time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events before this RFC 3339 time",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events with a smaller id",
+                        "name": "before_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events, 100 by default",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -261,12 +262,24 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/web.ConfigResponses"
+                                "$ref": "#/definitions/web.AuditEventResponse"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/web.WebResponse"
                         }
@@ -274,145 +287,3364 @@ const docTemplate = `{
                 }
             }
         },
-        "/schemas": {
+        "/admin/audit/verify": {
             "get": {
-                "description": "Returns schema list from loaded set",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recomputes the hash chain of the audit log and reports the first entry that was changed or whose predecessor was removed",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schemas"
+                    "admin"
                 ],
-                "summary": "List of loaded schemas",
+                "summary": "Verify the audit log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.SchemaResponse"
+                            "$ref": "#/definitions/web.AuditVerifyResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
                         }
                     }
                 }
             }
         },
-        "/schemas/{name}": {
+        "/admin/freezes": {
             "get": {
-                "description": "Returns a schema from the loaded set",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schemas"
-                ],
-                "summary": "Get JSON Schema by name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Schema Name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List freezes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.FreezeResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Schema not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/web.WebResponse"
                         }
-                    }
-                }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Locks the configs matching tenant, environment, schema and name globs against updates, rollbacks, cancellations, promotions and draft publications, until the freeze is deleted or during a weekly window. Changes overriding it need a reason and the approver role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a freeze",
+                "parameters": [
+                    {
+                        "description": "Scope, reason and window",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.FreezeCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.FreezeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/freezes/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a freeze",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Freeze ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/role-bindings": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List role bindings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant of the bindings, defaults to the default tenant",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list the bindings of this principal",
+                        "name": "principal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.RoleBindingResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grants a role to a principal on the configs of a tenant matching a schema and name glob",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a role binding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant of the bindings, defaults to the default tenant",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Principal, role and scope",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.RoleBindingCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.RoleBindingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/role-bindings/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a role binding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant of the bindings, defaults to the default tenant",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Binding ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the configurations of all selectors from one consistent snapshot. Selectors failing to fetch get the error and status code of a single fetch in their result.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Fetch many configurations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "schema/name for the latest version, schema/name@N for version N, schema/name@label for the version named by label",
+                        "name": "select",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Resolve references to other configs and render placeholders, true when omitted",
+                        "name": "resolve",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fail configs whose placeholders use undefined variables",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigBulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store a new version of every item in one transaction, creating the configurations that do not exist yet. Nothing is stored when any item fails, the error naming the item.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Update several configurations atomically",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for changing the configs despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Fetch configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Config version, latest when omitted",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the unmerged layers of an inheriting config",
+                        "name": "layers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Resolve references to other configs and render placeholders, true when omitted",
+                        "name": "resolve",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fail with 409 when placeholders use undefined variables",
+                        "name": "strict",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return secret values unmasked, requires the approver role",
+                        "name": "reveal",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Config data",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigFetchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified when If-None-Match matches the ETag"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update configuration with given schema and name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Update configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time the version becomes active, now when omitted",
+                        "name": "effective_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for changing the config despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Config data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create configuration with given schema and name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Create a new configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time the version becomes active, now when omitted",
+                        "name": "effective_at",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Config data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels a version scheduled with effective_at before it becomes active. Cancelled versions stay in the history but are never served.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Cancel a scheduled configuration version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for changing the config despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Scheduled version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/drafts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "List the drafts of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (default), published or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.DraftResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a draft of the next version, validated like an update, that is published once approved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "Propose a new configuration version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proposed config data and comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/environments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the latest version of the configuration in every environment having one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "List the environments of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.ConfigResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/labels": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the labels naming versions of the configuration, which fetches and promotions can select them by",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "List the labels of a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.ConfigLabelResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/labels/{label}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Names a version of the configuration by the label, moving the label when it named another version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Label a configuration version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label, a lowercase letter followed by lowercase letters, digits, '.', '_' or '-'",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for moving the label despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Labeled version",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigLabelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigLabelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Delete a configuration label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for deleting the label despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/promote": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copies a version of the configuration from one environment to another as a new version, recording the source in its metadata. Dry runs only report the changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Promote configuration to another environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for changing the target despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Source and target",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigPromoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigPromoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/referrers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every $configRef of the latest configuration versions to the configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "List the configurations referencing a configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.ConfigReferrerResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "Rollback configuration to previous version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for changing the config despite active freezes, requires the approver role",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Config data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigRollbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/configs/{schema}/{name}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "List configuration versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Schema name",
+                        "name": "schema",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Configuration name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.ConfigResponses"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drafts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the drafts of the configurations the caller can read, the pending ones by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "List the drafts of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (default), published or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.DraftResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drafts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a draft with its reviews and its changes to the version it was proposed on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "Get a draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drafts/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the approval of the caller, the draft is published as a new version once it has the approvals its schema requires",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "Approve a draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for publishing the draft despite active freezes",
+                        "name": "override",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Review comment",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Locked",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drafts/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drafts"
+                ],
+                "summary": "Reject a draft",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Draft ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason of the rejection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.DraftResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/environments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the configured environments, and those still holding configs, with their number of configs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "configs"
+                ],
+                "summary": "List environments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.EnvironmentResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is up, without checking dependencies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the database answers, schemas are loaded and migrations are current",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ReadinessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/web.ReadinessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schemas": {
+            "get": {
+                "description": "Returns schema list from loaded set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "List of loaded schemas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.SchemaResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schemas/{name}": {
+            "get": {
+                "description": "Returns a schema from the loaded set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemas"
+                ],
+                "summary": "Get JSON Schema by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Schema Name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Reports database statistics, loaded schemas and build information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Detailed service status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.StatusResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/web.StatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/variables": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "List the variables of an environment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.VariableResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/variables/{name}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or replaces a variable that ${name} placeholders in the configs of the environment are rendered with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "Set a variable",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Variable value, any JSON value",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.VariableSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.VariableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "Delete a variable",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant, defaults to the tenant of the caller",
+                        "name": "X-Tenant",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, default when omitted",
+                        "name": "X-Environment",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment, takes precedence over X-Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variable name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/web.WebResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "diff.Change": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {},
+                "op": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.APIKeyCreateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "tenant": {
+                    "description": "Tenant binds the key to one tenant, empty keys may use every tenant",
+                    "type": "string"
+                }
+            }
+        },
+        "web.APIKeyCreateResponse": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "admin": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuditEventResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after_hash": {
+                    "type": "string"
+                },
+                "before_hash": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.AuditVerifyResponse": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "first_invalid_id": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "web.BuildInfo": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "boolean"
+                },
+                "revision": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigBatchItem": {
+            "type": "object",
+            "required": [
+                "data",
+                "name",
+                "schema"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "expected_version": {
+                    "description": "ExpectedVersion fails the batch unless it is the latest version of the\nconfig, 0 expecting that the config does not exist yet and nil\nskipping the check",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigBatchRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/web.ConfigBatchItem"
+                    }
+                }
+            }
+        },
+        "web.ConfigBatchResponse": {
+            "type": "object",
+            "properties": {
+                "configs": {
+                    "description": "Configs holds the new versions in the order of the items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.ConfigResponse"
+                    }
+                },
+                "environment": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigBulkResponse": {
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "type": "string"
+                },
+                "results": {
+                    "description": "Results holds a result per selector, in the order of the selectors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.ConfigBulkResult"
+                    }
+                }
+            }
+        },
+        "web.ConfigBulkResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the HTTP status code a single fetch would have failed with",
+                    "type": "integer"
+                },
+                "config": {
+                    "$ref": "#/definitions/web.ConfigResponse"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "selector": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigCancelRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ConfigCreateRequest": {
+            "type": "object",
+            "required": [
+                "data"
+            ],
+            "properties": {
+                "data": {
+                    "description": "The configuration JSON object",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "effective_at": {
+                    "description": "EffectiveAt schedules the activation of the version, now when nil",
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigFetchRequest": {
+            "type": "object",
+            "properties": {
+                "layers": {
+                    "description": "Layers adds the unmerged layers of an inheriting config to the response",
+                    "type": "boolean"
+                },
+                "resolve": {
+                    "description": "Resolve replaces references to other configs by their values and\nrenders placeholders with variables, unless it is false",
+                    "type": "boolean"
+                },
+                "reveal": {
+                    "description": "Reveal returns the values marked secret by the schema instead of\nmasking them, it requires the approver role",
+                    "type": "boolean"
+                },
+                "strict": {
+                    "description": "Strict fails fetches of configs using undefined variables instead of\nleaving their placeholders as they are",
+                    "type": "boolean"
+                },
+                "version": {
+                    "description": "Version is optional",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ConfigLabelRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "description": "Version the label names",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "web.ConfigLabelResponse": {
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ConfigPromoteRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "dry_run": {
+                    "description": "Only report the changes",
+                    "type": "boolean"
+                },
+                "from": {
+                    "description": "Source environment",
+                    "type": "string"
+                },
+                "label": {
+                    "description": "Label naming the source version instead of Version",
+                    "type": "string",
+                    "maxLength": 63
+                },
+                "to": {
+                    "description": "Target environment",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Source version, latest when 0",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "web.ConfigPromoteResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changes compares Previous with Config",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Change"
+                    }
+                },
+                "config": {
+                    "description": "Config is the promoted version, or the version it would become on dry runs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    ]
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "previous": {
+                    "description": "Previous is the latest version of the target before the promotion",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.ConfigResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "web.ConfigReferrerResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is the JSON pointer of the reference within the referring config",
+                    "type": "string"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the latest version of the referring config",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ConfigResponse": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "raw JSON",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "effective_at": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "layers": {
+                    "description": "Layers holds the config and its ancestors as stored, nearest first,\nwhen requested on fetch",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.ConfigResponse"
+                    }
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ConfigResponses": {
+            "type": "object",
+            "properties": {
+                "configVersions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.ConfigResponse"
+                    }
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ConfigRollbackRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "integer"
+                }
             }
-        }
-    },
-    "definitions": {
-        "web.ConfigCreateRequest": {
+        },
+        "web.ConfigUpdateRequest": {
             "type": "object",
             "required": [
                 "data"
             ],
             "properties": {
                 "data": {
-                    "description": "The configuration JSON object",
                     "type": "object",
                     "additionalProperties": true
+                },
+                "effective_at": {
+                    "description": "EffectiveAt schedules the activation of the version, now when nil",
+                    "type": "string"
                 }
             }
         },
-        "web.ConfigFetchRequest": {
+        "web.DatabaseStatus": {
             "type": "object",
             "properties": {
-                "version": {
-                    "description": "Version is optional",
+                "configs": {
+                    "type": "integer"
+                },
+                "idle": {
+                    "type": "integer"
+                },
+                "in_use": {
+                    "type": "integer"
+                },
+                "open_connections": {
+                    "type": "integer"
+                },
+                "pending_migrations": {
+                    "type": "integer"
+                },
+                "versions": {
+                    "type": "integer"
+                },
+                "wait_count": {
                     "type": "integer"
+                },
+                "wait_duration": {
+                    "type": "string"
                 }
             }
         },
-        "web.ConfigResponse": {
+        "web.DraftCreateRequest": {
+            "type": "object",
+            "required": [
+                "data"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "Comment tells reviewers what the draft changes and why",
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "web.DraftResponse": {
             "type": "object",
             "properties": {
+                "approvals": {
+                    "type": "integer"
+                },
+                "base_version": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Changes compares the base version with the draft, only set for\nsingle drafts",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/diff.Change"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "type": "string"
+                },
                 "data": {
-                    "description": "raw JSON",
                     "type": "object",
                     "additionalProperties": true
                 },
+                "environment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
+                "published_version": {
+                    "type": "integer"
+                },
+                "required_approvals": {
+                    "description": "RequiredApprovals is the number of approvals publishing the draft",
+                    "type": "integer"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.DraftReviewResponse"
+                    }
+                },
                 "schema": {
                     "type": "string"
                 },
-                "version": {
+                "status": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.DraftReviewRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "web.DraftReviewResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "type": "string"
+                },
+                "reviewer": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.EnvironmentResponse": {
+            "type": "object",
+            "properties": {
+                "configs": {
+                    "description": "number of configs with a version in the environment",
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
                 }
             }
         },
-        "web.ConfigResponses": {
+        "web.FreezeCreateRequest": {
             "type": "object",
+            "required": [
+                "reason",
+                "schema"
+            ],
             "properties": {
-                "configVersions": {
+                "environment": {
+                    "type": "string"
+                },
+                "name_pattern": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "window": {
+                    "description": "Window makes the freeze recur every week, the freeze is a manual lock\nuntil it is deleted when nil",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.FreezeWindow"
+                        }
+                    ]
+                }
+            }
+        },
+        "web.FreezeResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Whether the freeze locks its configs now",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name_pattern": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
+                },
+                "window": {
+                    "$ref": "#/definitions/web.FreezeWindow"
+                }
+            }
+        },
+        "web.FreezeWindow": {
+            "type": "object",
+            "required": [
+                "end",
+                "start"
+            ],
+            "properties": {
+                "days": {
+                    "description": "Every day when empty",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/web.ConfigResponse"
+                        "type": "string"
                     }
                 },
+                "end": {
+                    "type": "string",
+                    "example": "06:00"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "timezone": {
+                    "description": "UTC when empty",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "web.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
+                }
+            }
+        },
+        "web.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.ReadinessResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.HealthCheck"
+                    }
                 },
-                "schema": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "web.ConfigRollbackRequest": {
+        "web.RoleBindingCreateRequest": {
             "type": "object",
             "required": [
-                "version"
+                "principal",
+                "role",
+                "schema"
             ],
             "properties": {
-                "version": {
-                    "type": "integer"
+                "name_pattern": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "reader",
+                        "writer",
+                        "approver",
+                        "admin"
+                    ]
+                },
+                "schema": {
+                    "type": "string"
                 }
             }
         },
-        "web.ConfigUpdateRequest": {
+        "web.RoleBindingResponse": {
             "type": "object",
-            "required": [
-                "data"
-            ],
             "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": true
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name_pattern": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "schema": {
+                    "type": "string"
+                },
+                "tenant": {
+                    "type": "string"
                 }
             }
         },
@@ -430,6 +3662,65 @@ const docTemplate = `{
                 }
             }
         },
+        "web.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "$ref": "#/definitions/web.BuildInfo"
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.HealthCheck"
+                    }
+                },
+                "database": {
+                    "$ref": "#/definitions/web.DatabaseStatus"
+                },
+                "schema_count": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.VariableResponse": {
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "web.VariableSetRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {}
+            }
+        },
         "web.WebResponse": {
             "type": "object",
             "properties": {
@@ -442,6 +3733,19 @@ const docTemplate = `{
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Bearer token issued by the configured identity provider, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
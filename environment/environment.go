@@ -0,0 +1,44 @@
+// Package environment holds the environment (e.g. dev, staging or prod) a
+// request operates on. Every environment keeps its own version history of a
+// config.
+package environment
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the environment of requests that do not name one, and of the
+// configs stored before environments were introduced.
+const Default = "default"
+
+// Header selects the environment of HTTP requests, and as lowercase metadata
+// key the environment of gRPC requests.
+const Header = "X-Environment"
+
+// Query selects the environment of HTTP requests, taking precedence over
+// the header.
+const Query = "env"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Valid reports whether name can be used as an environment: lowercase
+// letters, digits, underscores and dashes, starting with a letter or digit.
+func Valid(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+type environmentKey struct{}
+
+// With returns a copy of ctx operating on environment.
+func With(ctx context.Context, environment string) context.Context {
+	return context.WithValue(ctx, environmentKey{}, environment)
+}
+
+// FromContext returns the environment of ctx, Default when none was requested.
+func FromContext(ctx context.Context) string {
+	if environment, ok := ctx.Value(environmentKey{}).(string); ok && environment != "" {
+		return environment
+	}
+	return Default
+}
@@ -1,24 +1,43 @@
 package exception
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 
 	"config-service/helper"
 	"config-service/model/web"
 
+	validatorv9 "github.com/go-playground/validator"
 	"github.com/go-playground/validator/v10"
 )
 
 func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interface{}) {
 
-	log.Printf("[ERROR] %s %s - %v", request.Method, request.URL.Path, err)
+	logError(request.Context(), err, "method", request.Method, "path", request.URL.Path)
 
 	if notFoundError(writer, request, err) {
 		return
 	}
 
+	if conflictError(writer, request, err) {
+		return
+	}
+
+	if unauthorizedError(writer, request, err) {
+		return
+	}
+
+	if forbiddenError(writer, request, err) {
+		return
+	}
+
+	if lockedError(writer, request, err) {
+		return
+	}
+
 	if validationErrors(writer, request, err) {
 		return
 	}
@@ -27,6 +46,11 @@ func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interfa
 }
 
 func validationErrors(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	// Services validate requests with validator v9, gin bindings with v10
+	if exception, ok := err.(validatorv9.ValidationErrors); ok {
+		err = helper.ValidationError{Msg: exception.Error()}
+	}
+
 	exception, ok := err.(validator.ValidationErrors)
 	if ok {
 		writer.Header().Set("Content-Type", "application/json")
@@ -76,6 +100,82 @@ func notFoundError(writer http.ResponseWriter, request *http.Request, err interf
 	}
 }
 
+func conflictError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(ConflictError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusConflict)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusConflict,
+			Status: "CONFLICT",
+			Data:   exception.Error,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+func unauthorizedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(UnauthorizedError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusUnauthorized)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusUnauthorized,
+			Status: "UNAUTHORIZED",
+			Data:   exception.Error,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+func forbiddenError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(ForbiddenError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusForbidden)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "FORBIDDEN",
+			Data:   exception.Error,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+func lockedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(LockedError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusLocked)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusLocked,
+			Status: "LOCKED",
+			Data:   exception.Error,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
 func internalServerError(writer http.ResponseWriter, request *http.Request, err interface{}) {
 	writer.Header().Set("Content-Type", "application/json")
 	writer.WriteHeader(http.StatusInternalServerError)
@@ -88,3 +188,60 @@ func internalServerError(writer http.ResponseWriter, request *http.Request, err
 
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+// ErrorMessage returns the client facing message of a recovered panic.
+func ErrorMessage(err interface{}) string {
+	switch e := err.(type) {
+	case NotFoundError:
+		return e.Error
+	case ConflictError:
+		return e.Error
+	case UnauthorizedError:
+		return e.Error
+	case ForbiddenError:
+		return e.Error
+	case LockedError:
+		return e.Error
+	case helper.ValidationError:
+		return e.Msg
+	case error:
+		return e.Error()
+	default:
+		return fmt.Sprintf("%v", err)
+	}
+}
+
+// StatusCode returns the HTTP status code of a client error, 0 for any
+// other error.
+func StatusCode(err interface{}) int {
+	switch err.(type) {
+	case NotFoundError:
+		return http.StatusNotFound
+	case ConflictError:
+		return http.StatusConflict
+	case UnauthorizedError:
+		return http.StatusUnauthorized
+	case ForbiddenError:
+		return http.StatusForbidden
+	case LockedError:
+		return http.StatusLocked
+	case helper.ValidationError, validator.ValidationErrors, validatorv9.ValidationErrors:
+		return http.StatusBadRequest
+	default:
+		return 0
+	}
+}
+
+// logError logs client errors as warnings and everything else as errors
+// with the stack trace of the panic.
+func logError(ctx context.Context, err interface{}, attrs ...any) {
+	attrs = append(attrs, "error", ErrorMessage(err), "error_type", fmt.Sprintf("%T", err))
+
+	switch err.(type) {
+	case NotFoundError, ConflictError, UnauthorizedError, ForbiddenError, LockedError, helper.ValidationError, validator.ValidationErrors, validatorv9.ValidationErrors:
+		slog.WarnContext(ctx, "request failed", attrs...)
+	default:
+		attrs = append(attrs, "stack", string(debug.Stack()))
+		slog.ErrorContext(ctx, "request failed", attrs...)
+	}
+}
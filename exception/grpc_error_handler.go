@@ -0,0 +1,41 @@
+package exception
+
+import (
+	"context"
+	"fmt"
+
+	"config-service/helper"
+
+	validatorv9 "github.com/go-playground/validator"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCErrorHandler converts a recovered panic into a gRPC status error using
+// the same classification as ErrorHandler.
+func GRPCErrorHandler(ctx context.Context, method string, err interface{}) error {
+
+	logError(ctx, err, "grpc_method", method)
+
+	switch e := err.(type) {
+	case NotFoundError:
+		return status.Error(codes.NotFound, e.Error)
+	case ConflictError:
+		return status.Error(codes.AlreadyExists, e.Error)
+	case UnauthorizedError:
+		return status.Error(codes.Unauthenticated, e.Error)
+	case ForbiddenError:
+		return status.Error(codes.PermissionDenied, e.Error)
+	case LockedError:
+		return status.Error(codes.FailedPrecondition, e.Error)
+	case validator.ValidationErrors:
+		return status.Error(codes.InvalidArgument, e.Error())
+	case validatorv9.ValidationErrors:
+		return status.Error(codes.InvalidArgument, e.Error())
+	case helper.ValidationError:
+		return status.Error(codes.InvalidArgument, e.Msg)
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("%v", err))
+	}
+}
@@ -0,0 +1,9 @@
+package exception
+
+type LockedError struct {
+	Error string
+}
+
+func NewLockedError(error string) LockedError {
+	return LockedError{Error: error}
+}
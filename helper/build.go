@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"config-service/model/web"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is set at build time with -ldflags "-X config-service/helper.Version=...".
+var Version = "dev"
+
+func BuildInfo() web.BuildInfo {
+	buildInfo := web.BuildInfo{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return buildInfo
+	}
+
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			buildInfo.Revision = setting.Value
+		case "vcs.time":
+			buildInfo.BuildTime = setting.Value
+		case "vcs.modified":
+			buildInfo.Modified = setting.Value == "true"
+		}
+	}
+
+	return buildInfo
+}
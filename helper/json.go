@@ -1,6 +1,8 @@
 package helper
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 )
@@ -17,3 +19,30 @@ func WriteToResponseBody(writer http.ResponseWriter, response interface{}) {
 	err := encoder.Encode(response)
 	PanicIfError(err)
 }
+
+// NormalizeData round-trips data through JSON so it compares equal to data
+// read back from the database.
+func NormalizeData(data map[string]interface{}) map[string]interface{} {
+	content, err := json.Marshal(data)
+	PanicIfError(err)
+
+	var normalized map[string]interface{}
+	err = json.Unmarshal(content, &normalized)
+	PanicIfError(err)
+
+	return normalized
+}
+
+// DataHash returns the SHA-256 of the JSON encoding of data, which sorts
+// object keys, or "" for no data.
+func DataHash(data map[string]interface{}) string {
+	if data == nil {
+		return ""
+	}
+
+	content, err := json.Marshal(data)
+	PanicIfError(err)
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
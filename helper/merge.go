@@ -0,0 +1,26 @@
+package helper
+
+// MergeDocuments applies override to base like a JSON merge patch (RFC 7386):
+// objects merge recursively, null removes a key and any other value,
+// including arrays, replaces the one of base. Neither argument is modified.
+func MergeDocuments(base, override map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range override {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+
+		if overrideObject, ok := value.(map[string]interface{}); ok {
+			baseObject, _ := merged[key].(map[string]interface{})
+			value = MergeDocuments(baseObject, overrideObject)
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
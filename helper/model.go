@@ -1,23 +1,40 @@
 package helper
 
 import (
+	"config-service/metrics"
 	"config-service/model/domain"
 	"config-service/model/web"
+	"fmt"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
+// ToConfigResponse maps a config version to its response with the secret
+// values masked.
 func ToConfigResponse(config domain.ConfigRecord) web.ConfigResponse {
+	response := ToRevealedConfigResponse(config)
+	response.Data = MaskSecrets(config.Tenant, config.Schema, config.Data)
+	return response
+}
+
+// ToRevealedConfigResponse is ToConfigResponse keeping the secret values.
+func ToRevealedConfigResponse(config domain.ConfigRecord) web.ConfigResponse {
 	return web.ConfigResponse{
-		Schema:    config.Schema,
-		Name:      config.Name,
-		Version:   config.Version,
-		Data:      config.Data,
-		CreatedAt: config.CreatedAt,
+		Tenant:      config.Tenant,
+		Environment: config.Environment,
+		Schema:      config.Schema,
+		Name:        config.Name,
+		Version:     config.Version,
+		Data:        config.Data,
+		CreatedAt:   config.CreatedAt,
+		CreatedBy:   config.CreatedBy,
+		Metadata:    config.Metadata,
+		EffectiveAt: config.EffectiveAt,
+		CancelledAt: config.CancelledAt,
 	}
 }
 
-func ToConfigResponses(schema string, name string, configRecords []domain.ConfigRecord) web.ConfigResponses {
+func ToConfigResponses(environment string, schema string, name string, configRecords []domain.ConfigRecord) web.ConfigResponses {
 	configResponses := make([]web.ConfigResponse, 0)
 	for _, config := range configRecords {
 		schema = config.Schema
@@ -26,37 +43,186 @@ func ToConfigResponses(schema string, name string, configRecords []domain.Config
 	}
 
 	return web.ConfigResponses{
+		Environment:    environment,
 		Schema:         schema,
 		Name:           name,
 		ConfigVersions: configResponses,
 	}
 }
 
-func ValidateSchemaExistence(schemaName string) string {
-	schema, ok := domain.Schemas[schemaName]
+// ConfigETag returns the entity tag of a served config version. The data of
+// inheriting configs changes with their parents, so the tag also covers the
+// served data.
+func ConfigETag(config web.ConfigResponse) string {
+	return fmt.Sprintf(`"%s/%s/%s/%s/%d/%.16s"`, config.Tenant, config.Environment, config.Schema, config.Name, config.Version, DataHash(config.Data))
+}
+
+func ValidateSchemaExistence(tenant, schemaName string) string {
+	schema, ok := domain.LookupSchema(tenant, schemaName)
 	if !ok {
 		PanicIfError(ValidationError{Msg: "unknown schema"})
 	}
 	return schema
 }
 
-func ValidateAgainstSchema(schemaName string, data map[string]interface{}) {
+func ValidateAgainstSchema(tenant, schemaName string, data map[string]interface{}) {
+
+	schema := ValidateSchemaExistence(tenant, schemaName)
+
+	err := CheckAgainstSchema(schema, data)
+	if err != nil {
+		metrics.ValidationFailures.WithLabelValues(schemaName).Inc()
+	}
+	PanicIfError(err)
+}
 
-	schema := ValidateSchemaExistence(schemaName)
+// CheckAgainstSchema validates data against a JSON schema document and
+// returns a ValidationError describing every violation.
+func CheckAgainstSchema(schema string, data map[string]interface{}) error {
 	schemaLoader := gojsonschema.NewStringLoader(schema)
 
 	// Load data as JSON document
 	docLoader := gojsonschema.NewGoLoader(data)
 
 	res, err := gojsonschema.Validate(schemaLoader, docLoader)
-	PanicIfError(err)
+	if err != nil {
+		return err
+	}
 
 	if !res.Valid() {
 		msgs := ""
 		for _, e := range res.Errors() {
 			msgs += e.String() + "; "
 		}
-		PanicIfError(ValidationError{Msg: "schema validation failed: " + msgs})
+		return ValidationError{Msg: "schema validation failed: " + msgs}
+	}
+
+	return nil
+}
+
+func ToAPIKeyResponse(key domain.APIKey) web.APIKeyResponse {
+	return web.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Admin:      key.Admin,
+		Tenant:     key.Tenant,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+func ToRoleBindingResponse(binding domain.RoleBinding) web.RoleBindingResponse {
+	return web.RoleBindingResponse{
+		ID:          binding.ID,
+		Tenant:      binding.Tenant,
+		Principal:   binding.Principal,
+		Role:        binding.Role,
+		Schema:      binding.Schema,
+		NamePattern: binding.NamePattern,
+		CreatedAt:   binding.CreatedAt,
+		CreatedBy:   binding.CreatedBy,
+	}
+}
+
+// ToFreezeResponse maps a freeze to its response, active telling whether
+// it locks its configs at the time of the request.
+func ToFreezeResponse(freeze domain.Freeze, active bool) web.FreezeResponse {
+	response := web.FreezeResponse{
+		ID:          freeze.ID,
+		Tenant:      freeze.Tenant,
+		Environment: freeze.Environment,
+		Schema:      freeze.Schema,
+		NamePattern: freeze.NamePattern,
+		Reason:      freeze.Reason,
+		Active:      active,
+		CreatedAt:   freeze.CreatedAt,
+		CreatedBy:   freeze.CreatedBy,
+	}
+	if freeze.Window != nil {
+		response.Window = &web.FreezeWindow{
+			Days:     freeze.Window.Days,
+			Start:    freeze.Window.Start,
+			End:      freeze.Window.End,
+			Timezone: freeze.Window.Timezone,
+		}
+	}
+	return response
+}
+
+// ToDraftResponse maps a draft to its response with the secret values
+// masked.
+func ToDraftResponse(draft domain.Draft, requiredApprovals int) web.DraftResponse {
+	reviews := make([]web.DraftReviewResponse, 0, len(draft.Reviews))
+	for _, review := range draft.Reviews {
+		reviews = append(reviews, web.DraftReviewResponse{
+			Reviewer:  review.Reviewer,
+			Decision:  review.Decision,
+			Comment:   review.Comment,
+			CreatedAt: review.CreatedAt,
+		})
+	}
+
+	return web.DraftResponse{
+		ID:                draft.ID,
+		Tenant:            draft.Tenant,
+		Environment:       draft.Environment,
+		Schema:            draft.Schema,
+		Name:              draft.Name,
+		BaseVersion:       draft.BaseVersion,
+		Data:              MaskSecrets(draft.Tenant, draft.Schema, draft.Data),
+		Comment:           draft.Comment,
+		Status:            draft.Status,
+		PublishedVersion:  draft.PublishedVersion,
+		RequiredApprovals: requiredApprovals,
+		Approvals:         len(draft.Approvals()),
+		Reviews:           reviews,
+		CreatedAt:         draft.CreatedAt,
+		CreatedBy:         draft.CreatedBy,
+		UpdatedAt:         draft.UpdatedAt,
 	}
+}
+
+func ToVariableResponse(variable domain.Variable) web.VariableResponse {
+	return web.VariableResponse{
+		Environment: variable.Environment,
+		Name:        variable.Name,
+		Value:       variable.Value,
+		UpdatedAt:   variable.UpdatedAt,
+		UpdatedBy:   variable.UpdatedBy,
+	}
+}
 
+func ToConfigLabelResponse(label domain.ConfigLabel) web.ConfigLabelResponse {
+	return web.ConfigLabelResponse{
+		Environment: label.Environment,
+		Schema:      label.Schema,
+		Name:        label.Name,
+		Label:       label.Label,
+		Version:     label.Version,
+		UpdatedAt:   label.UpdatedAt,
+		UpdatedBy:   label.UpdatedBy,
+	}
+}
+
+func ToAuditEventResponse(event domain.AuditEvent) web.AuditEventResponse {
+	return web.AuditEventResponse{
+		ID:          event.ID,
+		OccurredAt:  event.OccurredAt,
+		Actor:       event.Actor,
+		Source:      event.Source,
+		Action:      event.Action,
+		Outcome:     event.Outcome,
+		Tenant:      event.Tenant,
+		Environment: event.Environment,
+		Schema:      event.Schema,
+		Name:        event.Name,
+		Version:     event.Version,
+		BeforeHash:  event.BeforeHash,
+		AfterHash:   event.AfterHash,
+		Detail:      event.Detail,
+		PrevHash:    event.PrevHash,
+		Hash:        event.Hash,
+	}
 }
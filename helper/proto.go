@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ToProtoConfig(config web.ConfigResponse) *configpb.Config {
+	data, err := structpb.NewStruct(config.Data)
+	PanicIfError(err)
+
+	var metadata *structpb.Struct
+	if len(config.Metadata) > 0 {
+		metadata, err = structpb.NewStruct(config.Metadata)
+		PanicIfError(err)
+	}
+
+	var layers []*configpb.Config
+	for _, layer := range config.Layers {
+		layers = append(layers, ToProtoConfig(layer))
+	}
+
+	return &configpb.Config{
+		Schema:      config.Schema,
+		Name:        config.Name,
+		Version:     int32(config.Version),
+		Data:        data,
+		CreatedAt:   timestamppb.New(config.CreatedAt),
+		CreatedBy:   config.CreatedBy,
+		Tenant:      config.Tenant,
+		Environment: config.Environment,
+		Metadata:    metadata,
+		Layers:      layers,
+		EffectiveAt: toProtoTime(config.EffectiveAt),
+		CancelledAt: toProtoTime(config.CancelledAt),
+	}
+}
+
+// FromProtoTime maps an optional timestamp, nil when it is unset.
+func FromProtoTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	at := timestamp.AsTime()
+	return &at
+}
+
+func toProtoTime(at *time.Time) *timestamppb.Timestamp {
+	if at == nil {
+		return nil
+	}
+	return timestamppb.New(*at)
+}
+
+func ToProtoConfigVersions(configs web.ConfigResponses) *configpb.ConfigVersions {
+	configVersions := make([]*configpb.Config, 0, len(configs.ConfigVersions))
+	for _, config := range configs.ConfigVersions {
+		configVersions = append(configVersions, ToProtoConfig(config))
+	}
+
+	return &configpb.ConfigVersions{
+		Schema:         configs.Schema,
+		Name:           configs.Name,
+		ConfigVersions: configVersions,
+	}
+}
@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"config-service/model/domain"
+	"config-service/secret"
+)
+
+// SecretPaths returns the paths of the values marked secret by a schema of
+// tenant, none for unknown schemas.
+func SecretPaths(tenant, schemaName string) []secret.Path {
+	schema, ok := domain.LookupSchema(tenant, schemaName)
+	if !ok {
+		return nil
+	}
+
+	// Malformed schemas are reported by validation, they mark nothing secret
+	paths, _ := secret.Paths(schema)
+	return paths
+}
+
+// MaskSecrets returns data with the values marked secret by its schema
+// masked.
+func MaskSecrets(tenant, schemaName string, data map[string]interface{}) map[string]interface{} {
+	paths := SecretPaths(tenant, schemaName)
+	if len(paths) == 0 {
+		return data
+	}
+	return secret.MaskValues(data, paths)
+}
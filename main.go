@@ -3,76 +3,70 @@
 // @description This is a service for managing configurations with versioning.
 // @host localhost:3000
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer token issued by the configured identity provider, as "Bearer <token>"
 package main
 
 import (
-	"config-service/app"
-	"config-service/controller"
-	"config-service/model/domain"
-	"config-service/repository"
-	"config-service/service"
-	"context"
-	"log"
-	"net/http"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"strings"
 
 	_ "config-service/docs"
 
-	"github.com/go-playground/validator"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func main() {
-
-	// Load schema
-	err := domain.LoadSchemas(domain.SchemaDir)
-	if err != nil {
-		log.Fatalf("Error loading schemas: %v", err)
-	}
+const usage = `Usage: config-service [command] [flags]
 
-	log.Println("Loaded schemas:", domain.Schemas)
+Commands:
+  serve                 Run the HTTP and gRPC servers (default)
+  migrate               Apply pending database migrations (-status to only list them)
+  validate-schemas      Check that every schema file is a valid JSON schema
+  revalidate-configs    Validate stored configs against their current schema
+  export                Write every config version as JSON
+  import                Restore config versions from an export
+  compact               Delete old versions and reclaim database space
+  rotate-secrets        Re-encrypt secret values with the active secret key
+  create-api-key        Create an API key (-name NAME, -admin for admin endpoints)
+`
 
-	// Setup dependencies
-	db := app.NewDB()
-	validate := validator.New()
-	configRepository := repository.NewConfigRepository()
-	configService := service.NewConfigService(configRepository, db, validate)
-	configController := controller.NewConfigController(configService)
-	schemaController := controller.NewSchemaController()
-
-	router := app.NewRouter(configController, schemaController)
-
-	server := &http.Server{
-		Addr:    ":3000",
-		Handler: router,
+func main() {
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
 	}
 
-	// Run server in a goroutine so it won't block
-	go func() {
-		log.Println("Running config service on port 3000...")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Could not listen on port 3000: %v\n", err)
-		}
-	}()
-
-	// Listen for interrupt signals (Ctrl+C, docker stop, etc.)
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-
-	// Block until a signal is received
-	<-stop
-	log.Println("Shutting down gracefully...")
-
-	// Give ongoing requests 5 seconds to complete
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	switch command {
+	case "serve":
+		serve(args)
+	case "migrate":
+		migrate(args)
+	case "validate-schemas":
+		validateSchemas(args)
+	case "revalidate-configs":
+		revalidateConfigs(args)
+	case "export":
+		exportConfigs(args)
+	case "import":
+		importConfigs(args)
+	case "compact":
+		compactConfigs(args)
+	case "rotate-secrets":
+		rotateSecrets(args)
+	case "create-api-key":
+		createAPIKey(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
-
-	log.Println("Server stopped.")
 }
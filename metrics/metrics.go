@@ -0,0 +1,72 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry is dedicated to this service so tests can build several routers
+// without registering collectors twice.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "config_service_http_requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "config_service_http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RepositoryQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "config_service_repository_query_duration_seconds",
+		Help:    "Latency of ConfigRepository queries by method.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"method"})
+
+	ValidationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "config_service_validation_failures_total",
+		Help: "Configs rejected by JSON schema validation, by schema.",
+	}, []string{"schema"})
+
+	VersionsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "config_service_versions_created_total",
+		Help: "Config versions written, by schema.",
+	}, []string{"schema"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "config_service_cache_requests_total",
+		Help: "Config fetches answered from the client cache through a matching ETag (hit) or with a full body (miss).",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RepositoryQueryDuration,
+		ValidationFailures,
+		VersionsCreated,
+		CacheRequests,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveQuery records the latency of a repository method, use it as
+// defer metrics.ObserveQuery("GetLatest", time.Now()).
+func ObserveQuery(method string, start time.Time) {
+	RepositoryQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type APIKey struct {
+	ID         string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Admin      bool
+	Tenant     string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
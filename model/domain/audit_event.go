@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// Audit actions.
+const (
+	AuditConfigCreated      = "config.created"
+	AuditConfigUpdated      = "config.updated"
+	AuditConfigRolledBack   = "config.rolled_back"
+	AuditConfigDeleted      = "config.deleted"
+	AuditConfigImported     = "config.imported"
+	AuditConfigPromoted     = "config.promoted"
+	AuditConfigPublished    = "config.published"
+	AuditConfigCancelled    = "config.cancelled"
+	AuditDraftProposed      = "draft.proposed"
+	AuditDraftApproved      = "draft.approved"
+	AuditDraftRejected      = "draft.rejected"
+	AuditDraftSuperseded    = "draft.superseded"
+	AuditLabelSet           = "label.set"
+	AuditLabelDeleted       = "label.deleted"
+	AuditSchemaCreated      = "schema.created"
+	AuditSchemaChanged      = "schema.changed"
+	AuditSchemaRemoved      = "schema.removed"
+	AuditAPIKeyCreated      = "api_key.created"
+	AuditAPIKeyRevoked      = "api_key.revoked"
+	AuditRoleBindingCreated = "role_binding.created"
+	AuditRoleBindingDeleted = "role_binding.deleted"
+	AuditFreezeCreated      = "freeze.created"
+	AuditFreezeDeleted      = "freeze.deleted"
+	AuditVariableSet        = "variable.set"
+	AuditVariableDeleted    = "variable.deleted"
+	AuditSecretsRevealed    = "config.secrets_revealed"
+	AuditSecretsRotated     = "secrets.rotated"
+	AuditRequestDenied      = "request.denied"
+)
+
+// Audit outcomes.
+const (
+	AuditSuccess = "success"
+	AuditDenied  = "denied"
+)
+
+// AuditEvent is an entry of the append-only audit log. Hash covers every
+// other field and the hash of the previous entry, so that changing or
+// removing an entry breaks the chain.
+type AuditEvent struct {
+	ID          int64
+	OccurredAt  time.Time
+	Actor       string
+	Source      string
+	Action      string
+	Outcome     string
+	Tenant      string
+	Environment string
+	Schema      string
+	Name        string
+	Version     int
+	BeforeHash  string
+	AfterHash   string
+	Detail      string
+	PrevHash    string
+	Hash        string
+}
+
+// AuditFilter selects audit events, empty fields match everything.
+type AuditFilter struct {
+	Actor       string
+	Action      string
+	Outcome     string
+	Tenant      string
+	Environment string
+	Schema      string
+	Name        string
+	Since       time.Time
+	Until       time.Time
+	BeforeID    int64
+	Limit       int
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// ConfigLabel names a version of a config, like stable or canary, so that
+// fetches and promotions can select it by name instead of by number.
+type ConfigLabel struct {
+	Tenant      string
+	Environment string
+	Schema      string
+	Name        string
+	Label       string
+	Version     int
+	UpdatedAt   time.Time
+	UpdatedBy   string
+}
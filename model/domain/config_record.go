@@ -2,10 +2,43 @@ package domain
 
 import "time"
 
+// ParentKey is the top-level key of config data naming the config it
+// inherits from. The parent belongs to the same tenant, environment and
+// schema, and the data only holds the overrides of the child.
+const ParentKey = "$parent"
+
+// ReferenceKey is the only key of a config data object replaced by a value
+// of another config on fetch, e.g. {"$configRef": "shared/endpoints#/payments/url"}.
+const ReferenceKey = "$configRef"
+
 type ConfigRecord struct {
-	Schema    string                 `json:"schema"`
-	Name      string                 `json:"name"`
-	Version   int                    `json:"version"`
-	Data      map[string]interface{} `json:"data"` // raw JSON
-	CreatedAt time.Time              `json:"created_at"`
+	Tenant      string                 `json:"tenant"`
+	Environment string                 `json:"environment"`
+	Schema      string                 `json:"schema"`
+	Name        string                 `json:"name"`
+	Version     int                    `json:"version"`
+	Data        map[string]interface{} `json:"data"` // raw JSON
+	CreatedAt   time.Time              `json:"created_at"`
+	CreatedBy   string                 `json:"created_by,omitempty"` // subject of the caller
+	// Metadata describes how the version was made, e.g. its promotion source
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// EffectiveAt is the time a scheduled version becomes active, nil for
+	// versions active once created
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+	// CancelledAt is the time a scheduled version was cancelled, cancelled
+	// versions never become active
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// Scheduled reports whether the version is still waiting for its activation
+// at the given time.
+func (config ConfigRecord) Scheduled(at time.Time) bool {
+	return config.CancelledAt == nil && config.EffectiveAt != nil && config.EffectiveAt.After(at)
+}
+
+// Effective reports whether the version took effect by the given time, as
+// it was not cancelled and its activation time passed. The active version
+// of a config is its newest effective one.
+func (config ConfigRecord) Effective(at time.Time) bool {
+	return config.CancelledAt == nil && (config.EffectiveAt == nil || !config.EffectiveAt.After(at))
 }
@@ -0,0 +1,7 @@
+package domain
+
+// ConfigStats counts stored configs and their versions.
+type ConfigStats struct {
+	Configs  int
+	Versions int
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Draft states. Drafts are proposed as pending and end up published as a
+// new version of their config once approved, or rejected. Drafts approved
+// after their config changed are superseded instead of published.
+const (
+	DraftPending    = "pending"
+	DraftPublished  = "published"
+	DraftRejected   = "rejected"
+	DraftSuperseded = "superseded"
+)
+
+// Review decisions.
+const (
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Draft is a proposed version of a config, stored but not served until
+// enough reviewers approve it.
+type Draft struct {
+	ID          int64
+	Tenant      string
+	Environment string
+	Schema      string
+	Name        string
+	// BaseVersion is the latest version of the config when the draft was
+	// proposed, 0 for new configs
+	BaseVersion      int
+	Data             map[string]interface{}
+	Comment          string
+	Status           string
+	PublishedVersion int
+	CreatedAt        time.Time
+	CreatedBy        string
+	UpdatedAt        time.Time
+	Reviews          []DraftReview
+}
+
+// Approvals returns the reviewers that approved the draft.
+func (draft Draft) Approvals() []string {
+	approvers := []string{}
+	for _, review := range draft.Reviews {
+		if review.Decision == ReviewApproved {
+			approvers = append(approvers, review.Reviewer)
+		}
+	}
+	return approvers
+}
+
+type DraftReview struct {
+	Reviewer  string
+	Decision  string
+	Comment   string
+	CreatedAt time.Time
+}
+
+// DraftFilter selects drafts, empty fields match every draft.
+type DraftFilter struct {
+	Tenant      string
+	Environment string
+	Schema      string
+	Name        string
+	Status      string
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"path"
+	"slices"
+	"time"
+)
+
+// Weekdays lists the day names of freeze windows, indexed by time.Weekday.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Freeze locks the configs whose tenant, environment, schema and name match
+// the given glob patterns against changes. Freezes without a Window are
+// manual locks, active until they are deleted.
+type Freeze struct {
+	ID          int64
+	Tenant      string
+	Environment string
+	Schema      string
+	NamePattern string
+	Reason      string
+	Window      *FreezeWindow
+	CreatedAt   time.Time
+	CreatedBy   string
+}
+
+// FreezeWindow is a change window that recurs every week, from Start to End
+// on each of Days. Windows ending before they start end on the next day.
+type FreezeWindow struct {
+	// Days are names of Weekdays, every day when empty
+	Days []string `json:"days,omitempty"`
+	// Start and End are times of day such as "18:00"
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Timezone is the IANA name of the time zone of Start and End, UTC when
+	// empty
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Matches reports whether the freeze applies to the given config.
+func (freeze Freeze) Matches(tenant, environment, schema, name string) bool {
+	for _, match := range [][2]string{
+		{freeze.Tenant, tenant},
+		{freeze.Environment, environment},
+		{freeze.Schema, schema},
+		{freeze.NamePattern, name},
+	} {
+		if matched, err := path.Match(match[0], match[1]); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ActiveAt reports whether the freeze locks its configs at the given time.
+func (freeze Freeze) ActiveAt(at time.Time) bool {
+	return freeze.Window == nil || freeze.Window.Contains(at)
+}
+
+// Contains reports whether at falls into the window. Windows with invalid
+// times or time zones never contain anything.
+func (window FreezeWindow) Contains(at time.Time) bool {
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", window.End)
+	if err != nil {
+		return false
+	}
+	location, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	from := start.Hour()*60 + start.Minute()
+	until := end.Hour()*60 + end.Minute()
+
+	if from < until {
+		return window.on(local.Weekday()) && minute >= from && minute < until
+	}
+
+	// The window runs past midnight, or all day long when it ends when it
+	// starts
+	return (window.on(local.Weekday()) && minute >= from) ||
+		(window.on(local.AddDate(0, 0, -1).Weekday()) && minute < until)
+}
+
+func (window FreezeWindow) on(day time.Weekday) bool {
+	return len(window.Days) == 0 || slices.Contains(window.Days, Weekdays[day])
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// RoleBinding grants a role to a principal on the configs of a tenant whose
+// schema and name match the given glob patterns.
+type RoleBinding struct {
+	ID          int64
+	Tenant      string
+	Principal   string
+	Role        string
+	Schema      string
+	NamePattern string
+	CreatedAt   time.Time
+	CreatedBy   string
+}
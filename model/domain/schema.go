@@ -1,16 +1,63 @@
 package domain
 
 import (
+	"config-service/tenant"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // Directory to store all schemas
 var SchemaDir = "schemas"
 
-// Schemas holds schema name -> JSON schema string
-var Schemas = map[string]string{}
+// Schemas holds tenant -> schema name -> JSON schema string. Files at the
+// top of SchemaDir belong to the default tenant, files in a subdirectory to
+// the tenant named like it.
+var Schemas = map[string]map[string]string{}
+
+// LookupSchema returns the JSON schema name of tenant.
+func LookupSchema(tenantName, name string) (string, bool) {
+	schema, ok := Schemas[tenantName][name]
+	return schema, ok
+}
+
+// SchemaNames returns the schema names of tenant, sorted.
+func SchemaNames(tenantName string) []string {
+	names := make([]string, 0, len(Schemas[tenantName]))
+	for name := range Schemas[tenantName] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tenants returns the tenants having schemas, sorted.
+func Tenants() []string {
+	tenants := make([]string, 0, len(Schemas))
+	for tenantName := range Schemas {
+		tenants = append(tenants, tenantName)
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// QualifiedSchemaName prefixes name with its tenant, unless it is the
+// default tenant.
+func QualifiedSchemaName(tenantName, name string) string {
+	if tenantName == tenant.Default {
+		return name
+	}
+	return tenantName + "/" + name
+}
+
+// SchemaPath returns the file of the schema name of tenant.
+func SchemaPath(tenantName, name string) string {
+	if tenantName == tenant.Default {
+		return filepath.Join(SchemaDir, name)
+	}
+	return filepath.Join(SchemaDir, tenantName, name)
+}
 
 // LoadSchemas loads all JSON schema files from a given directory into Schemas map
 func LoadSchemas(dir string) error {
@@ -19,6 +66,26 @@ func LoadSchemas(dir string) error {
 		return fmt.Errorf("failed to read schema directory: %w", err)
 	}
 
+	for _, file := range files {
+		if file.IsDir() {
+			if !tenant.Valid(file.Name()) {
+				continue
+			}
+			if err := loadTenantSchemas(filepath.Join(dir, file.Name()), file.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return loadTenantSchemas(dir, tenant.Default)
+}
+
+func loadTenantSchemas(dir, tenantName string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read schema directory: %w", err)
+	}
+
 	for _, file := range files {
 		if file.IsDir() {
 			continue
@@ -33,9 +100,13 @@ func LoadSchemas(dir string) error {
 			return fmt.Errorf("failed to read schema file %s: %w", file.Name(), err)
 		}
 
+		if Schemas[tenantName] == nil {
+			Schemas[tenantName] = map[string]string{}
+		}
+
 		// Use the file name (without .json) as the schema key
 		key := file.Name()[:len(file.Name())-len(filepath.Ext(file.Name()))]
-		Schemas[key] = string(content)
+		Schemas[tenantName][key] = string(content)
 	}
 
 	return nil
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Variable is a value that ${name} placeholders in the configs of an
+// environment are rendered with.
+type Variable struct {
+	Tenant      string
+	Environment string
+	Name        string
+	Value       interface{}
+	UpdatedAt   time.Time
+	UpdatedBy   string
+}
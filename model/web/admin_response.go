@@ -0,0 +1,32 @@
+package web
+
+type SchemaValidationResult struct {
+	Tenant string `json:"tenant"`
+	Name   string `json:"name"`
+	Valid  bool   `json:"valid"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ConfigValidationResult struct {
+	Tenant      string `json:"tenant"`
+	Environment string `json:"environment"`
+	Schema      string `json:"schema"`
+	Name        string `json:"name"`
+	Version     int    `json:"version"`
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+}
+
+type CompactResult struct {
+	Deleted int `json:"deleted"`
+	Skipped int `json:"skipped"` // Frozen configs whose versions were kept
+}
+
+type RotateSecretsResult struct {
+	Rotated int `json:"rotated"`
+}
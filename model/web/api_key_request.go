@@ -0,0 +1,8 @@
+package web
+
+type APIKeyCreateRequest struct {
+	Name  string `json:"name" validate:"required,max=64"`
+	Admin bool   `json:"admin"`
+	// Tenant binds the key to one tenant, empty keys may use every tenant
+	Tenant string `json:"tenant,omitempty"`
+}
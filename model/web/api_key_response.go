@@ -0,0 +1,21 @@
+package web
+
+import "time"
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Admin      bool       `json:"admin"`
+	Tenant     string     `json:"tenant,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeyCreateResponse is the only response that contains the key itself,
+// which is not stored and cannot be shown again.
+type APIKeyCreateResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
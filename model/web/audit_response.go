@@ -0,0 +1,31 @@
+package web
+
+import "time"
+
+type AuditEventResponse struct {
+	ID          int64     `json:"id"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	Actor       string    `json:"actor"`
+	Source      string    `json:"source,omitempty"`
+	Action      string    `json:"action"`
+	Outcome     string    `json:"outcome"`
+	Tenant      string    `json:"tenant,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	Schema      string    `json:"schema,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Version     int       `json:"version,omitempty"`
+	BeforeHash  string    `json:"before_hash,omitempty"`
+	AfterHash   string    `json:"after_hash,omitempty"`
+	Detail      string    `json:"detail,omitempty"`
+	PrevHash    string    `json:"prev_hash"`
+	Hash        string    `json:"hash"`
+}
+
+// AuditVerifyResponse reports the first entry whose hash or link to the
+// previous entry does not match.
+type AuditVerifyResponse struct {
+	Valid          bool   `json:"valid"`
+	Checked        int    `json:"checked"`
+	FirstInvalidID int64  `json:"first_invalid_id,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
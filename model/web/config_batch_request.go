@@ -0,0 +1,20 @@
+package web
+
+type ConfigBatchRequest struct {
+	Items []ConfigBatchItem `json:"items" validate:"required,min=1,max=100,dive"`
+	// Override is the reason for changing configs despite active freezes,
+	// passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
+}
+
+// ConfigBatchItem is the new version of one config of a batch, created when
+// the config does not exist yet.
+type ConfigBatchItem struct {
+	Schema string                 `json:"schema" validate:"required"`
+	Name   string                 `json:"name" validate:"required"`
+	Data   map[string]interface{} `json:"data" validate:"required"`
+	// ExpectedVersion fails the batch unless it is the latest version of the
+	// config, 0 expecting that the config does not exist yet and nil
+	// skipping the check
+	ExpectedVersion *int `json:"expected_version,omitempty" validate:"omitempty,min=0"`
+}
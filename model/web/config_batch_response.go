@@ -0,0 +1,7 @@
+package web
+
+type ConfigBatchResponse struct {
+	Environment string `json:"environment,omitempty"`
+	// Configs holds the new versions in the order of the items
+	Configs []ConfigResponse `json:"configs"`
+}
@@ -0,0 +1,13 @@
+package web
+
+type ConfigBulkFetchRequest struct {
+	// Selectors are schema/name for the latest version of a config or
+	// schema/name@version for a given one
+	Selectors []string `json:"selectors" validate:"required,min=1,max=100"`
+	// Resolve replaces references to other configs by their values and
+	// renders placeholders with variables, unless it is false
+	Resolve *bool `json:"resolve,omitempty"`
+	// Strict fails the configs using undefined variables instead of leaving
+	// their placeholders as they are
+	Strict bool `json:"strict,omitempty"`
+}
@@ -0,0 +1,17 @@
+package web
+
+type ConfigBulkResponse struct {
+	Environment string `json:"environment,omitempty"`
+	// Results holds a result per selector, in the order of the selectors
+	Results []ConfigBulkResult `json:"results"`
+}
+
+// ConfigBulkResult is either the config a selector selected or the error
+// fetching it failed with.
+type ConfigBulkResult struct {
+	Selector string          `json:"selector"`
+	Config   *ConfigResponse `json:"config,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// Code is the HTTP status code a single fetch would have failed with
+	Code int `json:"code,omitempty"`
+}
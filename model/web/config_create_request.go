@@ -1,5 +1,9 @@
 package web
 
+import "time"
+
 type ConfigCreateRequest struct {
 	Data map[string]interface{} `json:"data" validate:"required"` // The configuration JSON object
+	// EffectiveAt schedules the activation of the version, now when nil
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
 }
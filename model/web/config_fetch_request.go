@@ -3,4 +3,15 @@ package web
 type ConfigFetchRequest struct {
 	// Version is optional
 	Version *int `json:"version,omitempty"`
+	// Layers adds the unmerged layers of an inheriting config to the response
+	Layers bool `json:"layers,omitempty"`
+	// Resolve replaces references to other configs by their values and
+	// renders placeholders with variables, unless it is false
+	Resolve *bool `json:"resolve,omitempty"`
+	// Strict fails fetches of configs using undefined variables instead of
+	// leaving their placeholders as they are
+	Strict bool `json:"strict,omitempty"`
+	// Reveal returns the values marked secret by the schema instead of
+	// masking them, it requires the approver role
+	Reveal bool `json:"reveal,omitempty"`
 }
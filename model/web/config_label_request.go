@@ -0,0 +1,8 @@
+package web
+
+type ConfigLabelRequest struct {
+	Version int `validate:"required,min=1" json:"version"` // Version the label names
+	// Override is the reason for moving the label despite active freezes,
+	// passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
+}
@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+// ConfigLabelResponse is a label naming a version of a config.
+type ConfigLabelResponse struct {
+	Environment string    `json:"environment"`
+	Schema      string    `json:"schema"`
+	Name        string    `json:"name"`
+	Label       string    `json:"label"`
+	Version     int       `json:"version"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+}
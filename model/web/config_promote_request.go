@@ -0,0 +1,12 @@
+package web
+
+type ConfigPromoteRequest struct {
+	From    string `json:"from" validate:"required"`          // Source environment
+	Version int    `json:"version" validate:"min=0"`          // Source version, latest when 0
+	Label   string `json:"label,omitempty" validate:"max=63"` // Label naming the source version instead of Version
+	To      string `json:"to" validate:"required"`            // Target environment
+	DryRun  bool   `json:"dry_run"`                           // Only report the changes
+	// Override is the reason for changing the target despite active
+	// freezes, passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
+}
@@ -0,0 +1,13 @@
+package web
+
+import "config-service/diff"
+
+type ConfigPromoteResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Config is the promoted version, or the version it would become on dry runs
+	Config ConfigResponse `json:"config"`
+	// Previous is the latest version of the target before the promotion
+	Previous *ConfigResponse `json:"previous,omitempty"`
+	// Changes compares Previous with Config
+	Changes []diff.Change `json:"changes"`
+}
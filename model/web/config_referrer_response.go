@@ -0,0 +1,12 @@
+package web
+
+// ConfigReferrerResponse is a reference of a config to another one.
+type ConfigReferrerResponse struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Version is the latest version of the referring config
+	Version int `json:"version"`
+	// Path is the JSON pointer of the reference within the referring config
+	Path string `json:"path"`
+	Ref  string `json:"ref"`
+}
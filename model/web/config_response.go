@@ -3,14 +3,24 @@ package web
 import "time"
 
 type ConfigResponse struct {
-	Schema    string                 `json:"schema"`
-	Name      string                 `json:"name"`
-	Version   int                    `json:"version"`
-	Data      map[string]interface{} `json:"data"` // raw JSON
-	CreatedAt time.Time              `json:"created_at"`
+	Tenant      string                 `json:"tenant,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Schema      string                 `json:"schema"`
+	Name        string                 `json:"name"`
+	Version     int                    `json:"version"`
+	Data        map[string]interface{} `json:"data"` // raw JSON
+	CreatedAt   time.Time              `json:"created_at"`
+	CreatedBy   string                 `json:"created_by,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	EffectiveAt *time.Time             `json:"effective_at,omitempty"`
+	CancelledAt *time.Time             `json:"cancelled_at,omitempty"`
+	// Layers holds the config and its ancestors as stored, nearest first,
+	// when requested on fetch
+	Layers []ConfigResponse `json:"layers,omitempty"`
 }
 
 type ConfigResponses struct {
+	Environment    string           `json:"environment,omitempty"`
 	Schema         string           `json:"schema"`
 	Name           string           `json:"name"`
 	ConfigVersions []ConfigResponse `json:"configVersions"`
@@ -2,4 +2,7 @@ package web
 
 type ConfigRollbackRequest struct {
 	Version int `validate:"required" json:"version"`
+	// Override is the reason for changing the config despite active
+	// freezes, passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
 }
@@ -1,5 +1,12 @@
 package web
 
+import "time"
+
 type ConfigUpdateRequest struct {
 	Data map[string]interface{} `json:"data" validate:"required"`
+	// EffectiveAt schedules the activation of the version, now when nil
+	EffectiveAt *time.Time `json:"effective_at,omitempty"`
+	// Override is the reason for changing the config despite active
+	// freezes, passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
 }
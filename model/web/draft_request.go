@@ -0,0 +1,23 @@
+package web
+
+type DraftCreateRequest struct {
+	Data map[string]interface{} `json:"data" validate:"required"`
+	// Comment tells reviewers what the draft changes and why
+	Comment string `json:"comment,omitempty" validate:"max=1000"`
+}
+
+type DraftReviewRequest struct {
+	Comment string `json:"comment,omitempty" validate:"max=1000"`
+	// Override is the reason for publishing an approved draft despite
+	// active freezes, passed as the override query parameter
+	Override string `json:"-" validate:"max=1000"`
+}
+
+// DraftListRequest selects drafts of the environment of the caller, by
+// config when Schema and Name are set.
+type DraftListRequest struct {
+	Schema string
+	Name   string
+	// Status is pending when empty
+	Status string
+}
@@ -0,0 +1,36 @@
+package web
+
+import (
+	"config-service/diff"
+	"time"
+)
+
+type DraftResponse struct {
+	ID               int64                  `json:"id"`
+	Tenant           string                 `json:"tenant"`
+	Environment      string                 `json:"environment"`
+	Schema           string                 `json:"schema"`
+	Name             string                 `json:"name"`
+	BaseVersion      int                    `json:"base_version"`
+	Data             map[string]interface{} `json:"data"`
+	Comment          string                 `json:"comment,omitempty"`
+	Status           string                 `json:"status"`
+	PublishedVersion int                    `json:"published_version,omitempty"`
+	// RequiredApprovals is the number of approvals publishing the draft
+	RequiredApprovals int                   `json:"required_approvals"`
+	Approvals         int                   `json:"approvals"`
+	Reviews           []DraftReviewResponse `json:"reviews"`
+	CreatedAt         time.Time             `json:"created_at"`
+	CreatedBy         string                `json:"created_by,omitempty"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+	// Changes compares the base version with the draft, only set for
+	// single drafts
+	Changes []diff.Change `json:"changes,omitempty"`
+}
+
+type DraftReviewResponse struct {
+	Reviewer  string    `json:"reviewer,omitempty"`
+	Decision  string    `json:"decision"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,6 @@
+package web
+
+type EnvironmentResponse struct {
+	Name    string `json:"name"`
+	Configs int    `json:"configs"` // number of configs with a version in the environment
+}
@@ -0,0 +1,23 @@
+package web
+
+// FreezeCreateRequest freezes the configs matching the glob patterns, every
+// tenant, environment and name when the pattern is empty.
+type FreezeCreateRequest struct {
+	Tenant      string `json:"tenant"`
+	Environment string `json:"environment"`
+	Schema      string `json:"schema" validate:"required"`
+	NamePattern string `json:"name_pattern"`
+	Reason      string `json:"reason" validate:"required,max=1000"`
+	// Window makes the freeze recur every week, the freeze is a manual lock
+	// until it is deleted when nil
+	Window *FreezeWindow `json:"window,omitempty"`
+}
+
+// FreezeWindow recurs every week, from Start to End on each of Days, the
+// next day when End is before Start.
+type FreezeWindow struct {
+	Days     []string `json:"days,omitempty" validate:"dive,oneof=mon tue wed thu fri sat sun"` // Every day when empty
+	Start    string   `json:"start" validate:"required" example:"18:00"`
+	End      string   `json:"end" validate:"required" example:"06:00"`
+	Timezone string   `json:"timezone,omitempty" example:"Europe/Berlin"` // UTC when empty
+}
@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+type FreezeResponse struct {
+	ID          int64         `json:"id"`
+	Tenant      string        `json:"tenant"`
+	Environment string        `json:"environment"`
+	Schema      string        `json:"schema"`
+	NamePattern string        `json:"name_pattern"`
+	Reason      string        `json:"reason"`
+	Window      *FreezeWindow `json:"window,omitempty"`
+	Active      bool          `json:"active"` // Whether the freeze locks its configs now
+	CreatedAt   time.Time     `json:"created_at"`
+	CreatedBy   string        `json:"created_by,omitempty"`
+}
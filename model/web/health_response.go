@@ -0,0 +1,45 @@
+package web
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	Revision  string `json:"revision,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+type DatabaseStatus struct {
+	OpenConnections   int    `json:"open_connections"`
+	InUse             int    `json:"in_use"`
+	Idle              int    `json:"idle"`
+	WaitCount         int64  `json:"wait_count"`
+	WaitDuration      string `json:"wait_duration"`
+	Configs           int    `json:"configs"`
+	Versions          int    `json:"versions"`
+	PendingMigrations int    `json:"pending_migrations"`
+}
+
+type StatusResponse struct {
+	Status      string         `json:"status"`
+	Uptime      string         `json:"uptime"`
+	Build       BuildInfo      `json:"build"`
+	Database    DatabaseStatus `json:"database"`
+	SchemaCount int            `json:"schema_count"`
+	Schemas     []string       `json:"schemas"`
+	Checks      []HealthCheck  `json:"checks"`
+}
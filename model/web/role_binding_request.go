@@ -0,0 +1,8 @@
+package web
+
+type RoleBindingCreateRequest struct {
+	Principal   string `json:"principal" validate:"required,max=256"`
+	Role        string `json:"role" validate:"required,oneof=reader writer approver admin"`
+	Schema      string `json:"schema" validate:"required"`
+	NamePattern string `json:"name_pattern"`
+}
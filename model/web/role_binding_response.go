@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+type RoleBindingResponse struct {
+	ID          int64     `json:"id"`
+	Tenant      string    `json:"tenant"`
+	Principal   string    `json:"principal"`
+	Role        string    `json:"role"`
+	Schema      string    `json:"schema"`
+	NamePattern string    `json:"name_pattern"`
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+}
@@ -0,0 +1,5 @@
+package web
+
+type VariableSetRequest struct {
+	Value interface{} `json:"value" validate:"required"`
+}
@@ -0,0 +1,11 @@
+package web
+
+import "time"
+
+type VariableResponse struct {
+	Environment string      `json:"environment"`
+	Name        string      `json:"name"`
+	Value       interface{} `json:"value"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	UpdatedBy   string      `json:"updated_by,omitempty"`
+}
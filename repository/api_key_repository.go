@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, key domain.APIKey) domain.APIKey
+	FindByHash(ctx context.Context, tx *sql.Tx, keyHash string) (domain.APIKey, error)
+	FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.APIKey, error)
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.APIKey
+	Revoke(ctx context.Context, tx *sql.Tx, id string, revokedAt time.Time) error
+	TouchLastUsed(ctx context.Context, tx *sql.Tx, id string, usedAt time.Time)
+}
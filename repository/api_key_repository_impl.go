@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const apiKeyColumns = "id, name, prefix, key_hash, admin, tenant, created_at, last_used_at, revoked_at"
+
+type APIKeyRepositoryImpl struct{}
+
+func NewAPIKeyRepository() APIKeyRepository {
+	return &APIKeyRepositoryImpl{}
+}
+
+func scanAPIKey(row interface{ Scan(...any) error }) (domain.APIKey, error) {
+	key := domain.APIKey{}
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.KeyHash, &key.Admin, &key.Tenant, &key.CreatedAt, &lastUsedAt, &revokedAt)
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, err
+}
+
+func (repository *APIKeyRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, key domain.APIKey) domain.APIKey {
+	defer metrics.ObserveQuery("APIKey.Create", time.Now())
+
+	SQL := "INSERT INTO api_keys (id, name, prefix, key_hash, admin, tenant, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.Create", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, key.ID, key.Name, key.Prefix, key.KeyHash, key.Admin, key.Tenant, key.CreatedAt)
+	helper.PanicIfError(err)
+
+	return key
+}
+
+func (repository *APIKeyRepositoryImpl) FindByHash(ctx context.Context, tx *sql.Tx, keyHash string) (domain.APIKey, error) {
+	defer metrics.ObserveQuery("APIKey.FindByHash", time.Now())
+
+	SQL := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = ?"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.FindByHash", SQL)
+	defer tracing.End(span)
+
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, SQL, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return key, errors.New("api key is not found")
+	}
+	helper.PanicIfError(err)
+
+	return key, nil
+}
+
+func (repository *APIKeyRepositoryImpl) FindByName(ctx context.Context, tx *sql.Tx, name string) (domain.APIKey, error) {
+	defer metrics.ObserveQuery("APIKey.FindByName", time.Now())
+
+	SQL := "SELECT " + apiKeyColumns + " FROM api_keys WHERE name = ?"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.FindByName", SQL)
+	defer tracing.End(span)
+
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, SQL, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return key, errors.New("api key is not found")
+	}
+	helper.PanicIfError(err)
+
+	return key, nil
+}
+
+func (repository *APIKeyRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.APIKey {
+	defer metrics.ObserveQuery("APIKey.FindAll", time.Now())
+
+	SQL := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY created_at ASC, name ASC"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		helper.PanicIfError(err)
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (repository *APIKeyRepositoryImpl) Revoke(ctx context.Context, tx *sql.Tx, id string, revokedAt time.Time) error {
+	defer metrics.ObserveQuery("APIKey.Revoke", time.Now())
+
+	SQL := "UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.Revoke", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, revokedAt, id)
+	helper.PanicIfError(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	if affected == 0 {
+		return errors.New("api key is not found or already revoked")
+	}
+
+	return nil
+}
+
+func (repository *APIKeyRepositoryImpl) TouchLastUsed(ctx context.Context, tx *sql.Tx, id string, usedAt time.Time) {
+	defer metrics.ObserveQuery("APIKey.TouchLastUsed", time.Now())
+
+	SQL := "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+	ctx, span := tracing.StartQuery(ctx, "APIKeyRepository.TouchLastUsed", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, usedAt, id)
+	helper.PanicIfError(err)
+}
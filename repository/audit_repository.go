@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type AuditRepository interface {
+	Insert(ctx context.Context, tx *sql.Tx, event domain.AuditEvent) domain.AuditEvent
+	LastHash(ctx context.Context, tx *sql.Tx) string
+	Find(ctx context.Context, tx *sql.Tx, filter domain.AuditFilter) []domain.AuditEvent
+	// Walk calls fn with every event in insertion order until it returns false.
+	Walk(ctx context.Context, tx *sql.Tx, fn func(event domain.AuditEvent) bool)
+	// LatestSchemaHashes returns the hash of the last recorded content of
+	// every schema that was not removed, by tenant and schema name.
+	LatestSchemaHashes(ctx context.Context, tx *sql.Tx) map[string]map[string]string
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+const auditColumns = "id, occurred_at, actor, source, action, outcome, tenant, environment, schema, name, version, before_hash, after_hash, detail, prev_hash, hash"
+
+type AuditRepositoryImpl struct{}
+
+func NewAuditRepository() AuditRepository {
+	return &AuditRepositoryImpl{}
+}
+
+func scanAuditEvent(rows *sql.Rows) domain.AuditEvent {
+	event := domain.AuditEvent{}
+	err := rows.Scan(&event.ID, &event.OccurredAt, &event.Actor, &event.Source, &event.Action, &event.Outcome, &event.Tenant, &event.Environment, &event.Schema, &event.Name,
+		&event.Version, &event.BeforeHash, &event.AfterHash, &event.Detail, &event.PrevHash, &event.Hash)
+	helper.PanicIfError(err)
+	return event
+}
+
+func (repository *AuditRepositoryImpl) Insert(ctx context.Context, tx *sql.Tx, event domain.AuditEvent) domain.AuditEvent {
+	defer metrics.ObserveQuery("Audit.Insert", time.Now())
+
+	SQL := "INSERT INTO audit_log (occurred_at, actor, source, action, outcome, tenant, environment, schema, name, version, before_hash, after_hash, detail, prev_hash, hash) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "AuditRepository.Insert", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, event.OccurredAt, event.Actor, event.Source, event.Action, event.Outcome, event.Tenant, event.Environment, event.Schema, event.Name,
+		event.Version, event.BeforeHash, event.AfterHash, event.Detail, event.PrevHash, event.Hash)
+	helper.PanicIfError(err)
+
+	event.ID, err = result.LastInsertId()
+	helper.PanicIfError(err)
+
+	return event
+}
+
+func (repository *AuditRepositoryImpl) LastHash(ctx context.Context, tx *sql.Tx) string {
+	defer metrics.ObserveQuery("Audit.LastHash", time.Now())
+
+	SQL := "SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1"
+	ctx, span := tracing.StartQuery(ctx, "AuditRepository.LastHash", SQL)
+	defer tracing.End(span)
+
+	var hash string
+	err := tx.QueryRowContext(ctx, SQL).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return ""
+	}
+	helper.PanicIfError(err)
+
+	return hash
+}
+
+func (repository *AuditRepositoryImpl) Find(ctx context.Context, tx *sql.Tx, filter domain.AuditFilter) []domain.AuditEvent {
+	defer metrics.ObserveQuery("Audit.Find", time.Now())
+
+	var conditions []string
+	var args []interface{}
+	for column, value := range map[string]string{
+		"actor":       filter.Actor,
+		"action":      filter.Action,
+		"outcome":     filter.Outcome,
+		"tenant":      filter.Tenant,
+		"environment": filter.Environment,
+		"schema":      filter.Schema,
+		"name":        filter.Name,
+	} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, filter.Until.UTC())
+	}
+	if filter.BeforeID > 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	SQL := "SELECT " + auditColumns + " FROM audit_log"
+	if len(conditions) > 0 {
+		SQL += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	SQL += " ORDER BY id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	ctx, span := tracing.StartQuery(ctx, "AuditRepository.Find", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	events := []domain.AuditEvent{}
+	for rows.Next() {
+		events = append(events, scanAuditEvent(rows))
+	}
+
+	return events
+}
+
+func (repository *AuditRepositoryImpl) Walk(ctx context.Context, tx *sql.Tx, fn func(event domain.AuditEvent) bool) {
+	defer metrics.ObserveQuery("Audit.Walk", time.Now())
+
+	SQL := "SELECT " + auditColumns + " FROM audit_log ORDER BY id ASC"
+	ctx, span := tracing.StartQuery(ctx, "AuditRepository.Walk", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	for rows.Next() {
+		if !fn(scanAuditEvent(rows)) {
+			return
+		}
+	}
+	helper.PanicIfError(rows.Err())
+}
+
+func (repository *AuditRepositoryImpl) LatestSchemaHashes(ctx context.Context, tx *sql.Tx) map[string]map[string]string {
+	defer metrics.ObserveQuery("Audit.LatestSchemaHashes", time.Now())
+
+	// Schema events recorded before tenants were introduced have none
+	SQL := `SELECT COALESCE(NULLIF(tenant, ''), ?), schema, action, after_hash FROM audit_log WHERE id IN (
+		SELECT MAX(id) FROM audit_log WHERE action IN (?, ?, ?) GROUP BY COALESCE(NULLIF(tenant, ''), ?), schema)`
+	ctx, span := tracing.StartQuery(ctx, "AuditRepository.LatestSchemaHashes", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, tenant.Default, domain.AuditSchemaCreated, domain.AuditSchemaChanged, domain.AuditSchemaRemoved, tenant.Default)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	hashes := map[string]map[string]string{}
+	for rows.Next() {
+		var tenantName, schema, action, hash string
+		err := rows.Scan(&tenantName, &schema, &action, &hash)
+		helper.PanicIfError(err)
+
+		if action != domain.AuditSchemaRemoved {
+			if hashes[tenantName] == nil {
+				hashes[tenantName] = map[string]string{}
+			}
+			hashes[tenantName][schema] = hash
+		}
+	}
+
+	return hashes
+}
@@ -4,11 +4,42 @@ import (
 	"config-service/model/domain"
 	"context"
 	"database/sql"
+	"time"
 )
 
 type ConfigRepository interface {
+	// GetLatest returns the newest version of a config, including scheduled
+	// and cancelled versions, which the next version follows.
 	GetLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error)
+	// GetActive returns the newest version of a config active at the given
+	// time, skipping cancelled versions and those scheduled after it.
+	GetActive(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord, at time.Time) (domain.ConfigRecord, error)
+	// GetByVersion returns config.Version of a config, the version active now
+	// for version 0.
 	GetByVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error)
 	ListVersions(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord
 	CreateNewVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) domain.ConfigRecord
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.ConfigRecord
+	DeleteVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord)
+	// ReplaceData overwrites the data of a stored version. Versions are
+	// immutable, it only re-encrypts their secret values.
+	ReplaceData(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord)
+	// CancelVersion marks a scheduled version as cancelled at
+	// config.CancelledAt.
+	CancelVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord)
+	// ListActivations returns the versions of every tenant and environment
+	// becoming active after after and until until, cancelled ones excluded.
+	ListActivations(ctx context.Context, tx *sql.Tx, after, until time.Time) []domain.ConfigRecord
+	Stats(ctx context.Context, tx *sql.Tx) domain.ConfigStats
+	// CountConfigs counts the configs of tenant in all its environments, not
+	// their versions.
+	CountConfigs(ctx context.Context, tx *sql.Tx, tenant string) int
+	// LatestPerEnvironment returns the version of a config active now in
+	// every environment having one, ignoring config.Environment.
+	LatestPerEnvironment(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord
+	// CountPerEnvironment counts the configs of tenant by environment.
+	CountPerEnvironment(ctx context.Context, tx *sql.Tx, tenant string) map[string]int
+	// ListLatest returns the version active now of every config in the tenant
+	// and environment of config, limited to config.Schema unless it is empty.
+	ListLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord
 }
@@ -2,33 +2,90 @@ package repository
 
 import (
 	"config-service/helper"
+	"config-service/metrics"
 	"config-service/model/domain"
+	"config-service/tracing"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
+const configColumns = "tenant, environment, schema, name, version, data, created_at, created_by, metadata, effective_at, cancelled_at"
+
+// activeVersion restricts a query to the versions active at the time bound
+// to its parameter. Activation times are stored in UTC, so their text
+// compares like the times.
+const activeVersion = "cancelled_at IS NULL AND (effective_at IS NULL OR effective_at <= ?)"
+
 type ConfigRepositoryImpl struct{}
 
 func NewConfigRepository() ConfigRepository {
 	return &ConfigRepositoryImpl{}
 }
 
+func scanConfigRecord(rows *sql.Rows) domain.ConfigRecord {
+	var dataStr, metadataStr string
+	var effectiveAt, cancelledAt sql.NullTime
+	configRecord := domain.ConfigRecord{}
+	err := rows.Scan(&configRecord.Tenant, &configRecord.Environment, &configRecord.Schema, &configRecord.Name, &configRecord.Version, &dataStr,
+		&configRecord.CreatedAt, &configRecord.CreatedBy, &metadataStr, &effectiveAt, &cancelledAt)
+	helper.PanicIfError(err)
+	if effectiveAt.Valid {
+		configRecord.EffectiveAt = &effectiveAt.Time
+	}
+	if cancelledAt.Valid {
+		configRecord.CancelledAt = &cancelledAt.Time
+	}
+
+	err = json.Unmarshal([]byte(dataStr), &configRecord.Data)
+	helper.PanicIfError(err)
+
+	err = json.Unmarshal([]byte(metadataStr), &configRecord.Metadata)
+	helper.PanicIfError(err)
+	if len(configRecord.Metadata) == 0 {
+		configRecord.Metadata = nil
+	}
+
+	return configRecord
+}
+
 func (repository *ConfigRepositoryImpl) GetLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error) {
-	SQL := "SELECT schema, name, version, data, created_at FROM configs WHERE schema = ? AND name = ? ORDER BY version DESC LIMIT 1"
-	rows, err := tx.QueryContext(ctx, SQL, config.Schema, config.Name)
+	defer metrics.ObserveQuery("GetLatest", time.Now())
+
+	SQL := "SELECT " + configColumns + " FROM configs WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? ORDER BY version DESC LIMIT 1"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.GetLatest", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name)
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	var dataStr string
 	configRecord := domain.ConfigRecord{}
 	if rows.Next() {
-		err = rows.Scan(&configRecord.Schema, &configRecord.Name, &configRecord.Version, &dataStr, &configRecord.CreatedAt)
-		helper.PanicIfError(err)
+		configRecord = scanConfigRecord(rows)
+	} else {
+		return configRecord, errors.New("requested config is not found")
+	}
 
-		err = json.Unmarshal([]byte(dataStr), &configRecord.Data)
-		helper.PanicIfError(err)
+	return configRecord, nil
+}
+
+func (repository *ConfigRepositoryImpl) GetActive(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord, at time.Time) (domain.ConfigRecord, error) {
+	defer metrics.ObserveQuery("GetActive", time.Now())
+
+	SQL := "SELECT " + configColumns + " FROM configs WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND " + activeVersion + " ORDER BY version DESC LIMIT 1"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.GetActive", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name, at.UTC())
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	configRecord := domain.ConfigRecord{}
+	if rows.Next() {
+		configRecord = scanConfigRecord(rows)
 	} else {
 		return configRecord, errors.New("requested config is not found")
 	}
@@ -37,25 +94,24 @@ func (repository *ConfigRepositoryImpl) GetLatest(ctx context.Context, tx *sql.T
 }
 
 func (repository *ConfigRepositoryImpl) GetByVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error) {
+	defer metrics.ObserveQuery("GetByVersion", time.Now())
 
 	if config.Version == 0 {
-		configRecord, err := repository.GetLatest(ctx, tx, config)
+		configRecord, err := repository.GetActive(ctx, tx, config, time.Now())
 		return configRecord, err
 	}
 
-	SQL := "SELECT schema, name, version, data, created_at FROM configs WHERE schema = ? AND name = ? AND version = ? ORDER BY version DESC LIMIT 1"
-	rows, err := tx.QueryContext(ctx, SQL, config.Schema, config.Name, config.Version)
+	SQL := "SELECT " + configColumns + " FROM configs WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND version = ? ORDER BY version DESC LIMIT 1"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.GetByVersion", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name, config.Version)
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	var dataStr string
 	configRecord := domain.ConfigRecord{}
 	if rows.Next() {
-		err = rows.Scan(&configRecord.Schema, &configRecord.Name, &configRecord.Version, &dataStr, &configRecord.CreatedAt)
-		helper.PanicIfError(err)
-
-		err = json.Unmarshal([]byte(dataStr), &configRecord.Data)
-		helper.PanicIfError(err)
+		configRecord = scanConfigRecord(rows)
 	} else {
 		return configRecord, errors.New("requested version for specified config is not found")
 	}
@@ -64,37 +120,231 @@ func (repository *ConfigRepositoryImpl) GetByVersion(ctx context.Context, tx *sq
 }
 
 func (repository *ConfigRepositoryImpl) ListVersions(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	defer metrics.ObserveQuery("ListVersions", time.Now())
 
-	SQL := "SELECT schema, name, version, data, created_at FROM configs WHERE schema = ? AND name = ? ORDER BY version ASC"
-	rows, err := tx.QueryContext(ctx, SQL, config.Schema, config.Name)
+	SQL := "SELECT " + configColumns + " FROM configs WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? ORDER BY version ASC"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.ListVersions", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name)
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	var dataStr string
 	configRecords := []domain.ConfigRecord{}
 	for rows.Next() {
-		configRecord := domain.ConfigRecord{}
-		err = rows.Scan(&configRecord.Schema, &configRecord.Name, &configRecord.Version, &dataStr, &configRecord.CreatedAt)
-		helper.PanicIfError(err)
+		configRecords = append(configRecords, scanConfigRecord(rows))
+	}
+
+	return configRecords
+}
+
+func (repository *ConfigRepositoryImpl) CreateNewVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) domain.ConfigRecord {
+	defer metrics.ObserveQuery("CreateNewVersion", time.Now())
+
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.CreateNewVersion", "INSERT INTO configs")
+	defer tracing.End(span)
 
-		err = json.Unmarshal([]byte(dataStr), &configRecord.Data)
+	dataJSON, err := json.Marshal(config.Data)
+	helper.PanicIfError(err)
+
+	metadataJSON := []byte("{}")
+	if len(config.Metadata) > 0 {
+		metadataJSON, err = json.Marshal(config.Metadata)
 		helper.PanicIfError(err)
-		configRecords = append(configRecords, configRecord)
+	}
+
+	effectiveAt, cancelledAt := nullTime(config.EffectiveAt), nullTime(config.CancelledAt)
+	if config.CreatedAt.IsZero() {
+		SQL := "INSERT INTO configs (tenant, environment, schema, name, version, data, created_by, metadata, effective_at, cancelled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		_, err = tx.ExecContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name, config.Version, string(dataJSON), config.CreatedBy, string(metadataJSON),
+			effectiveAt, cancelledAt)
+	} else {
+		// Keep the original timestamp, e.g. when importing an export
+		SQL := "INSERT INTO configs (tenant, environment, schema, name, version, data, created_at, created_by, metadata, effective_at, cancelled_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		_, err = tx.ExecContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name, config.Version, string(dataJSON), config.CreatedAt, config.CreatedBy, string(metadataJSON),
+			effectiveAt, cancelledAt)
+	}
+	helper.PanicIfError(err)
+
+	metrics.VersionsCreated.WithLabelValues(config.Schema).Inc()
+
+	return config
+}
+
+func (repository *ConfigRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.ConfigRecord {
+	defer metrics.ObserveQuery("FindAll", time.Now())
+
+	SQL := "SELECT " + configColumns + " FROM configs ORDER BY tenant ASC, environment ASC, schema ASC, name ASC, version ASC"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	configRecords := []domain.ConfigRecord{}
+	for rows.Next() {
+		configRecords = append(configRecords, scanConfigRecord(rows))
 	}
 
 	return configRecords
 }
 
-func (repository *ConfigRepositoryImpl) CreateNewVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) domain.ConfigRecord {
+func (repository *ConfigRepositoryImpl) DeleteVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) {
+	defer metrics.ObserveQuery("DeleteVersion", time.Now())
+
+	SQL := "DELETE FROM configs WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND version = ?"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.DeleteVersion", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name, config.Version)
+	helper.PanicIfError(err)
+}
+
+func (repository *ConfigRepositoryImpl) ReplaceData(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) {
+	defer metrics.ObserveQuery("ReplaceData", time.Now())
 
 	dataJSON, err := json.Marshal(config.Data)
 	helper.PanicIfError(err)
 
-	SQL := "INSERT INTO configs (schema, name, version, data) VALUES (?, ?, ?, ?)"
-	_, err = tx.ExecContext(ctx, SQL, config.Schema, config.Name, config.Version, string(dataJSON))
+	SQL := "UPDATE configs SET data = ? WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND version = ?"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.ReplaceData", SQL)
+	defer tracing.End(span)
+
+	_, err = tx.ExecContext(ctx, SQL, string(dataJSON), config.Tenant, config.Environment, config.Schema, config.Name, config.Version)
 	helper.PanicIfError(err)
+}
+
+func (repository *ConfigRepositoryImpl) CancelVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) {
+	defer metrics.ObserveQuery("CancelVersion", time.Now())
+
+	SQL := "UPDATE configs SET cancelled_at = ? WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND version = ?"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.CancelVersion", SQL)
+	defer tracing.End(span)
 
+	_, err := tx.ExecContext(ctx, SQL, nullTime(config.CancelledAt), config.Tenant, config.Environment, config.Schema, config.Name, config.Version)
 	helper.PanicIfError(err)
+}
 
-	return config
+func (repository *ConfigRepositoryImpl) ListActivations(ctx context.Context, tx *sql.Tx, after, until time.Time) []domain.ConfigRecord {
+	defer metrics.ObserveQuery("ListActivations", time.Now())
+
+	SQL := "SELECT " + configColumns + ` FROM configs WHERE cancelled_at IS NULL AND effective_at > ? AND effective_at <= ?
+		ORDER BY effective_at ASC, version ASC`
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.ListActivations", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, after.UTC(), until.UTC())
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	configRecords := []domain.ConfigRecord{}
+	for rows.Next() {
+		configRecords = append(configRecords, scanConfigRecord(rows))
+	}
+
+	return configRecords
+}
+
+func (repository *ConfigRepositoryImpl) Stats(ctx context.Context, tx *sql.Tx) domain.ConfigStats {
+	defer metrics.ObserveQuery("Stats", time.Now())
+
+	SQL := "SELECT COUNT(DISTINCT tenant || '/' || environment || '/' || schema || '/' || name), COUNT(*) FROM configs"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.Stats", SQL)
+	defer tracing.End(span)
+
+	row := tx.QueryRowContext(ctx, SQL)
+
+	stats := domain.ConfigStats{}
+	err := row.Scan(&stats.Configs, &stats.Versions)
+	helper.PanicIfError(err)
+
+	return stats
+}
+
+func (repository *ConfigRepositoryImpl) CountConfigs(ctx context.Context, tx *sql.Tx, tenant string) int {
+	defer metrics.ObserveQuery("CountConfigs", time.Now())
+
+	SQL := "SELECT COUNT(DISTINCT environment || '/' || schema || '/' || name) FROM configs WHERE tenant = ?"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.CountConfigs", SQL)
+	defer tracing.End(span)
+
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, tenant).Scan(&count)
+	helper.PanicIfError(err)
+
+	return count
+}
+
+func (repository *ConfigRepositoryImpl) LatestPerEnvironment(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	defer metrics.ObserveQuery("LatestPerEnvironment", time.Now())
+
+	SQL := "SELECT " + configColumns + ` FROM configs c WHERE tenant = ? AND schema = ? AND name = ?
+		AND version = (SELECT MAX(version) FROM configs WHERE tenant = c.tenant AND environment = c.environment AND schema = c.schema AND name = c.name AND ` + activeVersion + `)
+		ORDER BY environment ASC`
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.LatestPerEnvironment", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Schema, config.Name, time.Now().UTC())
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	configRecords := []domain.ConfigRecord{}
+	for rows.Next() {
+		configRecords = append(configRecords, scanConfigRecord(rows))
+	}
+
+	return configRecords
+}
+
+func (repository *ConfigRepositoryImpl) ListLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	defer metrics.ObserveQuery("ListLatest", time.Now())
+
+	SQL := "SELECT " + configColumns + ` FROM configs c WHERE tenant = ? AND environment = ? AND (? = '' OR schema = ?)
+		AND version = (SELECT MAX(version) FROM configs WHERE tenant = c.tenant AND environment = c.environment AND schema = c.schema AND name = c.name AND ` + activeVersion + `)
+		ORDER BY schema ASC, name ASC`
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.ListLatest", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Schema, time.Now().UTC())
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	configRecords := []domain.ConfigRecord{}
+	for rows.Next() {
+		configRecords = append(configRecords, scanConfigRecord(rows))
+	}
+
+	return configRecords
+}
+
+func (repository *ConfigRepositoryImpl) CountPerEnvironment(ctx context.Context, tx *sql.Tx, tenant string) map[string]int {
+	defer metrics.ObserveQuery("CountPerEnvironment", time.Now())
+
+	SQL := "SELECT environment, COUNT(DISTINCT schema || '/' || name) FROM configs WHERE tenant = ? GROUP BY environment"
+	ctx, span := tracing.StartQuery(ctx, "ConfigRepository.CountPerEnvironment", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, tenant)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var environment string
+		var count int
+		err = rows.Scan(&environment, &count)
+		helper.PanicIfError(err)
+		counts[environment] = count
+	}
+
+	return counts
+}
+
+// nullTime stores optional times in UTC, so that they compare as text.
+func nullTime(at *time.Time) sql.NullTime {
+	if at == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: at.UTC(), Valid: true}
 }
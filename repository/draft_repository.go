@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type DraftRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, draft domain.Draft) domain.Draft
+	// FindByID returns a draft of an environment of a tenant with its
+	// reviews.
+	FindByID(ctx context.Context, tx *sql.Tx, tenant, environment string, id int64) (domain.Draft, error)
+	// FindAll returns the drafts selected by filter with their reviews,
+	// oldest first.
+	FindAll(ctx context.Context, tx *sql.Tx, filter domain.DraftFilter) []domain.Draft
+	// UpdateStatus stores the status and published version of draft.
+	UpdateStatus(ctx context.Context, tx *sql.Tx, draft domain.Draft)
+	AddReview(ctx context.Context, tx *sql.Tx, draftID int64, review domain.DraftReview) domain.DraftReview
+}
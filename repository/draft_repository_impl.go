@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const draftColumns = "id, tenant, environment, schema, name, base_version, data, comment, status, published_version, created_at, created_by, updated_at"
+
+type DraftRepositoryImpl struct{}
+
+func NewDraftRepository() DraftRepository {
+	return &DraftRepositoryImpl{}
+}
+
+func scanDraft(rows *sql.Rows) domain.Draft {
+	draft := domain.Draft{}
+	var data string
+	err := rows.Scan(&draft.ID, &draft.Tenant, &draft.Environment, &draft.Schema, &draft.Name, &draft.BaseVersion, &data,
+		&draft.Comment, &draft.Status, &draft.PublishedVersion, &draft.CreatedAt, &draft.CreatedBy, &draft.UpdatedAt)
+	helper.PanicIfError(err)
+
+	err = json.Unmarshal([]byte(data), &draft.Data)
+	helper.PanicIfError(err)
+
+	return draft
+}
+
+func (repository *DraftRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, draft domain.Draft) domain.Draft {
+	defer metrics.ObserveQuery("Draft.Save", time.Now())
+
+	data, err := json.Marshal(draft.Data)
+	helper.PanicIfError(err)
+
+	SQL := "INSERT INTO drafts (tenant, environment, schema, name, base_version, data, comment, status, created_at, created_by, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "DraftRepository.Save", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, draft.Tenant, draft.Environment, draft.Schema, draft.Name, draft.BaseVersion, string(data),
+		draft.Comment, draft.Status, draft.CreatedAt, draft.CreatedBy, draft.UpdatedAt)
+	helper.PanicIfError(err)
+
+	draft.ID, err = result.LastInsertId()
+	helper.PanicIfError(err)
+
+	return draft
+}
+
+func (repository *DraftRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, tenant, environment string, id int64) (domain.Draft, error) {
+	defer metrics.ObserveQuery("Draft.FindByID", time.Now())
+
+	SQL := "SELECT " + draftColumns + " FROM drafts WHERE tenant = ? AND environment = ? AND id = ?"
+	queryCtx, span := tracing.StartQuery(ctx, "DraftRepository.FindByID", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(queryCtx, SQL, tenant, environment, id)
+	helper.PanicIfError(err)
+
+	var draft domain.Draft
+	found := rows.Next()
+	if found {
+		draft = scanDraft(rows)
+	}
+	rows.Close()
+	if !found {
+		return draft, errors.New("draft is not found")
+	}
+
+	draft.Reviews = repository.findReviews(ctx, tx, draft.ID)
+	return draft, nil
+}
+
+func (repository *DraftRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, filter domain.DraftFilter) []domain.Draft {
+	defer metrics.ObserveQuery("Draft.FindAll", time.Now())
+
+	var conditions []string
+	var args []interface{}
+	for column, value := range map[string]string{
+		"tenant":      filter.Tenant,
+		"environment": filter.Environment,
+		"schema":      filter.Schema,
+		"name":        filter.Name,
+		"status":      filter.Status,
+	} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	SQL := "SELECT " + draftColumns + " FROM drafts"
+	if len(conditions) > 0 {
+		SQL += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	SQL += " ORDER BY id ASC"
+	queryCtx, span := tracing.StartQuery(ctx, "DraftRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(queryCtx, SQL, args...)
+	helper.PanicIfError(err)
+
+	drafts := []domain.Draft{}
+	for rows.Next() {
+		drafts = append(drafts, scanDraft(rows))
+	}
+	rows.Close()
+
+	for i := range drafts {
+		drafts[i].Reviews = repository.findReviews(ctx, tx, drafts[i].ID)
+	}
+
+	return drafts
+}
+
+func (repository *DraftRepositoryImpl) UpdateStatus(ctx context.Context, tx *sql.Tx, draft domain.Draft) {
+	defer metrics.ObserveQuery("Draft.UpdateStatus", time.Now())
+
+	SQL := "UPDATE drafts SET status = ?, published_version = ?, updated_at = ? WHERE id = ?"
+	ctx, span := tracing.StartQuery(ctx, "DraftRepository.UpdateStatus", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, draft.Status, draft.PublishedVersion, draft.UpdatedAt, draft.ID)
+	helper.PanicIfError(err)
+}
+
+func (repository *DraftRepositoryImpl) AddReview(ctx context.Context, tx *sql.Tx, draftID int64, review domain.DraftReview) domain.DraftReview {
+	defer metrics.ObserveQuery("Draft.AddReview", time.Now())
+
+	SQL := "INSERT INTO draft_reviews (draft_id, reviewer, decision, comment, created_at) VALUES (?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "DraftRepository.AddReview", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, draftID, review.Reviewer, review.Decision, review.Comment, review.CreatedAt)
+	helper.PanicIfError(err)
+
+	return review
+}
+
+func (repository *DraftRepositoryImpl) findReviews(ctx context.Context, tx *sql.Tx, draftID int64) []domain.DraftReview {
+	SQL := "SELECT reviewer, decision, comment, created_at FROM draft_reviews WHERE draft_id = ? ORDER BY id ASC"
+	ctx, span := tracing.StartQuery(ctx, "DraftRepository.findReviews", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, draftID)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	reviews := []domain.DraftReview{}
+	for rows.Next() {
+		review := domain.DraftReview{}
+		err := rows.Scan(&review.Reviewer, &review.Decision, &review.Comment, &review.CreatedAt)
+		helper.PanicIfError(err)
+		reviews = append(reviews, review)
+	}
+
+	return reviews
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type FreezeRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, freeze domain.Freeze) domain.Freeze
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.Freeze
+	Delete(ctx context.Context, tx *sql.Tx, id int64) error
+}
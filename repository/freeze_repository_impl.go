@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const freezeColumns = "id, tenant, environment, schema, name_pattern, reason, recurring_window, created_at, created_by"
+
+type FreezeRepositoryImpl struct{}
+
+func NewFreezeRepository() FreezeRepository {
+	return &FreezeRepositoryImpl{}
+}
+
+func scanFreezes(rows *sql.Rows) []domain.Freeze {
+	freezes := []domain.Freeze{}
+	for rows.Next() {
+		freeze := domain.Freeze{}
+		var window string
+		err := rows.Scan(&freeze.ID, &freeze.Tenant, &freeze.Environment, &freeze.Schema, &freeze.NamePattern, &freeze.Reason,
+			&window, &freeze.CreatedAt, &freeze.CreatedBy)
+		helper.PanicIfError(err)
+
+		// Manual locks are stored without a window
+		if window != "" {
+			freeze.Window = &domain.FreezeWindow{}
+			err = json.Unmarshal([]byte(window), freeze.Window)
+			helper.PanicIfError(err)
+		}
+
+		freezes = append(freezes, freeze)
+	}
+	return freezes
+}
+
+func (repository *FreezeRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, freeze domain.Freeze) domain.Freeze {
+	defer metrics.ObserveQuery("Freeze.Create", time.Now())
+
+	var window []byte
+	if freeze.Window != nil {
+		var err error
+		window, err = json.Marshal(freeze.Window)
+		helper.PanicIfError(err)
+	}
+
+	SQL := "INSERT INTO freezes (tenant, environment, schema, name_pattern, reason, recurring_window, created_at, created_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "FreezeRepository.Create", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, freeze.Tenant, freeze.Environment, freeze.Schema, freeze.NamePattern, freeze.Reason,
+		string(window), freeze.CreatedAt, freeze.CreatedBy)
+	helper.PanicIfError(err)
+
+	freeze.ID, err = result.LastInsertId()
+	helper.PanicIfError(err)
+
+	return freeze
+}
+
+func (repository *FreezeRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Freeze {
+	defer metrics.ObserveQuery("Freeze.FindAll", time.Now())
+
+	SQL := "SELECT " + freezeColumns + " FROM freezes ORDER BY id ASC"
+	ctx, span := tracing.StartQuery(ctx, "FreezeRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return scanFreezes(rows)
+}
+
+func (repository *FreezeRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, id int64) error {
+	defer metrics.ObserveQuery("Freeze.Delete", time.Now())
+
+	SQL := "DELETE FROM freezes WHERE id = ?"
+	ctx, span := tracing.StartQuery(ctx, "FreezeRepository.Delete", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, id)
+	helper.PanicIfError(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	if affected == 0 {
+		return errors.New("freeze is not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type LabelRepository interface {
+	// Find returns the label of a config by name.
+	Find(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) (domain.ConfigLabel, error)
+	// FindAll returns the labels of a config by name.
+	FindAll(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigLabel
+	// ListAll returns the labels of every config.
+	ListAll(ctx context.Context, tx *sql.Tx) []domain.ConfigLabel
+	// Save creates label or moves it to another version.
+	Save(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) domain.ConfigLabel
+	Delete(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const labelColumns = "tenant, environment, schema, name, label, version, updated_at, updated_by"
+
+type LabelRepositoryImpl struct{}
+
+func NewLabelRepository() LabelRepository {
+	return &LabelRepositoryImpl{}
+}
+
+func (repository *LabelRepositoryImpl) Find(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) (domain.ConfigLabel, error) {
+	defer metrics.ObserveQuery("Label.Find", time.Now())
+
+	SQL := "SELECT " + labelColumns + " FROM config_labels WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND label = ?"
+	ctx, span := tracing.StartQuery(ctx, "LabelRepository.Find", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, label.Tenant, label.Environment, label.Schema, label.Name, label.Label)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	labels := scanLabels(rows)
+	if len(labels) == 0 {
+		return domain.ConfigLabel{}, errors.New("label is not found")
+	}
+
+	return labels[0], nil
+}
+
+func (repository *LabelRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigLabel {
+	defer metrics.ObserveQuery("Label.FindAll", time.Now())
+
+	SQL := "SELECT " + labelColumns + " FROM config_labels WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? ORDER BY label ASC"
+	ctx, span := tracing.StartQuery(ctx, "LabelRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, config.Tenant, config.Environment, config.Schema, config.Name)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+func (repository *LabelRepositoryImpl) ListAll(ctx context.Context, tx *sql.Tx) []domain.ConfigLabel {
+	defer metrics.ObserveQuery("Label.ListAll", time.Now())
+
+	SQL := "SELECT " + labelColumns + " FROM config_labels ORDER BY tenant, environment, schema, name, label ASC"
+	ctx, span := tracing.StartQuery(ctx, "LabelRepository.ListAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+func (repository *LabelRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) domain.ConfigLabel {
+	defer metrics.ObserveQuery("Label.Save", time.Now())
+
+	SQL := `INSERT INTO config_labels (` + labelColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tenant, environment, schema, name, label) DO UPDATE SET version = excluded.version, updated_at = excluded.updated_at, updated_by = excluded.updated_by`
+	ctx, span := tracing.StartQuery(ctx, "LabelRepository.Save", SQL)
+	defer tracing.End(span)
+
+	_, err := tx.ExecContext(ctx, SQL, label.Tenant, label.Environment, label.Schema, label.Name, label.Label, label.Version, label.UpdatedAt, label.UpdatedBy)
+	helper.PanicIfError(err)
+
+	return label
+}
+
+func (repository *LabelRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, label domain.ConfigLabel) error {
+	defer metrics.ObserveQuery("Label.Delete", time.Now())
+
+	SQL := "DELETE FROM config_labels WHERE tenant = ? AND environment = ? AND schema = ? AND name = ? AND label = ?"
+	ctx, span := tracing.StartQuery(ctx, "LabelRepository.Delete", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, label.Tenant, label.Environment, label.Schema, label.Name, label.Label)
+	helper.PanicIfError(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	if affected == 0 {
+		return errors.New("label is not found")
+	}
+
+	return nil
+}
+
+func scanLabels(rows *sql.Rows) []domain.ConfigLabel {
+	labels := []domain.ConfigLabel{}
+	for rows.Next() {
+		label := domain.ConfigLabel{}
+		err := rows.Scan(&label.Tenant, &label.Environment, &label.Schema, &label.Name, &label.Label, &label.Version, &label.UpdatedAt, &label.UpdatedBy)
+		helper.PanicIfError(err)
+		labels = append(labels, label)
+	}
+	return labels
+}
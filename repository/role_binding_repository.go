@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type RoleBindingRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, binding domain.RoleBinding) (domain.RoleBinding, error)
+	FindByPrincipal(ctx context.Context, tx *sql.Tx, tenant, principal string) []domain.RoleBinding
+	FindAll(ctx context.Context, tx *sql.Tx, tenant string) []domain.RoleBinding
+	Delete(ctx context.Context, tx *sql.Tx, tenant string, id int64) error
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+const roleBindingColumns = "id, tenant, principal, role, schema, name_pattern, created_at, created_by"
+
+type RoleBindingRepositoryImpl struct{}
+
+func NewRoleBindingRepository() RoleBindingRepository {
+	return &RoleBindingRepositoryImpl{}
+}
+
+func scanRoleBindings(rows *sql.Rows) []domain.RoleBinding {
+	bindings := []domain.RoleBinding{}
+	for rows.Next() {
+		binding := domain.RoleBinding{}
+		err := rows.Scan(&binding.ID, &binding.Tenant, &binding.Principal, &binding.Role, &binding.Schema, &binding.NamePattern, &binding.CreatedAt, &binding.CreatedBy)
+		helper.PanicIfError(err)
+		bindings = append(bindings, binding)
+	}
+	return bindings
+}
+
+// Create stores binding and returns it with its ID, or an error when the
+// same binding already exists.
+func (repository *RoleBindingRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, binding domain.RoleBinding) (domain.RoleBinding, error) {
+	defer metrics.ObserveQuery("RoleBinding.Create", time.Now())
+
+	SQL := "INSERT INTO role_bindings (tenant, principal, role, schema, name_pattern, created_at, created_by) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	ctx, span := tracing.StartQuery(ctx, "RoleBindingRepository.Create", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, binding.Tenant, binding.Principal, binding.Role, binding.Schema, binding.NamePattern, binding.CreatedAt, binding.CreatedBy)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return binding, errors.New("role binding already exist")
+	}
+	helper.PanicIfError(err)
+
+	binding.ID, err = result.LastInsertId()
+	helper.PanicIfError(err)
+
+	return binding, nil
+}
+
+func (repository *RoleBindingRepositoryImpl) FindByPrincipal(ctx context.Context, tx *sql.Tx, tenant, principal string) []domain.RoleBinding {
+	defer metrics.ObserveQuery("RoleBinding.FindByPrincipal", time.Now())
+
+	SQL := "SELECT " + roleBindingColumns + " FROM role_bindings WHERE tenant = ? AND principal = ? ORDER BY id ASC"
+	ctx, span := tracing.StartQuery(ctx, "RoleBindingRepository.FindByPrincipal", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, tenant, principal)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return scanRoleBindings(rows)
+}
+
+func (repository *RoleBindingRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, tenant string) []domain.RoleBinding {
+	defer metrics.ObserveQuery("RoleBinding.FindAll", time.Now())
+
+	SQL := "SELECT " + roleBindingColumns + " FROM role_bindings WHERE tenant = ? ORDER BY principal ASC, id ASC"
+	ctx, span := tracing.StartQuery(ctx, "RoleBindingRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, tenant)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	return scanRoleBindings(rows)
+}
+
+func (repository *RoleBindingRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, tenant string, id int64) error {
+	defer metrics.ObserveQuery("RoleBinding.Delete", time.Now())
+
+	SQL := "DELETE FROM role_bindings WHERE tenant = ? AND id = ?"
+	ctx, span := tracing.StartQuery(ctx, "RoleBindingRepository.Delete", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, tenant, id)
+	helper.PanicIfError(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	if affected == 0 {
+		return errors.New("role binding is not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/secret"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SecretConfigRepository is a ConfigRepository encrypting the values marked
+// secret by the schemas of the configs it stores.
+type SecretConfigRepository interface {
+	ConfigRepository
+	// RotateSecrets re-encrypts the data keys of the secret values that are
+	// not encrypted with the active key, returning the number of versions
+	// changed.
+	RotateSecrets(ctx context.Context, tx *sql.Tx) int
+}
+
+// SecretConfigRepositoryImpl encrypts secret values before they are
+// written by ConfigRepository and decrypts them after they are read, so
+// callers only see plaintext.
+type SecretConfigRepositoryImpl struct {
+	ConfigRepository
+	Keyring *secret.Keyring
+}
+
+func NewSecretConfigRepository(configRepository ConfigRepository, keyring *secret.Keyring) SecretConfigRepository {
+	return &SecretConfigRepositoryImpl{
+		ConfigRepository: configRepository,
+		Keyring:          keyring,
+	}
+}
+
+func (repository *SecretConfigRepositoryImpl) GetLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error) {
+	configRecord, err := repository.ConfigRepository.GetLatest(ctx, tx, config)
+	return repository.open(configRecord), err
+}
+
+func (repository *SecretConfigRepositoryImpl) GetActive(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord, at time.Time) (domain.ConfigRecord, error) {
+	configRecord, err := repository.ConfigRepository.GetActive(ctx, tx, config, at)
+	return repository.open(configRecord), err
+}
+
+func (repository *SecretConfigRepositoryImpl) GetByVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) (domain.ConfigRecord, error) {
+	configRecord, err := repository.ConfigRepository.GetByVersion(ctx, tx, config)
+	return repository.open(configRecord), err
+}
+
+func (repository *SecretConfigRepositoryImpl) ListVersions(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	return repository.openAll(repository.ConfigRepository.ListVersions(ctx, tx, config))
+}
+
+func (repository *SecretConfigRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.ConfigRecord {
+	return repository.openAll(repository.ConfigRepository.FindAll(ctx, tx))
+}
+
+func (repository *SecretConfigRepositoryImpl) LatestPerEnvironment(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	return repository.openAll(repository.ConfigRepository.LatestPerEnvironment(ctx, tx, config))
+}
+
+func (repository *SecretConfigRepositoryImpl) ListLatest(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) []domain.ConfigRecord {
+	return repository.openAll(repository.ConfigRepository.ListLatest(ctx, tx, config))
+}
+
+func (repository *SecretConfigRepositoryImpl) ListActivations(ctx context.Context, tx *sql.Tx, after, until time.Time) []domain.ConfigRecord {
+	return repository.openAll(repository.ConfigRepository.ListActivations(ctx, tx, after, until))
+}
+
+func (repository *SecretConfigRepositoryImpl) CreateNewVersion(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) domain.ConfigRecord {
+	data := config.Data
+	config = repository.ConfigRepository.CreateNewVersion(ctx, tx, repository.seal(config))
+	config.Data = data
+	return config
+}
+
+func (repository *SecretConfigRepositoryImpl) ReplaceData(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord) {
+	repository.ConfigRepository.ReplaceData(ctx, tx, repository.seal(config))
+}
+
+func (repository *SecretConfigRepositoryImpl) RotateSecrets(ctx context.Context, tx *sql.Tx) int {
+	rotated := 0
+	for _, configRecord := range repository.ConfigRepository.FindAll(ctx, tx) {
+		changed := false
+		data, err := secret.FindEnvelopes(configRecord.Data, func(text string) (interface{}, error) {
+			envelope, rewrapped, err := repository.Keyring.Rewrap(text)
+			if err != nil || !rewrapped {
+				return map[string]interface{}{secret.EnvelopeKey: text}, err
+			}
+			changed = true
+			return envelope, nil
+		})
+		if err != nil {
+			helper.PanicIfError(fmt.Errorf("%s/%s version %d: %w", configRecord.Schema, configRecord.Name, configRecord.Version, err))
+		}
+
+		if changed {
+			configRecord.Data = data.(map[string]interface{})
+			repository.ConfigRepository.ReplaceData(ctx, tx, configRecord)
+			rotated++
+		}
+	}
+
+	return rotated
+}
+
+// seal encrypts the secret values of config.
+func (repository *SecretConfigRepositoryImpl) seal(config domain.ConfigRecord) domain.ConfigRecord {
+	config.Data = sealSecrets(repository.Keyring, config.Tenant, config.Schema, config.Data)
+	return config
+}
+
+// open decrypts the encrypted values of config.
+func (repository *SecretConfigRepositoryImpl) open(config domain.ConfigRecord) domain.ConfigRecord {
+	data, err := openSecrets(repository.Keyring, config.Data)
+	if err != nil {
+		helper.PanicIfError(fmt.Errorf("%s/%s version %d: %w", config.Schema, config.Name, config.Version, err))
+	}
+	config.Data = data
+
+	return config
+}
+
+func (repository *SecretConfigRepositoryImpl) openAll(configRecords []domain.ConfigRecord) []domain.ConfigRecord {
+	for i := range configRecords {
+		configRecords[i] = repository.open(configRecords[i])
+	}
+	return configRecords
+}
+
+// sealSecrets encrypts the values of data marked secret by schema. Envelope
+// objects are reserved for encrypted values, so data holding some is
+// rejected.
+func sealSecrets(keyring *secret.Keyring, tenant, schema string, data map[string]interface{}) map[string]interface{} {
+	_, err := secret.FindEnvelopes(data, func(string) (interface{}, error) {
+		return nil, helper.ValidationError{Msg: secret.EnvelopeKey + " objects are reserved for encrypted values"}
+	})
+	helper.PanicIfError(err)
+
+	paths := helper.SecretPaths(tenant, schema)
+	if len(paths) == 0 {
+		return data
+	}
+
+	data, err = secret.Transform(data, paths, func(_ string, value interface{}) (interface{}, error) {
+		return keyring.Seal(value)
+	})
+	helper.PanicIfError(err)
+
+	return data
+}
+
+// openSecrets decrypts the encrypted values of data, wherever they are, as
+// its schema may have changed since they were encrypted.
+func openSecrets(keyring *secret.Keyring, data map[string]interface{}) (map[string]interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	opened, err := secret.FindEnvelopes(data, keyring.Open)
+	if err != nil {
+		return nil, err
+	}
+	result, _ := opened.(map[string]interface{})
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/secret"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SecretDraftRepositoryImpl encrypts the secret values of drafts like
+// SecretConfigRepositoryImpl does for versions.
+type SecretDraftRepositoryImpl struct {
+	DraftRepository
+	Keyring *secret.Keyring
+}
+
+func NewSecretDraftRepository(draftRepository DraftRepository, keyring *secret.Keyring) DraftRepository {
+	return &SecretDraftRepositoryImpl{
+		DraftRepository: draftRepository,
+		Keyring:         keyring,
+	}
+}
+
+func (repository *SecretDraftRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, draft domain.Draft) domain.Draft {
+	data := draft.Data
+	draft.Data = sealSecrets(repository.Keyring, draft.Tenant, draft.Schema, draft.Data)
+	draft = repository.DraftRepository.Save(ctx, tx, draft)
+	draft.Data = data
+	return draft
+}
+
+func (repository *SecretDraftRepositoryImpl) FindByID(ctx context.Context, tx *sql.Tx, tenant, environment string, id int64) (domain.Draft, error) {
+	draft, err := repository.DraftRepository.FindByID(ctx, tx, tenant, environment, id)
+	if err != nil {
+		return draft, err
+	}
+	return repository.open(draft), nil
+}
+
+func (repository *SecretDraftRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, filter domain.DraftFilter) []domain.Draft {
+	drafts := repository.DraftRepository.FindAll(ctx, tx, filter)
+	for i := range drafts {
+		drafts[i] = repository.open(drafts[i])
+	}
+	return drafts
+}
+
+func (repository *SecretDraftRepositoryImpl) open(draft domain.Draft) domain.Draft {
+	data, err := openSecrets(repository.Keyring, draft.Data)
+	if err != nil {
+		helper.PanicIfError(fmt.Errorf("draft %d: %w", draft.ID, err))
+	}
+	draft.Data = data
+	return draft
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+)
+
+type VariableRepository interface {
+	// FindAll returns the variables of an environment of a tenant by name.
+	FindAll(ctx context.Context, tx *sql.Tx, tenant, environment string) []domain.Variable
+	// Save creates variable or replaces its value.
+	Save(ctx context.Context, tx *sql.Tx, variable domain.Variable) domain.Variable
+	Delete(ctx context.Context, tx *sql.Tx, variable domain.Variable) error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"config-service/helper"
+	"config-service/metrics"
+	"config-service/model/domain"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type VariableRepositoryImpl struct{}
+
+func NewVariableRepository() VariableRepository {
+	return &VariableRepositoryImpl{}
+}
+
+func (repository *VariableRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, tenant, environment string) []domain.Variable {
+	defer metrics.ObserveQuery("Variable.FindAll", time.Now())
+
+	SQL := "SELECT tenant, environment, name, value, updated_at, updated_by FROM variables WHERE tenant = ? AND environment = ? ORDER BY name ASC"
+	ctx, span := tracing.StartQuery(ctx, "VariableRepository.FindAll", SQL)
+	defer tracing.End(span)
+
+	rows, err := tx.QueryContext(ctx, SQL, tenant, environment)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	variables := []domain.Variable{}
+	for rows.Next() {
+		variable := domain.Variable{}
+		var value string
+		err := rows.Scan(&variable.Tenant, &variable.Environment, &variable.Name, &value, &variable.UpdatedAt, &variable.UpdatedBy)
+		helper.PanicIfError(err)
+
+		err = json.Unmarshal([]byte(value), &variable.Value)
+		helper.PanicIfError(err)
+
+		variables = append(variables, variable)
+	}
+
+	return variables
+}
+
+func (repository *VariableRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, variable domain.Variable) domain.Variable {
+	defer metrics.ObserveQuery("Variable.Save", time.Now())
+
+	value, err := json.Marshal(variable.Value)
+	helper.PanicIfError(err)
+
+	SQL := `INSERT INTO variables (tenant, environment, name, value, updated_at, updated_by) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tenant, environment, name) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at, updated_by = excluded.updated_by`
+	ctx, span := tracing.StartQuery(ctx, "VariableRepository.Save", SQL)
+	defer tracing.End(span)
+
+	_, err = tx.ExecContext(ctx, SQL, variable.Tenant, variable.Environment, variable.Name, string(value), variable.UpdatedAt, variable.UpdatedBy)
+	helper.PanicIfError(err)
+
+	return variable
+}
+
+func (repository *VariableRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, variable domain.Variable) error {
+	defer metrics.ObserveQuery("Variable.Delete", time.Now())
+
+	SQL := "DELETE FROM variables WHERE tenant = ? AND environment = ? AND name = ?"
+	ctx, span := tracing.StartQuery(ctx, "VariableRepository.Delete", SQL)
+	defer tracing.End(span)
+
+	result, err := tx.ExecContext(ctx, SQL, variable.Tenant, variable.Environment, variable.Name)
+	helper.PanicIfError(err)
+
+	affected, err := result.RowsAffected()
+	helper.PanicIfError(err)
+	if affected == 0 {
+		return errors.New("variable is not found")
+	}
+
+	return nil
+}
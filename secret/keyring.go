@@ -0,0 +1,234 @@
+// Package secret encrypts the secret values of configs at rest and masks
+// them in responses.
+//
+// Values are encrypted with envelope encryption: every value is encrypted
+// with its own random data key, which is encrypted with a key of the keyring
+// and stored next to the value. Rotating the keyring only re-encrypts the
+// data keys.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// EnvelopeKey is the only key of the objects encrypted values are stored as,
+// {"$secret": "v1:<key id>:<data key>:<value>"}.
+const EnvelopeKey = "$secret"
+
+const envelopeVersion = "v1"
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Keyring holds the keys encrypting the data keys of secret values, by ID.
+// New values are encrypted with the active key, the other keys decrypt the
+// values encrypted before a rotation.
+type Keyring struct {
+	active string
+	keys   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a keyring from base64 encoded 256 bit keys by ID. An
+// empty keyring decrypts nothing and refuses to encrypt.
+func NewKeyring(keys map[string]string, active string) (*Keyring, error) {
+	keyring := &Keyring{active: active, keys: map[string]cipher.AEAD{}}
+
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(keys[id])
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %s must be 32 base64 encoded bytes", id)
+		}
+		keyring.keys[id], err = newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if active == "" && len(keys) > 0 {
+		return nil, errors.New("the active key ID is required")
+	}
+	if _, ok := keyring.keys[active]; active != "" && !ok {
+		return nil, fmt.Errorf("active key %s is not in the keyring", active)
+	}
+
+	return keyring, nil
+}
+
+// Active returns the ID of the key encrypting new values, "" for empty
+// keyrings.
+func (keyring *Keyring) Active() string {
+	return keyring.active
+}
+
+// Seal encrypts value, returning its envelope object.
+func (keyring *Keyring) Seal(value interface{}) (map[string]interface{}, error) {
+	kek, ok := keyring.keys[keyring.active]
+	if !ok {
+		return nil, errors.New("no secret key is configured to encrypt secret values")
+	}
+
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	dek, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := seal(kek, dataKey, []byte(keyring.active))
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(dek, plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelope(keyring.active, wrapped, ciphertext), nil
+}
+
+// Open decrypts the value of an envelope.
+func (keyring *Keyring) Open(text string) (interface{}, error) {
+	id, wrapped, ciphertext, err := parseEnvelope(text)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := keyring.unwrap(id, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(dek, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("secret value can't be decrypted")
+	}
+
+	var value interface{}
+	err = json.Unmarshal(plaintext, &value)
+	return value, err
+}
+
+// Rewrap re-encrypts the data key of an envelope with the active key,
+// leaving the encrypted value as it is. It reports whether the envelope was
+// encrypted with another key.
+func (keyring *Keyring) Rewrap(text string) (map[string]interface{}, bool, error) {
+	id, wrapped, ciphertext, err := parseEnvelope(text)
+	if err != nil || id == keyring.active {
+		return nil, false, err
+	}
+
+	kek, ok := keyring.keys[keyring.active]
+	if !ok {
+		return nil, false, errors.New("no secret key is configured to encrypt secret values")
+	}
+	dataKey, err := keyring.unwrap(id, wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+	if wrapped, err = seal(kek, dataKey, []byte(keyring.active)); err != nil {
+		return nil, false, err
+	}
+
+	return envelope(keyring.active, wrapped, ciphertext), true, nil
+}
+
+func (keyring *Keyring) unwrap(id string, wrapped []byte) ([]byte, error) {
+	kek, ok := keyring.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("secret key %s is not configured", id)
+	}
+	dataKey, err := open(kek, wrapped, []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("secret value can't be decrypted with key %s", id)
+	}
+	return dataKey, nil
+}
+
+// AsEnvelope returns the encrypted text of an envelope object, and whether
+// value is one.
+func AsEnvelope(value interface{}) (string, bool) {
+	object, ok := value.(map[string]interface{})
+	if !ok || len(object) != 1 {
+		return "", false
+	}
+	text, ok := object[EnvelopeKey].(string)
+	return text, ok
+}
+
+func envelope(id string, wrapped, ciphertext []byte) map[string]interface{} {
+	encoding := base64.RawStdEncoding
+	return map[string]interface{}{
+		EnvelopeKey: strings.Join([]string{envelopeVersion, id, encoding.EncodeToString(wrapped), encoding.EncodeToString(ciphertext)}, ":"),
+	}
+}
+
+func parseEnvelope(text string) (string, []byte, []byte, error) {
+	parts := strings.Split(text, ":")
+	if len(parts) != 4 || parts[0] != envelopeVersion {
+		return "", nil, nil, errors.New("malformed secret envelope")
+	}
+
+	encoding := base64.RawStdEncoding
+	wrapped, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", nil, nil, errors.New("malformed secret envelope")
+	}
+	ciphertext, err := encoding.DecodeString(parts[3])
+	if err != nil {
+		return "", nil, nil, errors.New("malformed secret envelope")
+	}
+
+	return parts[1], wrapped, ciphertext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext with a random nonce, which is prepended.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,176 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Keyword marks the values of a schema that are secret, {"x-secret": true}.
+const Keyword = "x-secret"
+
+// Mask replaces secret values in responses.
+const Mask = "********"
+
+// Path addresses the values of a document matching a secret part of its
+// schema. Wildcard tokens match every item of an array or every key of an
+// object.
+type Path []string
+
+const wildcard = "*"
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func (path Path) String() string {
+	return "/" + strings.Join(path, "/")
+}
+
+// Paths returns the paths of the values marked secret by a JSON schema.
+// Subschemas are followed through properties, additionalProperties,
+// patternProperties, items and the allOf, anyOf and oneOf keywords.
+func Paths(schema string) ([]Path, error) {
+	var document interface{}
+	if err := json.Unmarshal([]byte(schema), &document); err != nil {
+		return nil, err
+	}
+
+	var paths []Path
+	collectPaths(nil, document, &paths)
+	return paths, nil
+}
+
+func collectPaths(path Path, node interface{}, paths *[]Path) {
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if secret, _ := schema[Keyword].(bool); secret {
+		*paths = append(*paths, append(Path{}, path...))
+		return
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for key, child := range properties {
+			collectPaths(append(path[:len(path):len(path)], key), child, paths)
+		}
+	}
+	if patterns, ok := schema["patternProperties"].(map[string]interface{}); ok {
+		for _, child := range patterns {
+			collectPaths(append(path[:len(path):len(path)], wildcard), child, paths)
+		}
+	}
+	collectPaths(append(path[:len(path):len(path)], wildcard), schema["additionalProperties"], paths)
+
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		collectPaths(append(path[:len(path):len(path)], wildcard), items, paths)
+	case []interface{}:
+		for _, child := range items {
+			collectPaths(append(path[:len(path):len(path)], wildcard), child, paths)
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if subschemas, ok := schema[keyword].([]interface{}); ok {
+			for _, child := range subschemas {
+				collectPaths(path, child, paths)
+			}
+		}
+	}
+}
+
+// Transform returns a copy of data with fn applied to the values at paths,
+// passing their JSON pointer.
+// Null values are left alone, they remove inherited values of layered
+// configs. data is not modified.
+func Transform(data map[string]interface{}, paths []Path, fn func(path string, value interface{}) (interface{}, error)) (map[string]interface{}, error) {
+	var value interface{} = data
+	for _, path := range paths {
+		var err error
+		if value, err = transform(value, path, "", fn); err != nil {
+			return nil, err
+		}
+	}
+
+	result, _ := value.(map[string]interface{})
+	return result, nil
+}
+
+func transform(value interface{}, path Path, pointer string, fn func(string, interface{}) (interface{}, error)) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if len(path) == 0 {
+		return fn(pointer, value)
+	}
+
+	token := path[0]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if token == wildcard || token == key {
+				var err error
+				if item, err = transform(item, path[1:], pointer+"/"+pointerEscaper.Replace(key), fn); err != nil {
+					return nil, err
+				}
+			}
+			copied[key] = item
+		}
+		return copied, nil
+	case []interface{}:
+		if token != wildcard {
+			return value, nil
+		}
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if copied[i], err = transform(item, path[1:], fmt.Sprintf("%s/%d", pointer, i), fn); err != nil {
+				return nil, err
+			}
+		}
+		return copied, nil
+	default:
+		return value, nil
+	}
+}
+
+// MaskValues returns a copy of data with the values at paths masked.
+func MaskValues(data map[string]interface{}, paths []Path) map[string]interface{} {
+	masked, _ := Transform(data, paths, func(string, interface{}) (interface{}, error) {
+		return Mask, nil
+	})
+	return masked
+}
+
+// FindEnvelopes calls fn with every envelope object within value and
+// returns value with each of them replaced by the result of fn.
+func FindEnvelopes(value interface{}, fn func(text string) (interface{}, error)) (interface{}, error) {
+	if text, ok := AsEnvelope(value); ok {
+		return fn(text)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			var err error
+			if copied[key], err = FindEnvelopes(item, fn); err != nil {
+				return nil, err
+			}
+		}
+		return copied, nil
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if copied[i], err = FindEnvelopes(item, fn); err != nil {
+				return nil, err
+			}
+		}
+		return copied, nil
+	default:
+		return value, nil
+	}
+}
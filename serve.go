@@ -0,0 +1,153 @@
+package main
+
+import (
+	"config-service/app"
+	"config-service/controller"
+	"config-service/model/domain"
+	"config-service/repository"
+	"config-service/service"
+	"config-service/tracing"
+	"context"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-playground/validator"
+)
+
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	settings := loadSettings(flags, args)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), settings.TraceExporter, settings.TraceEndpoint)
+	if err != nil {
+		fatal("could not set up tracing", "error", err)
+	}
+
+	// Load schema
+	loadSchemas()
+
+	schemaCount := 0
+	for _, tenantName := range domain.Tenants() {
+		schemaCount += len(domain.SchemaNames(tenantName))
+	}
+	slog.Info("loaded schemas", "count", schemaCount, "tenants", len(domain.Tenants()))
+
+	if !settings.AuthRequired {
+		slog.Warn("authentication is optional, anonymous callers can change configs")
+	}
+
+	// Setup dependencies
+	db := app.NewDB(settings)
+	validate := validator.New()
+	auditService := service.NewAuditService(repository.NewAuditRepository(), db)
+	runAdmin("audit schemas", func() {
+		auditService.RecordSchemaChanges(context.Background())
+	})
+
+	configRepository := newConfigRepository(settings)
+	variableRepository := repository.NewVariableRepository()
+	freezeService := service.NewFreezeService(repository.NewFreezeRepository(), db, validate, auditService)
+	configService := service.NewConfigService(configRepository, db, validate,
+		service.WithAuditService(auditService),
+		service.WithTenantQuotas(service.TenantQuotas{Default: settings.TenantMaxConfigs, Limits: settings.TenantQuotas}),
+		service.WithEnvironments(settings.Environments),
+		service.WithVariables(variableRepository),
+		service.WithDrafts(repository.NewSecretDraftRepository(repository.NewDraftRepository(), newKeyring(settings)), settings.RequiredApprovals),
+		service.WithFreezes(freezeService),
+		service.WithLabels(repository.NewLabelRepository()),
+	)
+	configController := controller.NewConfigController(configService)
+	schemaController := controller.NewSchemaController()
+	healthService := service.NewHealthService(configRepository, db, app.MigrationStatus(db))
+	healthController := controller.NewHealthController(healthService)
+
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(), db, validate, auditService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	roleBindingController := controller.NewRoleBindingController(service.NewRoleBindingService(repository.NewRoleBindingRepository(), db, validate, auditService))
+	freezeController := controller.NewFreezeController(freezeService)
+	auditController := controller.NewAuditController(auditService)
+	variableController := controller.NewVariableController(service.NewVariableService(variableRepository, db, validate, auditService, settings.Environments))
+	draftController := controller.NewDraftController(configService)
+
+	authenticator, err := app.NewAuthenticator(context.Background(), settings, apiKeyService, auditService)
+	if err != nil {
+		fatal("could not set up authentication", "error", err)
+	}
+
+	configGRPCController := controller.NewConfigGRPCController(configService)
+
+	router := app.NewRouter(settings, authenticator, configController, schemaController, healthController, apiKeyController, roleBindingController, freezeController, auditController, variableController, draftController)
+	grpcServer := app.NewGRPCServer(authenticator, configGRPCController)
+
+	server := &http.Server{
+		Addr:    settings.HTTPAddr,
+		Handler: router,
+	}
+
+	// Run server in a goroutine so it won't block
+	go func() {
+		slog.Info("running config service", "addr", settings.HTTPAddr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal("could not listen", "addr", settings.HTTPAddr, "error", err)
+		}
+	}()
+
+	// gRPC API shares the same service and repository instances
+	go func() {
+		listener, err := net.Listen("tcp", settings.GRPCAddr)
+		if err != nil {
+			fatal("could not listen", "addr", settings.GRPCAddr, "error", err)
+		}
+
+		slog.Info("running config gRPC service", "addr", settings.GRPCAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			fatal("could not serve gRPC", "addr", settings.GRPCAddr, "error", err)
+		}
+	}()
+
+	// Scheduled versions are published to watchers once they become active
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go service.RunScheduler(schedulerCtx, configService, settings.ActivationInterval)
+
+	// Listen for interrupt signals (Ctrl+C, docker stop, etc.)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	// Block until a signal is received
+	<-stop
+	slog.Info("shutting down gracefully")
+	stopScheduler()
+
+	// Give ongoing requests some time to complete
+	ctx, cancel := context.WithTimeout(context.Background(), settings.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		fatal("server forced to shutdown", "error", err)
+	}
+
+	// Open Watch streams would keep GracefulStop waiting, so fall back to Stop
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("could not flush traces", "error", err)
+	}
+
+	slog.Info("server stopped")
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"config-service/model/web"
+	"context"
+)
+
+// AdminService holds maintenance operations that are run by operators
+// through the server binary instead of the REST API.
+type AdminService interface {
+	ValidateSchemas() []web.SchemaValidationResult
+	RevalidateConfigs(ctx context.Context, allVersions bool) []web.ConfigValidationResult
+	ExportConfigs(ctx context.Context) []web.ConfigResponse
+	ImportConfigs(ctx context.Context, configs []web.ConfigResponse) web.ImportResult
+	CompactConfigs(ctx context.Context, keep int, override string) web.CompactResult
+	RotateSecrets(ctx context.Context) web.RotateSecretsResult
+}
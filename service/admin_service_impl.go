@@ -0,0 +1,446 @@
+package service
+
+import (
+	"config-service/environment"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+type AdminServiceImpl struct {
+	ConfigRepository   repository.ConfigRepository
+	VariableRepository repository.VariableRepository
+	LabelRepository    repository.LabelRepository
+	FreezeRepository   repository.FreezeRepository
+	DB                 *sql.DB
+	Audit              AuditService
+}
+
+func NewAdminService(configRepository repository.ConfigRepository, variableRepository repository.VariableRepository, labelRepository repository.LabelRepository, freezeRepository repository.FreezeRepository, DB *sql.DB, auditService AuditService) AdminService {
+	return &AdminServiceImpl{
+		ConfigRepository:   configRepository,
+		VariableRepository: variableRepository,
+		LabelRepository:    labelRepository,
+		FreezeRepository:   freezeRepository,
+		DB:                 DB,
+		Audit:              auditService,
+	}
+}
+
+func (service *AdminServiceImpl) ValidateSchemas() []web.SchemaValidationResult {
+	results := []web.SchemaValidationResult{}
+	for _, tenantName := range domain.Tenants() {
+		for _, name := range domain.SchemaNames(tenantName) {
+			result := web.SchemaValidationResult{Tenant: tenantName, Name: name, Valid: true}
+
+			schema, _ := domain.LookupSchema(tenantName, name)
+			_, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schema))
+			if err != nil {
+				result.Valid = false
+				result.Error = err.Error()
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+func (service *AdminServiceImpl) RevalidateConfigs(ctx context.Context, allVersions bool) []web.ConfigValidationResult {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecords := service.ConfigRepository.FindAll(ctx, tx)
+	if !allVersions {
+		configRecords = servedVersions(configRecords, time.Now())
+	}
+
+	results := make([]web.ConfigValidationResult, 0, len(configRecords))
+	for _, configRecord := range configRecords {
+		result := web.ConfigValidationResult{
+			Tenant:      configRecord.Tenant,
+			Environment: configRecord.Environment,
+			Schema:      configRecord.Schema,
+			Name:        configRecord.Name,
+			Version:     configRecord.Version,
+			Valid:       true,
+		}
+
+		schema, ok := domain.LookupSchema(configRecord.Tenant, configRecord.Schema)
+		if !ok {
+			result.Valid = false
+			result.Error = "unknown schema"
+		} else if data, err := service.effectiveData(ctx, tx, configRecord); err != nil {
+			result.Valid = false
+			result.Error = err.Error()
+		} else if err := helper.CheckAgainstSchema(schema, data); err != nil {
+			result.Valid = false
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func (service *AdminServiceImpl) ExportConfigs(ctx context.Context) []web.ConfigResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecords := service.ConfigRepository.FindAll(ctx, tx)
+
+	// Exports restore the secret values, so they hold them decrypted
+	configResponses := make([]web.ConfigResponse, 0, len(configRecords))
+	for _, configRecord := range configRecords {
+		configResponses = append(configResponses, helper.ToRevealedConfigResponse(configRecord))
+	}
+
+	return configResponses
+}
+
+// ImportConfigs restores exported versions in a single transaction.
+// Versions that already exist with the same data are skipped, versions that
+// exist with different data abort the whole import.
+func (service *AdminServiceImpl) ImportConfigs(ctx context.Context, configs []web.ConfigResponse) web.ImportResult {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	result := web.ImportResult{}
+	for _, config := range configs {
+		// Exports made before tenants were introduced have none
+		if config.Tenant == "" {
+			config.Tenant = tenant.Default
+		}
+		if !tenant.Valid(config.Tenant) {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("%s/%s: invalid tenant %s", config.Schema, config.Name, config.Tenant)})
+		}
+		// and exports made before environments were introduced
+		if config.Environment == "" {
+			config.Environment = environment.Default
+		}
+		if !environment.Valid(config.Environment) {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("%s/%s: invalid environment %s", config.Schema, config.Name, config.Environment)})
+		}
+		if config.Version <= 0 {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("%s/%s: version must be positive", config.Schema, config.Name)})
+		}
+
+		schema, ok := domain.LookupSchema(config.Tenant, config.Schema)
+		if !ok {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("%s/%s: unknown schema", config.Schema, config.Name)})
+		}
+		// Parents and referenced configs may follow later in the export, and
+		// variables are not exported, revalidate-configs checks the effective
+		// data of those
+		derived, err := isDerived(config.Data)
+		if err == nil && !derived {
+			err = helper.CheckAgainstSchema(schema, config.Data)
+		}
+		if err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("%s/%s version %d: %v", config.Schema, config.Name, config.Version, err)})
+		}
+
+		configRecord := domain.ConfigRecord{
+			Tenant:      config.Tenant,
+			Environment: config.Environment,
+			Schema:      config.Schema,
+			Name:        config.Name,
+			Version:     config.Version,
+			Data:        config.Data,
+			CreatedAt:   config.CreatedAt,
+			CreatedBy:   config.CreatedBy,
+			Metadata:    config.Metadata,
+			EffectiveAt: config.EffectiveAt,
+			CancelledAt: config.CancelledAt,
+		}
+
+		existing, err := service.ConfigRepository.GetByVersion(ctx, tx, configRecord)
+		if err == nil {
+			if !reflect.DeepEqual(existing.Data, helper.NormalizeData(config.Data)) {
+				panic(exception.NewConflictError(fmt.Sprintf("%s/%s version %d already exists with different data", config.Schema, config.Name, config.Version)))
+			}
+			result.Skipped++
+			continue
+		}
+
+		configRecord = service.ConfigRepository.CreateNewVersion(ctx, tx, configRecord)
+		service.Audit.Record(ctx, tx, domain.AuditEvent{
+			Action:      domain.AuditConfigImported,
+			Tenant:      configRecord.Tenant,
+			Environment: configRecord.Environment,
+			Schema:      configRecord.Schema,
+			Name:        configRecord.Name,
+			Version:     configRecord.Version,
+			AfterHash:   helper.DataHash(configRecord.Data),
+		})
+		result.Imported++
+	}
+
+	return result
+}
+
+// CompactConfigs deletes all but the newest keep versions of every config
+// that took effect, the active version first, and reclaims the freed space.
+// Pending scheduled versions and cancelled versions newer than the kept ones
+// are not deleted, nor are versions named by labels or pinned by references
+// of the kept versions. Configs locked by active freezes are skipped unless
+// override gives the reason for compacting them. keep <= 0 only reclaims
+// space.
+func (service *AdminServiceImpl) CompactConfigs(ctx context.Context, keep int, override string) web.CompactResult {
+	result := web.CompactResult{}
+
+	if keep > 0 {
+		func() {
+			tx, err := service.DB.Begin()
+			helper.PanicIfError(err)
+			defer helper.CommitOrRollback(tx)
+
+			configRecords := service.ConfigRepository.FindAll(ctx, tx)
+			kept := keptVersions(configRecords, keep, time.Now())
+			labeled := labeledVersions(service.LabelRepository.ListAll(ctx, tx))
+			retained := func(configRecord domain.ConfigRecord) bool {
+				return configRecord.Version >= kept[configKey(configRecord)] || labeled[versionKey(configRecord)]
+			}
+			pinned := pinnedVersions(configRecords, retained)
+			frozen := service.frozenConfigs(ctx, tx, configRecords, time.Now())
+
+			skipped := map[string]bool{}
+			for _, configRecord := range configRecords {
+				if retained(configRecord) || pinned[versionKey(configRecord)] {
+					continue
+				}
+
+				// Deleting versions of frozen configs takes an override, which
+				// is recorded with every deleted version
+				var overridden map[string]interface{}
+				if freezes := frozen[configKey(configRecord)]; len(freezes) > 0 {
+					if override == "" {
+						skipped[configKey(configRecord)] = true
+						continue
+					}
+					overridden = freezeOverride(ctx, freezes, override)
+				}
+
+				service.ConfigRepository.DeleteVersion(ctx, tx, configRecord)
+				service.Audit.Record(ctx, tx, domain.AuditEvent{
+					Action:      domain.AuditConfigDeleted,
+					Tenant:      configRecord.Tenant,
+					Environment: configRecord.Environment,
+					Schema:      configRecord.Schema,
+					Name:        configRecord.Name,
+					Version:     configRecord.Version,
+					BeforeHash:  helper.DataHash(configRecord.Data),
+					Detail:      overrideDetail(fmt.Sprintf("compacted to the newest %d version(s)", keep), overridden),
+				})
+				result.Deleted++
+			}
+			result.Skipped = len(skipped)
+		}()
+	}
+
+	// VACUUM cannot run inside a transaction
+	_, err := service.DB.ExecContext(ctx, "VACUUM")
+	helper.PanicIfError(err)
+
+	return result
+}
+
+// RotateSecrets re-encrypts the data keys of the secret values of every
+// version with the active secret key, once a new key has been configured.
+func (service *AdminServiceImpl) RotateSecrets(ctx context.Context) web.RotateSecretsResult {
+	secretRepository, ok := service.ConfigRepository.(repository.SecretConfigRepository)
+	if !ok {
+		helper.PanicIfError(helper.ValidationError{Msg: "secret values are not encrypted"})
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	result := web.RotateSecretsResult{Rotated: secretRepository.RotateSecrets(ctx, tx)}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditSecretsRotated,
+		Detail: fmt.Sprintf("re-encrypted the secrets of %d config version(s)", result.Rotated),
+	})
+
+	return result
+}
+
+func configKey(configRecord domain.ConfigRecord) string {
+	return configRecord.Tenant + "/" + configRecord.Environment + "/" + configRecord.Schema + "/" + configRecord.Name
+}
+
+func versionKey(configRecord domain.ConfigRecord) string {
+	return fmt.Sprintf("%s@%d", configKey(configRecord), configRecord.Version)
+}
+
+// labeledVersions returns the versions named by labels by versionKey.
+func labeledVersions(labels []domain.ConfigLabel) map[string]bool {
+	labeled := map[string]bool{}
+	for _, label := range labels {
+		labeled[versionKey(domain.ConfigRecord{
+			Tenant:      label.Tenant,
+			Environment: label.Environment,
+			Schema:      label.Schema,
+			Name:        label.Name,
+			Version:     label.Version,
+		})] = true
+	}
+	return labeled
+}
+
+// frozenConfigs returns the freezes active at the given time by configKey,
+// for the configs they lock.
+func (service *AdminServiceImpl) frozenConfigs(ctx context.Context, tx *sql.Tx, configRecords []domain.ConfigRecord, at time.Time) map[string][]domain.Freeze {
+	frozen := map[string][]domain.Freeze{}
+	if service.FreezeRepository == nil {
+		return frozen
+	}
+
+	freezes := service.FreezeRepository.FindAll(ctx, tx)
+	for _, configRecord := range configRecords {
+		key := configKey(configRecord)
+		if _, ok := frozen[key]; ok {
+			continue
+		}
+		frozen[key] = nil
+		for _, freeze := range freezes {
+			if freeze.Matches(configRecord.Tenant, configRecord.Environment, configRecord.Schema, configRecord.Name) && freeze.ActiveAt(at) {
+				frozen[key] = append(frozen[key], freeze)
+			}
+		}
+	}
+
+	return frozen
+}
+
+// pinnedVersions returns the versions that references of the retained
+// versions pin by versionKey, and in turn those that references of the
+// pinned versions pin, as resolving a reference resolves the references of
+// the referenced version.
+func pinnedVersions(configRecords []domain.ConfigRecord, retained func(domain.ConfigRecord) bool) map[string]bool {
+	versions := map[string]domain.ConfigRecord{}
+	pending := []domain.ConfigRecord{}
+	for _, configRecord := range configRecords {
+		versions[versionKey(configRecord)] = configRecord
+		if retained(configRecord) {
+			pending = append(pending, configRecord)
+		}
+	}
+
+	pinned := map[string]bool{}
+	for len(pending) > 0 {
+		configRecord := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		// Stored versions hold valid references only
+		_ = findConfigRefs("", configRecord.Data, func(_ string, ref configRef) {
+			if ref.Version == 0 {
+				return
+			}
+			key := versionKey(domain.ConfigRecord{
+				Tenant:      configRecord.Tenant,
+				Environment: configRecord.Environment,
+				Schema:      ref.Schema,
+				Name:        ref.Name,
+				Version:     ref.Version,
+			})
+			if pinned[key] {
+				return
+			}
+			pinned[key] = true
+			if target, ok := versions[key]; ok && !retained(target) {
+				pending = append(pending, target)
+			}
+		})
+	}
+
+	return pinned
+}
+
+// effectiveData returns the data of configRecord merged with its ancestors,
+// at their latest version, with its references resolved and its placeholders
+// rendered.
+func (service *AdminServiceImpl) effectiveData(ctx context.Context, tx *sql.Tx, configRecord domain.ConfigRecord) (map[string]interface{}, error) {
+	resolver := newReferenceResolver(ctx, tx, service.ConfigRepository, configRecord.Tenant, configRecord.Environment)
+	resolver.variables = service.VariableRepository
+	data, err := resolver.effective(fmt.Sprintf("%s/%s@%d", configRecord.Schema, configRecord.Name, configRecord.Version), configRecord)
+	if err != nil {
+		return nil, err
+	}
+	data, _ = resolver.render(data)
+	return data, nil
+}
+
+// servedVersions keeps the version of every config fetches serve at the
+// given time, its active version, and the scheduled versions waiting for
+// their activation. configRecords must be ordered by tenant, environment,
+// schema, name and version.
+func servedVersions(configRecords []domain.ConfigRecord, at time.Time) []domain.ConfigRecord {
+	served := []domain.ConfigRecord{}
+	for i, configRecord := range configRecords {
+		if configRecord.Scheduled(at) {
+			served = append(served, configRecord)
+			continue
+		}
+		if !configRecord.Effective(at) {
+			continue
+		}
+
+		// Versions are active until a newer version takes effect
+		active := true
+		for _, next := range configRecords[i+1:] {
+			if configKey(next) != configKey(configRecord) {
+				break
+			}
+			if next.Effective(at) {
+				active = false
+				break
+			}
+		}
+		if active {
+			served = append(served, configRecord)
+		}
+	}
+
+	return served
+}
+
+// keptVersions returns the lowest version of every config to keep when
+// keeping the keep newest versions that took effect by the given time.
+// Configs with at most keep such versions are missing, they keep every
+// version. configRecords must be ordered by version.
+func keptVersions(configRecords []domain.ConfigRecord, keep int, at time.Time) map[string]int {
+	effective := map[string][]int{}
+	for _, configRecord := range configRecords {
+		if configRecord.Effective(at) {
+			key := configKey(configRecord)
+			effective[key] = append(effective[key], configRecord.Version)
+		}
+	}
+
+	kept := map[string]int{}
+	for key, versions := range effective {
+		if len(versions) > keep {
+			kept[key] = versions[len(versions)-keep]
+		}
+	}
+
+	return kept
+}
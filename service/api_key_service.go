@@ -0,0 +1,15 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/model/web"
+	"context"
+)
+
+// APIKeyService manages the API keys that authenticate callers.
+type APIKeyService interface {
+	CreateKey(ctx context.Context, request web.APIKeyCreateRequest) web.APIKeyCreateResponse
+	ListKeys(ctx context.Context) []web.APIKeyResponse
+	RevokeKey(ctx context.Context, id string)
+	Authenticate(ctx context.Context, key string) auth.Principal
+}
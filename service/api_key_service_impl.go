@@ -0,0 +1,142 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+// lastUsedResolution limits how often last_used_at is written for a key
+// that is used on every request.
+const lastUsedResolution = time.Minute
+
+type APIKeyServiceImpl struct {
+	APIKeyRepository repository.APIKeyRepository
+	DB               *sql.DB
+	Validate         *validator.Validate
+	Audit            AuditService
+}
+
+func NewAPIKeyService(apiKeyRepository repository.APIKeyRepository, DB *sql.DB, validate *validator.Validate, auditService AuditService) APIKeyService {
+	return &APIKeyServiceImpl{
+		APIKeyRepository: apiKeyRepository,
+		DB:               DB,
+		Validate:         validate,
+		Audit:            auditService,
+	}
+}
+
+func (service *APIKeyServiceImpl) CreateKey(ctx context.Context, request web.APIKeyCreateRequest) web.APIKeyCreateResponse {
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if request.Tenant != "" && !tenant.Valid(request.Tenant) {
+		helper.PanicIfError(helper.ValidationError{Msg: "invalid tenant name"})
+	}
+	if request.Tenant != "" && request.Admin {
+		helper.PanicIfError(helper.ValidationError{Msg: "admin keys cannot be bound to a tenant"})
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := service.APIKeyRepository.FindByName(ctx, tx, request.Name); err == nil {
+		panic(exception.NewConflictError("api key name already exist"))
+	}
+
+	key, prefix := auth.NewAPIKey()
+	apiKey := service.APIKeyRepository.Create(ctx, tx, domain.APIKey{
+		ID:        auth.NewKeyID(),
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashAPIKey(key),
+		Admin:     request.Admin,
+		Tenant:    request.Tenant,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditAPIKeyCreated,
+		Tenant: apiKey.Tenant,
+		Detail: fmt.Sprintf("key %s (%s) created, admin %t", apiKey.ID, apiKey.Name, apiKey.Admin),
+	})
+
+	slog.InfoContext(ctx, "api key created", "key_id", apiKey.ID, "key_name", apiKey.Name, "admin", apiKey.Admin, "tenant", apiKey.Tenant, "by", auth.Subject(ctx))
+
+	return web.APIKeyCreateResponse{
+		APIKeyResponse: helper.ToAPIKeyResponse(apiKey),
+		Key:            key,
+	}
+}
+
+func (service *APIKeyServiceImpl) ListKeys(ctx context.Context) []web.APIKeyResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	responses := []web.APIKeyResponse{}
+	for _, apiKey := range service.APIKeyRepository.FindAll(ctx, tx) {
+		responses = append(responses, helper.ToAPIKeyResponse(apiKey))
+	}
+
+	return responses
+}
+
+func (service *APIKeyServiceImpl) RevokeKey(ctx context.Context, id string) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if err := service.APIKeyRepository.Revoke(ctx, tx, id, time.Now().UTC()); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditAPIKeyRevoked,
+		Detail: fmt.Sprintf("key %s revoked", id),
+	})
+
+	slog.InfoContext(ctx, "api key revoked", "key_id", id, "by", auth.Subject(ctx))
+}
+
+// Authenticate resolves an API key into its principal and records its use.
+// Unknown and revoked keys are rejected with an UnauthorizedError.
+func (service *APIKeyServiceImpl) Authenticate(ctx context.Context, key string) auth.Principal {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	apiKey, err := service.APIKeyRepository.FindByHash(ctx, tx, auth.HashAPIKey(key))
+	if err != nil || apiKey.RevokedAt != nil {
+		panic(exception.NewUnauthorizedError("invalid api key"))
+	}
+
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		service.APIKeyRepository.TouchLastUsed(ctx, tx, apiKey.ID, now)
+	}
+
+	principal := auth.Principal{
+		Subject: "apikey:" + apiKey.Name,
+		Method:  auth.MethodAPIKey,
+		Tenant:  apiKey.Tenant,
+	}
+	if apiKey.Admin {
+		principal.Roles = []string{auth.RoleAdmin}
+	}
+
+	return principal
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"config-service/model/domain"
+	"config-service/model/web"
+	"context"
+	"database/sql"
+)
+
+// AuditService writes and reads the append-only, hash-chained audit log.
+type AuditService interface {
+	// Record appends event within tx, so that it is only kept when the
+	// audited change is committed. Actor and source default to the caller
+	// of ctx.
+	Record(ctx context.Context, tx *sql.Tx, event domain.AuditEvent)
+	// RecordDenied appends a request.denied event in its own transaction.
+	// It never panics because it runs while another error is handled.
+	RecordDenied(ctx context.Context, schema, name, reason string)
+	// RecordSchemaChanges audits schemas added, changed or removed since the
+	// last run, comparing domain.Schemas with the recorded hashes.
+	RecordSchemaChanges(ctx context.Context)
+	ListEvents(ctx context.Context, filter domain.AuditFilter) []web.AuditEventResponse
+	Verify(ctx context.Context) web.AuditVerifyResponse
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditServiceImpl struct {
+	AuditRepository repository.AuditRepository
+	DB              *sql.DB
+}
+
+func NewAuditService(auditRepository repository.AuditRepository, DB *sql.DB) AuditService {
+	return &AuditServiceImpl{
+		AuditRepository: auditRepository,
+		DB:              DB,
+	}
+}
+
+// auditHash chains an event to the previous one. Fields are quoted, so the
+// separating newlines cannot be forged by their content.
+func auditHash(event domain.AuditEvent) string {
+	fields := []string{
+		event.PrevHash,
+		event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		event.Actor,
+		event.Source,
+		event.Action,
+		event.Outcome,
+		event.Schema,
+		event.Name,
+		strconv.Itoa(event.Version),
+		event.BeforeHash,
+		event.AfterHash,
+		event.Detail,
+	}
+	// Entries recorded before tenants or environments were introduced have
+	// none
+	if event.Tenant != "" || event.Environment != "" {
+		fields = append(fields, event.Tenant)
+	}
+	if event.Environment != "" {
+		fields = append(fields, event.Environment)
+	}
+	for i, field := range fields {
+		fields[i] = strconv.Quote(field)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (service *AuditServiceImpl) Record(ctx context.Context, tx *sql.Tx, event domain.AuditEvent) {
+	if event.Actor == "" {
+		event.Actor = auth.Subject(ctx)
+	}
+	if event.Actor == "" {
+		event.Actor = "anonymous"
+	}
+	if event.Source == "" {
+		event.Source = auth.Source(ctx)
+	}
+	if event.Outcome == "" {
+		event.Outcome = domain.AuditSuccess
+	}
+	if event.Tenant == "" && event.Schema != "" {
+		event.Tenant = tenant.FromContext(ctx)
+	}
+	event.OccurredAt = time.Now().UTC()
+
+	// The audited change holds the write lock of the database, so no other
+	// entry can be appended between reading the last hash and the insert
+	event.PrevHash = service.AuditRepository.LastHash(ctx, tx)
+	event.Hash = auditHash(event)
+
+	service.AuditRepository.Insert(ctx, tx, event)
+}
+
+func (service *AuditServiceImpl) RecordDenied(ctx context.Context, schema, name, reason string) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.ErrorContext(ctx, "could not audit denied request", "error", err)
+		}
+	}()
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.Record(ctx, tx, domain.AuditEvent{
+		Action:  domain.AuditRequestDenied,
+		Outcome: domain.AuditDenied,
+		Schema:  schema,
+		Name:    name,
+		Detail:  reason,
+	})
+}
+
+func (service *AuditServiceImpl) RecordSchemaChanges(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	ctx = auth.WithPrincipal(ctx, auth.Principal{Subject: "system"})
+	recorded := service.AuditRepository.LatestSchemaHashes(ctx, tx)
+
+	for _, tenantName := range domain.Tenants() {
+		for _, name := range domain.SchemaNames(tenantName) {
+			schema, _ := domain.LookupSchema(tenantName, name)
+			sum := sha256.Sum256([]byte(schema))
+			hash := hex.EncodeToString(sum[:])
+
+			before, known := recorded[tenantName][name]
+			delete(recorded[tenantName], name)
+			if before == hash {
+				continue
+			}
+
+			action := domain.AuditSchemaChanged
+			if !known {
+				action = domain.AuditSchemaCreated
+			}
+			service.Record(ctx, tx, domain.AuditEvent{Action: action, Tenant: tenantName, Schema: name, BeforeHash: before, AfterHash: hash})
+			slog.InfoContext(ctx, "schema change audited", "tenant", tenantName, "schema", name, "action", action)
+		}
+	}
+
+	tenants := make([]string, 0, len(recorded))
+	for tenantName := range recorded {
+		tenants = append(tenants, tenantName)
+	}
+	sort.Strings(tenants)
+
+	for _, tenantName := range tenants {
+		names := make([]string, 0, len(recorded[tenantName]))
+		for name := range recorded[tenantName] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			service.Record(ctx, tx, domain.AuditEvent{Action: domain.AuditSchemaRemoved, Tenant: tenantName, Schema: name, BeforeHash: recorded[tenantName][name]})
+			slog.InfoContext(ctx, "schema change audited", "tenant", tenantName, "schema", name, "action", domain.AuditSchemaRemoved)
+		}
+	}
+}
+
+func (service *AuditServiceImpl) ListEvents(ctx context.Context, filter domain.AuditFilter) []web.AuditEventResponse {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("limit must not exceed %d", maxAuditLimit)})
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	responses := []web.AuditEventResponse{}
+	for _, event := range service.AuditRepository.Find(ctx, tx, filter) {
+		responses = append(responses, helper.ToAuditEventResponse(event))
+	}
+
+	return responses
+}
+
+// Verify recomputes the chain from the first entry. Editing an entry breaks
+// its hash, removing one breaks the link of the next entry.
+func (service *AuditServiceImpl) Verify(ctx context.Context) web.AuditVerifyResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	result := web.AuditVerifyResponse{Valid: true}
+	prevHash := ""
+	service.AuditRepository.Walk(ctx, tx, func(event domain.AuditEvent) bool {
+		result.Checked++
+
+		switch {
+		case event.PrevHash != prevHash:
+			result.Error = "entry is not linked to the previous entry"
+		case auditHash(event) != event.Hash:
+			result.Error = "entry does not match its hash"
+		default:
+			prevHash = event.Hash
+			return true
+		}
+
+		result.Valid = false
+		result.FirstInvalidID = event.ID
+		return false
+	})
+
+	if !result.Valid {
+		slog.ErrorContext(ctx, "audit log verification failed", "id", result.FirstInvalidID, "error", result.Error)
+	}
+
+	return result
+}
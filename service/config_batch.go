@@ -0,0 +1,150 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-playground/validator"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BatchUpdateConfigs stores a new version of every item in a single
+// transaction, creating the configs that do not exist yet. A failing item
+// rolls back the whole batch, its error naming the item.
+func (service *ConfigServiceImpl) BatchUpdateConfigs(ctx context.Context, request web.ConfigBatchRequest) web.ConfigBatchResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.BatchUpdateConfigs", attribute.Int("batch.size", len(request.Items)))
+	defer tracing.End(span)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	seen := map[string]bool{}
+	for index, item := range request.Items {
+		key := item.Schema + "/" + item.Name
+		if seen[key] {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("items[%d] repeats %s", index, key)})
+		}
+		seen[key] = true
+	}
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+
+	var configRecords []domain.ConfigRecord
+	defer func() {
+		if err := recover(); err != nil {
+			panic(err)
+		}
+		for index := range configRecords {
+			service.afterCommit(ctx, "config updated in batch", &configRecords[index])
+		}
+	}()
+	defer helper.CommitOrRollback(tx)
+
+	response := web.ConfigBatchResponse{Environment: environmentName, Configs: []web.ConfigResponse{}}
+	for index, item := range request.Items {
+		configRecord := service.writeBatchItem(ctx, tx, environmentName, index, item, request.Override, len(request.Items))
+		configRecords = append(configRecords, configRecord)
+		response.Configs = append(response.Configs, helper.ToConfigResponse(configRecord))
+	}
+
+	return response
+}
+
+// writeBatchItem stores the new version of an item of a batch of total
+// items, validated like a create or an update.
+func (service *ConfigServiceImpl) writeBatchItem(ctx context.Context, tx *sql.Tx, environmentName string, index int, item web.ConfigBatchItem, override string, total int) domain.ConfigRecord {
+	defer func() {
+		if err := recover(); err != nil {
+			panic(batchItemError(index, item, err))
+		}
+	}()
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, item.Schema, item.Name)
+	service.checkDirectWrite(ctx, item.Schema)
+
+	configRecord := domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      item.Schema,
+		Name:        item.Name,
+		Data:        item.Data,
+		CreatedBy:   auth.Subject(ctx),
+	}
+
+	latest, err := service.ConfigRepository.GetLatest(ctx, tx, configRecord)
+	exists := err == nil && latest.Version > 0
+
+	if item.ExpectedVersion != nil && *item.ExpectedVersion != latest.Version {
+		panic(exception.NewConflictError(fmt.Sprintf("expected version %d, the latest is %d", *item.ExpectedVersion, latest.Version)))
+	}
+
+	// Validate against schema, data keeping masked secret values once they
+	// have been restored
+	if hasMaskedSecrets(configRecord.Tenant, item.Schema, item.Data) {
+		if !exists {
+			helper.PanicIfError(helper.ValidationError{Msg: "masked secret values can only be kept by updates"})
+		}
+		configRecord.Data = keepMaskedSecrets(configRecord.Tenant, item.Schema, item.Data, latest.Data)
+	}
+	validateData(ctx, item.Schema, configRecord.Data)
+
+	action := domain.AuditConfigCreated
+	var frozen map[string]interface{}
+	if exists {
+		// Check whether the config is frozen
+		frozen = service.checkFreeze(ctx, tx, configRecord, override)
+		configRecord.Metadata = withFreezeOverride(configRecord.Metadata, frozen)
+		action = domain.AuditConfigUpdated
+	} else {
+		// Check the quota of the tenant
+		service.checkQuota(ctx, tx, configRecord.Tenant)
+	}
+
+	configRecord.Version = latest.Version + 1
+	service.validateEffectiveData(ctx, tx, configRecord, exists)
+
+	// Save new config version
+	configRecord = service.ConfigRepository.CreateNewVersion(ctx, tx, configRecord)
+	service.audit(ctx, tx, action, latest.Data, configRecord, overrideDetail(fmt.Sprintf("batch of %d configs", total), frozen))
+
+	return configRecord
+}
+
+// batchItemError prefixes the message of err with the item it failed for,
+// keeping the type of client errors so that they keep their status codes.
+func batchItemError(index int, item web.ConfigBatchItem, err interface{}) interface{} {
+	prefix := fmt.Sprintf("items[%d] %s/%s: ", index, item.Schema, item.Name)
+
+	switch e := err.(type) {
+	case exception.NotFoundError:
+		return exception.NewNotFoundError(prefix + e.Error)
+	case exception.ConflictError:
+		return exception.NewConflictError(prefix + e.Error)
+	case exception.UnauthorizedError:
+		return exception.NewUnauthorizedError(prefix + e.Error)
+	case exception.ForbiddenError:
+		return exception.NewForbiddenError(prefix + e.Error)
+	case exception.LockedError:
+		return exception.NewLockedError(prefix + e.Error)
+	case helper.ValidationError:
+		return helper.ValidationError{Msg: prefix + e.Msg}
+	case validator.ValidationErrors:
+		return helper.ValidationError{Msg: prefix + e.Error()}
+	}
+	return err
+}
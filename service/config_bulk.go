@@ -0,0 +1,122 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// BulkFetchConfigs fetches the configs of many selectors from a single
+// snapshot. Selectors failing like a single fetch would get an error in
+// their result instead of failing the others.
+func (service *ConfigServiceImpl) BulkFetchConfigs(ctx context.Context, request web.ConfigBulkFetchRequest) web.ConfigBulkResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.BulkFetchConfigs", attribute.Int("bulk.size", len(request.Selectors)))
+	defer tracing.End(span)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction, its reads all see the same snapshot
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	failed := 0
+	response := web.ConfigBulkResponse{Environment: environmentName, Results: []web.ConfigBulkResult{}}
+	for _, selector := range request.Selectors {
+		result := service.fetchSelected(ctx, tx, environmentName, selector, request)
+		if result.Error != "" {
+			failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	span.SetAttributes(attribute.Int("bulk.failed", failed))
+	return response
+}
+
+// fetchSelected fetches the config of a selector of a bulk fetch, recording
+// client errors in the result. Other errors fail the whole fetch.
+func (service *ConfigServiceImpl) fetchSelected(ctx context.Context, tx *sql.Tx, environmentName, selector string, request web.ConfigBulkFetchRequest) (result web.ConfigBulkResult) {
+	result.Selector = selector
+	defer func() {
+		if err := recover(); err != nil {
+			code := exception.StatusCode(err)
+			if code == 0 {
+				panic(err)
+			}
+			result.Error = exception.ErrorMessage(err)
+			result.Code = code
+		}
+	}()
+
+	ctx, span := tracing.Start(ctx, "config.fetch", attribute.String("config.selector", selector))
+	defer tracing.End(span)
+
+	schema, name, version, label := parseSelector(selector)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	if label != "" {
+		version = service.resolveLabel(ctx, tx, domain.ConfigRecord{
+			Tenant:      tenant.FromContext(ctx),
+			Environment: environmentName,
+			Schema:      schema,
+			Name:        name,
+		}, label)
+	}
+
+	config := service.fetchConfig(ctx, tx, environmentName, schema, name, web.ConfigFetchRequest{
+		Version: &version,
+		Resolve: request.Resolve,
+		Strict:  request.Strict,
+	})
+	result.Config = &config
+	return result
+}
+
+// parseSelector splits a schema/name[@version|label] selector, version
+// being 0 for the latest version or for the version named by label.
+func parseSelector(selector string) (string, string, int, string) {
+	schema, name, ok := strings.Cut(selector, "/")
+	if !ok || schema == "" || name == "" || strings.Contains(name, "/") {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("selector %q must be schema/name, schema/name@version or schema/name@label", selector)})
+	}
+
+	name, reference, versioned := strings.Cut(name, "@")
+	if !versioned {
+		return schema, name, 0, ""
+	}
+	if name == "" {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("selector %q must be schema/name, schema/name@version or schema/name@label", selector)})
+	}
+
+	// Labels start with a letter, anything else must be a version number
+	if labelPattern.MatchString(reference) {
+		return schema, name, 0, reference
+	}
+	version, err := strconv.Atoi(reference)
+	if err != nil || version <= 0 {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("selector %q must select a version by its number or a label", selector)})
+	}
+	return schema, name, version, ""
+}
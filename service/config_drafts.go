@@ -0,0 +1,383 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/diff"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// WithDrafts lets configs be changed through drafts that are published once
+// approved. Approvals is the number of approvals the drafts of a schema
+// need by approvalsKey, 1 for unlisted schemas; configs of listed schemas
+// can only be changed through drafts.
+func WithDrafts(draftRepository repository.DraftRepository, approvals map[string]int) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Drafts = draftRepository
+		service.Approvals = approvals
+	}
+}
+
+// approvalsKey keys the approvals of a schema of a tenant like the schema
+// files are laid out: schema for the default tenant, tenant/schema for the
+// others.
+func approvalsKey(tenantName, schema string) string {
+	if tenantName == tenant.Default {
+		return schema
+	}
+	return tenantName + "/" + schema
+}
+
+// requiredApprovals returns the number of approvals publishing a draft of
+// schema of the tenant.
+func (service *ConfigServiceImpl) requiredApprovals(tenantName, schema string) int {
+	return max(service.Approvals[approvalsKey(tenantName, schema)], 1)
+}
+
+// checkDirectWrite rejects writing versions of schema of the tenant of ctx
+// without a draft when its drafts need approval.
+func (service *ConfigServiceImpl) checkDirectWrite(ctx context.Context, schema string) {
+	if approvals := service.Approvals[approvalsKey(tenant.FromContext(ctx), schema)]; approvals > 0 {
+		panic(exception.NewForbiddenError(fmt.Sprintf("changes to %s configs require %d approval(s), propose a draft instead", schema, approvals)))
+	}
+}
+
+func (service *ConfigServiceImpl) toDraftResponse(draft domain.Draft) web.DraftResponse {
+	return helper.ToDraftResponse(draft, service.requiredApprovals(draft.Tenant, draft.Schema))
+}
+
+func (service *ConfigServiceImpl) ProposeConfig(ctx context.Context, schema, name string, request web.DraftCreateRequest) web.DraftResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ProposeConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Validate against schema, data keeping masked secret values once they
+	// have been restored
+	masked := hasMaskedSecrets(tenant.FromContext(ctx), schema, request.Data)
+	if masked {
+		helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+	} else {
+		validateData(ctx, schema, request.Data)
+	}
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	now := time.Now().UTC()
+	draft := domain.Draft{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Data:        request.Data,
+		Comment:     request.Comment,
+		Status:      domain.DraftPending,
+		CreatedAt:   now,
+		CreatedBy:   auth.Subject(ctx),
+		UpdatedAt:   now,
+	}
+
+	// Drafts of new configs are based on version 0
+	configRecord := domain.ConfigRecord{Tenant: draft.Tenant, Environment: environmentName, Schema: schema, Name: name, Data: draft.Data}
+	latest, err := service.ConfigRepository.GetLatest(ctx, tx, configRecord)
+	if err == nil {
+		draft.BaseVersion = latest.Version
+	}
+	if masked {
+		draft.Data = keepMaskedSecrets(draft.Tenant, schema, draft.Data, latest.Data)
+		validateData(ctx, schema, draft.Data)
+	}
+
+	// Drafts are validated as if they were published now
+	configRecord.Data = draft.Data
+	configRecord.Version = draft.BaseVersion + 1
+	service.validateEffectiveData(ctx, tx, configRecord, true)
+
+	draft = service.Drafts.Save(ctx, tx, draft)
+	service.auditDraft(ctx, tx, domain.AuditDraftProposed, draft, fmt.Sprintf("draft %d proposed on version %d", draft.ID, draft.BaseVersion))
+
+	slog.InfoContext(ctx, "draft proposed", "draft_id", draft.ID, "schema", schema, "name", name, "base_version", draft.BaseVersion)
+
+	return service.toDraftResponse(draft)
+}
+
+func (service *ConfigServiceImpl) ListDrafts(ctx context.Context, request web.DraftListRequest) []web.DraftResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListDrafts", tracing.ConfigAttributes(request.Schema, request.Name)...)
+	defer tracing.End(span)
+
+	// Authorize caller, drafts of other configs are left out
+	if request.Schema != "" {
+		service.RoleBindings.Authorize(ctx, auth.RoleReader, request.Schema, request.Name)
+	}
+
+	status := request.Status
+	if status == "" {
+		status = domain.DraftPending
+	}
+	if !slices.Contains([]string{domain.DraftPending, domain.DraftPublished, domain.DraftRejected, domain.DraftSuperseded}, status) {
+		helper.PanicIfError(helper.ValidationError{Msg: "status must be pending, published, rejected or superseded"})
+	}
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	drafts := service.Drafts.FindAll(ctx, tx, domain.DraftFilter{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      request.Schema,
+		Name:        request.Name,
+		Status:      status,
+	})
+
+	responses := []web.DraftResponse{}
+	for _, draft := range drafts {
+		if request.Schema == "" && !service.RoleBindings.Allowed(ctx, auth.RoleReader, draft.Schema, draft.Name) {
+			continue
+		}
+		responses = append(responses, service.toDraftResponse(draft))
+	}
+
+	return responses
+}
+
+func (service *ConfigServiceImpl) GetDraft(ctx context.Context, id int64) web.DraftResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.GetDraft")
+	defer tracing.End(span)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	draft := service.findDraft(ctx, tx, environmentName, id)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, draft.Schema, draft.Name)
+
+	// Reviewers see what the draft changes, with the secret values masked
+	var base domain.ConfigRecord
+	if draft.BaseVersion > 0 {
+		base, _ = service.ConfigRepository.GetByVersion(ctx, tx, domain.ConfigRecord{
+			Tenant:      draft.Tenant,
+			Environment: draft.Environment,
+			Schema:      draft.Schema,
+			Name:        draft.Name,
+			Version:     draft.BaseVersion,
+		})
+	}
+
+	response := service.toDraftResponse(draft)
+	response.Changes = maskChanges(draft.Tenant, draft.Schema, base.Data, draft.Data, diff.Compare(base.Data, draft.Data))
+	return response
+}
+
+// ApproveDraft records the approval of the caller and publishes the draft
+// as a new version once it has enough approvals. Drafts whose config changed
+// since they were proposed are superseded, along with the approval, and
+// fail with a conflict.
+func (service *ConfigServiceImpl) ApproveDraft(ctx context.Context, id int64, request web.DraftReviewRequest) web.DraftResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ApproveDraft")
+	defer tracing.End(span)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	draft := service.approveDraft(ctx, environmentName, id, request)
+	if draft.Status == domain.DraftSuperseded {
+		panic(exception.NewConflictError(fmt.Sprintf("%s/%s changed since draft %d was proposed on version %d, the draft is superseded, propose it again",
+			draft.Schema, draft.Name, draft.ID, draft.BaseVersion)))
+	}
+
+	return service.toDraftResponse(draft)
+}
+
+// approveDraft is ApproveDraft within a transaction, which is committed
+// when the draft is superseded too.
+func (service *ConfigServiceImpl) approveDraft(ctx context.Context, environmentName string, id int64, request web.DraftReviewRequest) domain.Draft {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+
+	var published domain.ConfigRecord
+	defer service.afterCommit(ctx, "config published", &published)
+	defer helper.CommitOrRollback(tx)
+
+	draft := service.findDraft(ctx, tx, environmentName, id)
+	review := service.review(ctx, tx, draft, domain.ReviewApproved, request.Comment)
+	draft.Reviews = append(draft.Reviews, review)
+	service.auditDraft(ctx, tx, domain.AuditDraftApproved, draft, fmt.Sprintf("draft %d approved", draft.ID))
+
+	approvers := draft.Approvals()
+	if len(approvers) < service.requiredApprovals(draft.Tenant, draft.Schema) {
+		return draft
+	}
+
+	// Publish the draft, unless its config changed since it was proposed
+	configRecord := domain.ConfigRecord{
+		Tenant:      draft.Tenant,
+		Environment: draft.Environment,
+		Schema:      draft.Schema,
+		Name:        draft.Name,
+		Data:        draft.Data,
+		CreatedBy:   draft.CreatedBy,
+		Metadata: map[string]interface{}{
+			"draft": map[string]interface{}{
+				"id":          draft.ID,
+				"approved_by": approvers,
+			},
+		},
+	}
+	latest, err := service.ConfigRepository.GetLatest(ctx, tx, configRecord)
+	if err != nil {
+		latest = domain.ConfigRecord{}
+	}
+	if latest.Version != draft.BaseVersion {
+		draft.Status = domain.DraftSuperseded
+		draft.UpdatedAt = time.Now().UTC()
+		service.Drafts.UpdateStatus(ctx, tx, draft)
+		service.auditDraft(ctx, tx, domain.AuditDraftSuperseded, draft, fmt.Sprintf("draft %d superseded by version %d", draft.ID, latest.Version))
+		return draft
+	}
+	if latest.Version == 0 {
+		service.checkQuota(ctx, tx, configRecord.Tenant)
+	}
+
+	// Check whether the config is frozen
+	override := service.checkFreeze(ctx, tx, configRecord, request.Override)
+	configRecord.Metadata = withFreezeOverride(configRecord.Metadata, override)
+
+	// The schema, ancestors and referenced configs may have changed too
+	configRecord.Version = latest.Version + 1
+	validateData(ctx, draft.Schema, configRecord.Data)
+	service.validateEffectiveData(ctx, tx, configRecord, true)
+
+	published = service.ConfigRepository.CreateNewVersion(ctx, tx, configRecord)
+	service.audit(ctx, tx, domain.AuditConfigPublished, latest.Data, published, overrideDetail(fmt.Sprintf("published draft %d", draft.ID), override))
+
+	draft.Status = domain.DraftPublished
+	draft.PublishedVersion = published.Version
+	draft.UpdatedAt = time.Now().UTC()
+	service.Drafts.UpdateStatus(ctx, tx, draft)
+
+	return draft
+}
+
+// RejectDraft records the rejection of the caller and closes the draft.
+func (service *ConfigServiceImpl) RejectDraft(ctx context.Context, id int64, request web.DraftReviewRequest) web.DraftResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.RejectDraft")
+	defer tracing.End(span)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	if request.Comment == "" {
+		helper.PanicIfError(helper.ValidationError{Msg: "rejections need a comment"})
+	}
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	draft := service.findDraft(ctx, tx, environmentName, id)
+	review := service.review(ctx, tx, draft, domain.ReviewRejected, request.Comment)
+	draft.Reviews = append(draft.Reviews, review)
+
+	draft.Status = domain.DraftRejected
+	draft.UpdatedAt = time.Now().UTC()
+	service.Drafts.UpdateStatus(ctx, tx, draft)
+	service.auditDraft(ctx, tx, domain.AuditDraftRejected, draft, fmt.Sprintf("draft %d rejected", draft.ID))
+
+	return service.toDraftResponse(draft)
+}
+
+func (service *ConfigServiceImpl) findDraft(ctx context.Context, tx *sql.Tx, environmentName string, id int64) domain.Draft {
+	draft, err := service.Drafts.FindByID(ctx, tx, tenant.FromContext(ctx), environmentName, id)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	return draft
+}
+
+// review records the decision of the caller on a pending draft. Authors
+// can't review their own drafts and reviewers decide once, unless the
+// requests are anonymous.
+func (service *ConfigServiceImpl) review(ctx context.Context, tx *sql.Tx, draft domain.Draft, decision, comment string) domain.DraftReview {
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleApprover, draft.Schema, draft.Name)
+
+	if draft.Status != domain.DraftPending {
+		panic(exception.NewConflictError(fmt.Sprintf("draft %d is %s", draft.ID, draft.Status)))
+	}
+
+	reviewer := auth.Subject(ctx)
+	if reviewer != "" {
+		if reviewer == draft.CreatedBy {
+			panic(exception.NewForbiddenError(fmt.Sprintf("%s can't review its own draft", reviewer)))
+		}
+		if slices.ContainsFunc(draft.Reviews, func(review domain.DraftReview) bool { return review.Reviewer == reviewer }) {
+			panic(exception.NewConflictError(fmt.Sprintf("%s already reviewed draft %d", reviewer, draft.ID)))
+		}
+	}
+
+	slog.InfoContext(ctx, "draft reviewed", "draft_id", draft.ID, "decision", decision, "by", reviewer)
+
+	return service.Drafts.AddReview(ctx, tx, draft.ID, domain.DraftReview{
+		Reviewer:  reviewer,
+		Decision:  decision,
+		Comment:   comment,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// auditDraft records a change of a draft in the audit log, if auditing is
+// enabled.
+func (service *ConfigServiceImpl) auditDraft(ctx context.Context, tx *sql.Tx, action string, draft domain.Draft, detail string) {
+	if service.Audit == nil {
+		return
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action:      action,
+		Tenant:      draft.Tenant,
+		Environment: draft.Environment,
+		Schema:      draft.Schema,
+		Name:        draft.Name,
+		Version:     draft.BaseVersion,
+		AfterHash:   helper.DataHash(draft.Data),
+		Detail:      detail,
+	})
+}
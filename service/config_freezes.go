@@ -0,0 +1,114 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/model/domain"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"time"
+)
+
+// WithFreezes locks configs matching active freezes against updates,
+// rollbacks, promotions, cancellations and draft publications, unless they
+// are overridden with a reason.
+func WithFreezes(freezeService FreezeService) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Freezes = freezeService
+	}
+}
+
+// checkFreeze panics with a LockedError when freezes lock configRecord now
+// or, for scheduled versions, when it becomes active, unless the caller
+// overrides them with a reason, which takes the approver role. It returns
+// the override to record with the change, nil when no freeze is active.
+func (service *ConfigServiceImpl) checkFreeze(ctx context.Context, tx *sql.Tx, configRecord domain.ConfigRecord, override string) map[string]interface{} {
+	if service.Freezes == nil {
+		return nil
+	}
+
+	freezes := service.Freezes.Active(ctx, tx, configRecord, time.Now())
+	if configRecord.EffectiveAt != nil {
+		for _, freeze := range service.Freezes.Active(ctx, tx, configRecord, *configRecord.EffectiveAt) {
+			if !containsFreeze(freezes, freeze.ID) {
+				freezes = append(freezes, freeze)
+			}
+		}
+	}
+	if len(freezes) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(freezes))
+	for _, freeze := range freezes {
+		reasons = append(reasons, fmt.Sprintf("freeze %d (%s)", freeze.ID, freeze.Reason))
+	}
+
+	if override == "" {
+		panic(exception.NewLockedError(fmt.Sprintf("%s/%s is locked by %s, changing it requires an override with a reason",
+			configRecord.Schema, configRecord.Name, strings.Join(reasons, ", "))))
+	}
+	service.RoleBindings.Authorize(ctx, auth.RoleApprover, configRecord.Schema, configRecord.Name)
+
+	overridden := freezeOverride(ctx, freezes, override)
+	slog.WarnContext(ctx, "freeze overridden", "tenant", configRecord.Tenant, "environment", configRecord.Environment,
+		"schema", configRecord.Schema, "name", configRecord.Name, "freezes", overridden["freezes"], "reason", override, "by", auth.Subject(ctx))
+
+	return overridden
+}
+
+// freezeOverride returns the override of freezes by the caller with reason,
+// as recorded with the changes it allows.
+func freezeOverride(ctx context.Context, freezes []domain.Freeze, reason string) map[string]interface{} {
+	// Metadata holds JSON values, which protobuf structs can convert
+	ids := make([]interface{}, 0, len(freezes))
+	for _, freeze := range freezes {
+		ids = append(ids, freeze.ID)
+	}
+
+	return map[string]interface{}{
+		"reason":  reason,
+		"by":      auth.Subject(ctx),
+		"freezes": ids,
+	}
+}
+
+func containsFreeze(freezes []domain.Freeze, id int64) bool {
+	for _, freeze := range freezes {
+		if freeze.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// withFreezeOverride records override in the metadata of a new version.
+func withFreezeOverride(metadata map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	if override == nil {
+		return metadata
+	}
+
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["freeze_override"] = override
+	return metadata
+}
+
+// overrideDetail adds override to the detail of an audit event.
+func overrideDetail(detail string, override map[string]interface{}) string {
+	if override == nil {
+		return detail
+	}
+
+	overridden := fmt.Sprintf("overrode freezes %v: %s", override["freezes"], override["reason"])
+	if detail == "" {
+		return overridden
+	}
+	return detail + ", " + overridden
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+)
+
+// labelPattern starts labels with a letter, keeping them apart from version
+// numbers in schema/name@ref selectors.
+var labelPattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,62}$`)
+
+// WithLabels lets versions of configs be named by labels, like stable, that
+// fetches and promotions can select them by. Without it selecting versions
+// by label is rejected.
+func WithLabels(labelRepository repository.LabelRepository) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Labels = labelRepository
+	}
+}
+
+func (service *ConfigServiceImpl) ListLabels(ctx context.Context, schema, name string) []web.ConfigLabelResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListLabels", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+	service.checkLabels()
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	labels := service.Labels.FindAll(ctx, tx, domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+	})
+
+	responses := []web.ConfigLabelResponse{}
+	for _, label := range labels {
+		responses = append(responses, helper.ToConfigLabelResponse(label))
+	}
+	return responses
+}
+
+func (service *ConfigServiceImpl) SetLabel(ctx context.Context, schema, name, label string, request web.ConfigLabelRequest) web.ConfigLabelResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.SetLabel", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkLabels()
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	validateLabel(label)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecord, err := service.ConfigRepository.GetByVersion(ctx, tx, domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Version:     request.Version,
+	})
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+	if configRecord.CancelledAt != nil {
+		panic(exception.NewConflictError(fmt.Sprintf("version %d is cancelled and cannot be labeled", configRecord.Version)))
+	}
+
+	// Check whether the config is frozen, moving a label changes what is
+	// served to the fetches selecting it
+	override := service.checkFreeze(ctx, tx, domain.ConfigRecord{
+		Tenant:      configRecord.Tenant,
+		Environment: configRecord.Environment,
+		Schema:      schema,
+		Name:        name,
+	}, request.Override)
+
+	configLabel := domain.ConfigLabel{
+		Tenant:      configRecord.Tenant,
+		Environment: configRecord.Environment,
+		Schema:      schema,
+		Name:        name,
+		Label:       label,
+		Version:     configRecord.Version,
+		UpdatedAt:   time.Now().UTC(),
+		UpdatedBy:   auth.Subject(ctx),
+	}
+
+	detail := fmt.Sprintf("label %s set to version %d", label, configLabel.Version)
+	if previous, err := service.Labels.Find(ctx, tx, configLabel); err == nil {
+		detail = fmt.Sprintf("label %s moved from version %d to version %d", label, previous.Version, configLabel.Version)
+	}
+
+	configLabel = service.Labels.Save(ctx, tx, configLabel)
+	service.audit(ctx, tx, domain.AuditLabelSet, nil, configRecord, overrideDetail(detail, override))
+
+	slog.InfoContext(ctx, "label set", "tenant", configLabel.Tenant, "environment", configLabel.Environment, "schema", schema, "name", name,
+		"label", label, "version", configLabel.Version, "by", configLabel.UpdatedBy)
+
+	return helper.ToConfigLabelResponse(configLabel)
+}
+
+func (service *ConfigServiceImpl) DeleteLabel(ctx context.Context, schema, name, label, override string) {
+	ctx, span := tracing.Start(ctx, "ConfigService.DeleteLabel", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkLabels()
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configLabel, err := service.Labels.Find(ctx, tx, domain.ConfigLabel{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Label:       label,
+	})
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	configRecord := domain.ConfigRecord{
+		Tenant:      configLabel.Tenant,
+		Environment: configLabel.Environment,
+		Schema:      schema,
+		Name:        name,
+	}
+	overridden := service.checkFreeze(ctx, tx, configRecord, override)
+
+	err = service.Labels.Delete(ctx, tx, configLabel)
+	helper.PanicIfError(err)
+
+	configRecord.Version = configLabel.Version
+	service.audit(ctx, tx, domain.AuditLabelDeleted, nil, configRecord,
+		overrideDetail(fmt.Sprintf("label %s deleted from version %d", label, configLabel.Version), overridden))
+
+	slog.InfoContext(ctx, "label deleted", "tenant", configLabel.Tenant, "environment", configLabel.Environment, "schema", schema, "name", name,
+		"label", label, "by", auth.Subject(ctx))
+}
+
+// resolveLabel returns the version a label of a config names.
+func (service *ConfigServiceImpl) resolveLabel(ctx context.Context, tx *sql.Tx, configRecord domain.ConfigRecord, label string) int {
+	service.checkLabels()
+
+	configLabel, err := service.Labels.Find(ctx, tx, domain.ConfigLabel{
+		Tenant:      configRecord.Tenant,
+		Environment: configRecord.Environment,
+		Schema:      configRecord.Schema,
+		Name:        configRecord.Name,
+		Label:       label,
+	})
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("label %s of %s/%s is not found", label, configRecord.Schema, configRecord.Name)))
+	}
+
+	return configLabel.Version
+}
+
+// checkLabels rejects selecting versions by label when labels are not
+// enabled.
+func (service *ConfigServiceImpl) checkLabels() {
+	if service.Labels == nil {
+		helper.PanicIfError(helper.ValidationError{Msg: "version labels are not enabled"})
+	}
+}
+
+func validateLabel(label string) {
+	if !labelPattern.MatchString(label) {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("invalid label %q, labels start with a lowercase letter followed by at most 62 lowercase letters, digits, '.', '_' or '-'", label)})
+	}
+}
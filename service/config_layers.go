@@ -0,0 +1,156 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// parentOf returns the name of the config that data inherits from, "" for
+// none.
+func parentOf(data map[string]interface{}) (string, error) {
+	value, ok := data[domain.ParentKey]
+	if !ok {
+		return "", nil
+	}
+
+	parent, ok := value.(string)
+	if !ok || parent == "" {
+		return "", helper.ValidationError{Msg: domain.ParentKey + " must be the name of a config"}
+	}
+	return parent, nil
+}
+
+// resolveLayers returns configRecord followed by its ancestors, nearest
+// first. lookup returns the latest version of a config of the same schema.
+func resolveLayers(configRecord domain.ConfigRecord, lookup func(name string) (domain.ConfigRecord, bool)) ([]domain.ConfigRecord, error) {
+	layers := []domain.ConfigRecord{configRecord}
+	chain := []string{configRecord.Name}
+
+	for {
+		parent, err := parentOf(layers[len(layers)-1].Data)
+		if err != nil || parent == "" {
+			return layers, err
+		}
+
+		if slices.Contains(chain, parent) {
+			return nil, helper.ValidationError{Msg: "config inheritance cycle: " + strings.Join(append(chain, parent), " -> ")}
+		}
+
+		layer, ok := lookup(parent)
+		if !ok {
+			return nil, helper.ValidationError{Msg: fmt.Sprintf("parent config %s of %s doesn't exist", parent, chain[len(chain)-1])}
+		}
+
+		layers = append(layers, layer)
+		chain = append(chain, parent)
+	}
+}
+
+// mergeLayers returns the effective data of the layers returned by
+// resolveLayers, applying every layer to its parent.
+func mergeLayers(layers []domain.ConfigRecord) map[string]interface{} {
+	var merged map[string]interface{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		merged = helper.MergeDocuments(merged, layers[i].Data)
+	}
+	delete(merged, domain.ParentKey)
+
+	return merged
+}
+
+// referenceResolver returns a resolver of the configs of the caller,
+// checking that it may read the referenced ones.
+func (service *ConfigServiceImpl) referenceResolver(ctx context.Context, tx *sql.Tx, environmentName string) *referenceResolver {
+	resolver := newReferenceResolver(ctx, tx, service.ConfigRepository, tenant.FromContext(ctx), environmentName)
+	resolver.variables = service.Variables
+	resolver.authorize = func(schema, name string) {
+		service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+	}
+	return resolver
+}
+
+// validateEffectiveData validates the effective data of configRecord, about
+// to become the latest version of its config, when it inherits from another
+// config, references other configs or holds placeholders, rendered with the
+// variables of the environment. With descendants it also validates every
+// config inheriting from it. Other data is validated by the caller.
+func (service *ConfigServiceImpl) validateEffectiveData(ctx context.Context, tx *sql.Tx, configRecord domain.ConfigRecord, descendants bool) {
+	derived, err := isDerived(configRecord.Data)
+	helper.PanicIfError(err)
+	if !derived && !descendants {
+		return
+	}
+
+	resolver := service.referenceResolver(ctx, tx, configRecord.Environment)
+	resolver.pending = &configRecord
+
+	if derived {
+		data, err := resolver.effective(configRecord.Schema+"/"+configRecord.Name, configRecord)
+		helper.PanicIfError(err)
+		data, _ = resolver.render(data)
+		validateAgainstSchema(ctx, configRecord.Schema, data)
+	}
+
+	if !descendants {
+		return
+	}
+
+	for _, record := range service.ConfigRepository.ListLatest(ctx, tx, configRecord) {
+		if record.Name == configRecord.Name {
+			continue
+		}
+
+		// Broken chains and references not involving configRecord are
+		// reported on fetch
+		layers, err := resolver.layers(record)
+		if err != nil || !slices.ContainsFunc(layers, func(layer domain.ConfigRecord) bool { return layer.Name == configRecord.Name }) {
+			continue
+		}
+		data, err := resolver.effective(record.Schema+"/"+record.Name, record)
+		if err != nil {
+			continue
+		}
+		data, _ = resolver.render(data)
+
+		schema := helper.ValidateSchemaExistence(tenant.FromContext(ctx), configRecord.Schema)
+		if err := helper.CheckAgainstSchema(schema, data); err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("config %s inherits from %s: %s", record.Name, configRecord.Name, err.Error())})
+		}
+	}
+}
+
+// isDerived reports whether the effective data of a config with data depends
+// on other configs or on variables, rejecting invalid parents and references.
+func isDerived(data map[string]interface{}) (bool, error) {
+	parent, err := parentOf(data)
+	if err != nil {
+		return false, err
+	}
+	refs, err := hasConfigRefs(data)
+	if err != nil {
+		return false, err
+	}
+	return parent != "" || refs || hasPlaceholders(data), nil
+}
+
+// validateData validates data against schema unless it inherits from another
+// config, references other configs or holds placeholders. The effective data
+// of those is validated by validateEffectiveData once the other configs and
+// the variables have been read.
+func validateData(ctx context.Context, schema string, data map[string]interface{}) {
+	derived, err := isDerived(data)
+	helper.PanicIfError(err)
+
+	if derived {
+		helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+		return
+	}
+	validateAgainstSchema(ctx, schema, data)
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configRef is a parsed "schema/name[@version][#pointer]" reference.
+type configRef struct {
+	Schema string
+	Name   string
+	// Version pins the reference, 0 follows the latest version
+	Version int
+	// Pointer is the JSON pointer (RFC 6901) of the value, "" for the whole
+	// document
+	Pointer string
+}
+
+// key identifies the referenced document while resolving.
+func (ref configRef) key() string {
+	if ref.Version > 0 {
+		return fmt.Sprintf("%s/%s@%d", ref.Schema, ref.Name, ref.Version)
+	}
+	return ref.Schema + "/" + ref.Name
+}
+
+func (ref configRef) String() string {
+	if ref.Pointer != "" {
+		return ref.key() + "#" + ref.Pointer
+	}
+	return ref.key()
+}
+
+func parseConfigRef(value string) (configRef, error) {
+	invalid := func(reason string) (configRef, error) {
+		return configRef{}, helper.ValidationError{Msg: fmt.Sprintf("invalid %s %q: %s", domain.ReferenceKey, value, reason)}
+	}
+
+	target, pointer, _ := strings.Cut(value, "#")
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return invalid("the pointer must start with /")
+	}
+
+	ref := configRef{Pointer: pointer}
+	target, version, pinned := strings.Cut(target, "@")
+	if pinned {
+		v, err := strconv.Atoi(version)
+		if err != nil || v <= 0 {
+			return invalid("the version must be a positive integer")
+		}
+		ref.Version = v
+	}
+
+	schema, name, ok := strings.Cut(target, "/")
+	if !ok || schema == "" || name == "" || strings.Contains(name, "/") {
+		return invalid("expected schema/name[@version][#pointer]")
+	}
+	ref.Schema, ref.Name = schema, name
+
+	return ref, nil
+}
+
+// asConfigRef returns the reference of a {"$configRef": "..."} object, and
+// whether value is one.
+func asConfigRef(value map[string]interface{}) (configRef, bool, error) {
+	target, ok := value[domain.ReferenceKey]
+	if !ok {
+		return configRef{}, false, nil
+	}
+
+	text, ok := target.(string)
+	if !ok || len(value) != 1 {
+		return configRef{}, true, helper.ValidationError{Msg: domain.ReferenceKey + " objects must only hold a reference string"}
+	}
+
+	ref, err := parseConfigRef(text)
+	return ref, true, err
+}
+
+// findConfigRefs calls fn with the JSON pointer and the reference of every
+// reference object in value, stopping at the first invalid one.
+func findConfigRefs(pointer string, value interface{}, fn func(pointer string, ref configRef)) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ref, ok, err := asConfigRef(v)
+		if err != nil {
+			return err
+		}
+		if ok {
+			fn(pointer, ref)
+			return nil
+		}
+
+		for key, item := range v {
+			if err := findConfigRefs(pointer+"/"+escapePointerToken(key), item, fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := findConfigRefs(pointer+"/"+strconv.Itoa(i), item, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasConfigRefs reports whether data holds reference objects, rejecting
+// invalid ones.
+func hasConfigRefs(data map[string]interface{}) (bool, error) {
+	found := false
+	err := findConfigRefs("", data, func(string, configRef) { found = true })
+	return found, err
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// lookupPointer returns the value at pointer within document.
+func lookupPointer(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+
+	value := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			item, ok := v[token]
+			if !ok {
+				return nil, false
+			}
+			value = item
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// referenceResolver computes the effective data of configs of a tenant and
+// environment within a transaction: the data merged with the ancestors of
+// the config and with references replaced by the referenced values.
+type referenceResolver struct {
+	ctx         context.Context
+	tx          *sql.Tx
+	repository  repository.ConfigRepository
+	tenant      string
+	environment string
+	// authorize panics unless the caller may read a referenced config, nil
+	// allows every config
+	authorize func(schema, name string)
+	// pending is about to become the latest version of its config
+	pending *domain.ConfigRecord
+	// resolving holds the keys of the documents being resolved, outermost
+	// first, to detect cycles
+	resolving []string
+	documents map[string]map[string]interface{}
+	// variables holds the variables placeholders are rendered with, nil
+	// renders none
+	variables repository.VariableRepository
+	values    map[string]interface{}
+	// read holds the schema/name of the configs read at their latest
+	// version, whose changes can change the resolved documents
+	read map[string]bool
+}
+
+func newReferenceResolver(ctx context.Context, tx *sql.Tx, configRepository repository.ConfigRepository, tenantName, environmentName string) *referenceResolver {
+	return &referenceResolver{
+		ctx:         ctx,
+		tx:          tx,
+		repository:  configRepository,
+		tenant:      tenantName,
+		environment: environmentName,
+		documents:   map[string]map[string]interface{}{},
+		read:        map[string]bool{},
+	}
+}
+
+func (resolver *referenceResolver) latest(schema, name string) (domain.ConfigRecord, bool) {
+	resolver.read[schema+"/"+name] = true
+	if pending := resolver.pending; pending != nil && pending.Schema == schema && pending.Name == name {
+		return *pending, true
+	}
+
+	configRecord, err := resolver.repository.GetActive(resolver.ctx, resolver.tx, domain.ConfigRecord{
+		Tenant:      resolver.tenant,
+		Environment: resolver.environment,
+		Schema:      schema,
+		Name:        name,
+	}, time.Now())
+	return configRecord, err == nil
+}
+
+// layers returns configRecord followed by its ancestors, nearest first.
+func (resolver *referenceResolver) layers(configRecord domain.ConfigRecord) ([]domain.ConfigRecord, error) {
+	return resolveLayers(configRecord, func(name string) (domain.ConfigRecord, bool) {
+		return resolver.latest(configRecord.Schema, name)
+	})
+}
+
+// merged returns the data of configRecord merged with its ancestors.
+func (resolver *referenceResolver) merged(configRecord domain.ConfigRecord) (map[string]interface{}, error) {
+	layers, err := resolver.layers(configRecord)
+	if err != nil || len(layers) == 1 {
+		return configRecord.Data, err
+	}
+	return mergeLayers(layers), nil
+}
+
+// effective returns the data of configRecord, identified by key while
+// resolving, merged with its ancestors and with its references resolved.
+func (resolver *referenceResolver) effective(key string, configRecord domain.ConfigRecord) (map[string]interface{}, error) {
+	data, err := resolver.merged(configRecord)
+	if err != nil {
+		return nil, err
+	}
+	return resolver.resolveDocument(key, data)
+}
+
+// resolveDocument replaces the references within data, which belongs to the
+// document identified by key.
+func (resolver *referenceResolver) resolveDocument(key string, data map[string]interface{}) (map[string]interface{}, error) {
+	resolver.resolving = append(resolver.resolving, key)
+	defer func() { resolver.resolving = resolver.resolving[:len(resolver.resolving)-1] }()
+
+	resolved, err := resolver.resolveValue(data)
+	if err != nil {
+		return nil, err
+	}
+
+	document, _ := resolved.(map[string]interface{})
+	return document, nil
+}
+
+func (resolver *referenceResolver) resolveValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		ref, ok, err := asConfigRef(v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return resolver.resolveRef(ref)
+		}
+
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if resolved[key], err = resolver.resolveValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			var err error
+			if resolved[i], err = resolver.resolveValue(item); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func (resolver *referenceResolver) resolveRef(ref configRef) (interface{}, error) {
+	key := ref.key()
+	if slices.Contains(resolver.resolving, key) {
+		return nil, helper.ValidationError{Msg: "config reference cycle: " + strings.Join(append(slices.Clone(resolver.resolving), key), " -> ")}
+	}
+
+	if resolver.authorize != nil {
+		resolver.authorize(ref.Schema, ref.Name)
+	}
+
+	document, ok := resolver.documents[key]
+	if !ok {
+		var configRecord domain.ConfigRecord
+		if ref.Version > 0 {
+			var err error
+			configRecord, err = resolver.repository.GetByVersion(resolver.ctx, resolver.tx, domain.ConfigRecord{
+				Tenant:      resolver.tenant,
+				Environment: resolver.environment,
+				Schema:      ref.Schema,
+				Name:        ref.Name,
+				Version:     ref.Version,
+			})
+			ok = err == nil
+		} else {
+			configRecord, ok = resolver.latest(ref.Schema, ref.Name)
+		}
+		if !ok {
+			return nil, helper.ValidationError{Msg: fmt.Sprintf("referenced config %s doesn't exist", key)}
+		}
+
+		var err error
+		if document, err = resolver.effective(key, configRecord); err != nil {
+			return nil, err
+		}
+		resolver.documents[key] = document
+	}
+
+	value, ok := lookupPointer(document, ref.Pointer)
+	if !ok {
+		return nil, helper.ValidationError{Msg: fmt.Sprintf("referenced config %s has no value at %s", key, ref.Pointer)}
+	}
+	if err := checkSecretReference(resolver.tenant, ref.Schema, document, ref, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// render renders the placeholders within data with the variables of the
+// environment, returning the names of the undefined ones.
+func (resolver *referenceResolver) render(data map[string]interface{}) (map[string]interface{}, []string) {
+	if resolver.values == nil {
+		resolver.values = map[string]interface{}{}
+		if resolver.variables != nil {
+			for _, variable := range resolver.variables.FindAll(resolver.ctx, resolver.tx, resolver.tenant, resolver.environment) {
+				resolver.values[variable.Name] = variable.Value
+			}
+		}
+	}
+	return renderDocument(data, resolver.values)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/tenant"
+	"config-service/tracing"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// validateEffectiveAt rejects activation times that already passed and
+// returns the time in UTC, nil for versions active once created.
+func validateEffectiveAt(effectiveAt *time.Time) *time.Time {
+	if effectiveAt == nil {
+		return nil
+	}
+	if !effectiveAt.After(time.Now()) {
+		helper.PanicIfError(helper.ValidationError{Msg: "effective_at must be in the future"})
+	}
+
+	at := effectiveAt.UTC()
+	return &at
+}
+
+// scheduleDetail describes the activation of a scheduled version for the
+// audit log.
+func scheduleDetail(configRecord domain.ConfigRecord) string {
+	if configRecord.EffectiveAt == nil {
+		return ""
+	}
+	return "effective at " + configRecord.EffectiveAt.Format(time.RFC3339)
+}
+
+func (service *ConfigServiceImpl) CancelConfig(ctx context.Context, schema, name string, request web.ConfigCancelRequest) web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.CancelConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecord, err := service.ConfigRepository.GetByVersion(ctx, tx, domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Version:     request.Version,
+	})
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	// Only versions still waiting for their activation can be cancelled
+	now := time.Now().UTC()
+	switch {
+	case configRecord.CancelledAt != nil:
+		panic(exception.NewConflictError(fmt.Sprintf("version %d is already cancelled", configRecord.Version)))
+	case !configRecord.Scheduled(now):
+		panic(exception.NewConflictError(fmt.Sprintf("version %d is not scheduled, it is already active", configRecord.Version)))
+	}
+
+	// Check whether the config is frozen, cancellations change what is
+	// served once the version would have become active
+	override := service.checkFreeze(ctx, tx, configRecord, request.Override)
+
+	configRecord.CancelledAt = &now
+	service.ConfigRepository.CancelVersion(ctx, tx, configRecord)
+	service.audit(ctx, tx, domain.AuditConfigCancelled, nil, configRecord, overrideDetail("cancelled the activation "+scheduleDetail(configRecord), override))
+
+	slog.InfoContext(ctx, "scheduled config cancelled", "tenant", configRecord.Tenant, "environment", configRecord.Environment, "schema", schema, "name", name, "version", configRecord.Version)
+
+	return helper.ToConfigResponse(configRecord)
+}
+
+func (service *ConfigServiceImpl) PublishActivations(ctx context.Context, after, until time.Time) int {
+	ctx, span := tracing.Start(ctx, "ConfigService.PublishActivations")
+	defer tracing.End(span)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	var activated []domain.ConfigRecord
+	for _, configRecord := range service.ConfigRepository.ListActivations(ctx, tx, after, until) {
+		active, err := service.ConfigRepository.GetActive(ctx, tx, configRecord, until)
+		if err != nil || active.Version != configRecord.Version {
+			continue
+		}
+		activated = append(activated, configRecord)
+	}
+
+	for _, configRecord := range activated {
+		slog.InfoContext(ctx, "scheduled config activated", "tenant", configRecord.Tenant, "environment", configRecord.Environment, "schema", configRecord.Schema, "name", configRecord.Name, "version", configRecord.Version)
+		service.Watcher.Publish(configRecord)
+	}
+
+	return len(activated)
+}
+
+// RunScheduler publishes the activations of scheduled versions to the
+// watchers of their configs, checking every interval until ctx is done.
+// Every instance of the service notifies its own watchers.
+func RunScheduler(ctx context.Context, configService ConfigService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		if publishActivations(ctx, configService, last, now) {
+			last = now
+		}
+	}
+}
+
+// publishActivations reports whether the activations were published, the
+// scheduler retries the same period on the next tick otherwise.
+func publishActivations(ctx context.Context, configService ConfigService, after, until time.Time) (published bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.ErrorContext(ctx, "could not publish scheduled configs", "error", err)
+		}
+	}()
+
+	configService.PublishActivations(ctx, after, until)
+	return true
+}
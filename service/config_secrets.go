@@ -0,0 +1,101 @@
+package service
+
+import (
+	"config-service/diff"
+	"config-service/helper"
+	"config-service/secret"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// hasMaskedSecrets reports whether data holds masks at the paths schema
+// marks secret, as sent back by callers that fetched the config.
+func hasMaskedSecrets(tenantName, schema string, data map[string]interface{}) bool {
+	masked := false
+	secret.Transform(data, helper.SecretPaths(tenantName, schema), func(_ string, value interface{}) (interface{}, error) {
+		masked = masked || value == secret.Mask
+		return value, nil
+	})
+	return masked
+}
+
+// keepMaskedSecrets returns data with the masks at secret paths replaced by
+// the values at the same paths of stored, so that fetched configs can be
+// updated without revealing their secrets.
+func keepMaskedSecrets(tenantName, schema string, data, stored map[string]interface{}) map[string]interface{} {
+	kept, err := secret.Transform(data, helper.SecretPaths(tenantName, schema), func(pointer string, value interface{}) (interface{}, error) {
+		if value != secret.Mask {
+			return value, nil
+		}
+		storedValue, ok := lookupPointer(stored, pointer)
+		if !ok || storedValue == nil {
+			return nil, helper.ValidationError{Msg: fmt.Sprintf("no secret value is stored at %s to keep", pointer)}
+		}
+		return storedValue, nil
+	})
+	helper.PanicIfError(err)
+
+	return kept
+}
+
+// maskChanges masks the old and new values of the changes between base and
+// target below the paths schema marks secret.
+func maskChanges(tenantName, schema string, base, target map[string]interface{}, changes []diff.Change) []diff.Change {
+	paths := helper.SecretPaths(tenantName, schema)
+	if len(paths) == 0 {
+		return changes
+	}
+
+	secretPaths := map[string]bool{}
+	for _, data := range []map[string]interface{}{base, target} {
+		flat := map[string]interface{}{}
+		diff.Flatten("", secret.MaskValues(data, paths), flat)
+		for path, value := range flat {
+			if value == secret.Mask {
+				secretPaths[path] = true
+			}
+		}
+	}
+
+	for i, change := range changes {
+		if !isSecretChange(change.Path, secretPaths) {
+			continue
+		}
+		if change.Old != nil {
+			changes[i].Old = secret.Mask
+		}
+		if change.New != nil {
+			changes[i].New = secret.Mask
+		}
+	}
+
+	return changes
+}
+
+// isSecretChange reports whether the dotted path of a change is one of
+// secretPaths or below one of them.
+func isSecretChange(path string, secretPaths map[string]bool) bool {
+	for secretPath := range secretPaths {
+		if path == secretPath || strings.HasPrefix(path, secretPath+".") || strings.HasPrefix(path, secretPath+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSecretReference rejects references to values of a config of schema
+// that are secret or hold secrets, as referencing configs would serve them
+// without masking them.
+func checkSecretReference(tenantName, schema string, document map[string]interface{}, ref configRef, value interface{}) error {
+	paths := helper.SecretPaths(tenantName, schema)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	masked, _ := lookupPointer(secret.MaskValues(document, paths), ref.Pointer)
+	if !reflect.DeepEqual(masked, value) {
+		return helper.ValidationError{Msg: fmt.Sprintf("reference %s points to secret values", ref)}
+	}
+	return nil
+}
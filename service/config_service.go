@@ -3,12 +3,58 @@ package service
 import (
 	"config-service/model/web"
 	"context"
+	"time"
 )
 
 type ConfigService interface {
 	CreateConfig(ctx context.Context, schema, name string, request web.ConfigCreateRequest) web.ConfigResponse
 	UpdateConfig(ctx context.Context, schema, name string, request web.ConfigUpdateRequest) web.ConfigResponse
+	// BatchUpdateConfigs stores new versions of several configs in one
+	// transaction, storing none when any of them fails.
+	BatchUpdateConfigs(ctx context.Context, request web.ConfigBatchRequest) web.ConfigBatchResponse
 	RollbackConfig(ctx context.Context, schema, name string, request web.ConfigRollbackRequest) web.ConfigResponse
 	FetchConfig(ctx context.Context, schema, name string, request web.ConfigFetchRequest) web.ConfigResponse
+	// BulkFetchConfigs fetches many configs in one transaction, reporting
+	// the errors of single configs in their results.
+	BulkFetchConfigs(ctx context.Context, request web.ConfigBulkFetchRequest) web.ConfigBulkResponse
+	// PromoteConfig copies a version of a config from one environment to
+	// another as a new version there, or only reports the changes on dry runs.
+	PromoteConfig(ctx context.Context, schema, name string, request web.ConfigPromoteRequest) web.ConfigPromoteResponse
+	// CancelConfig cancels a version of a config scheduled to become active
+	// later.
+	CancelConfig(ctx context.Context, schema, name string, request web.ConfigCancelRequest) web.ConfigResponse
+	// PublishActivations notifies the watchers of the configs whose scheduled
+	// versions became active after after and until until, returning their
+	// number. Versions superseded by newer active ones are skipped.
+	PublishActivations(ctx context.Context, after, until time.Time) int
 	ListVersions(ctx context.Context, schema, name string) web.ConfigResponses
+	// Watch streams the versions of a config becoming active, resolved like
+	// FetchConfig, and the config again when changes of its parents or
+	// referenced configs change its data, until ctx is done. The returned
+	// function reports ErrWatchFellBehind once the channel is closed when the
+	// watch did not keep up with the changes.
+	Watch(ctx context.Context, schema, name string) (<-chan web.ConfigResponse, func() error)
+	// ListEnvironments lists the environments with their number of configs.
+	ListEnvironments(ctx context.Context) []web.EnvironmentResponse
+	// ListConfigEnvironments returns the latest version of a config in every
+	// environment having one.
+	ListConfigEnvironments(ctx context.Context, schema, name string) []web.ConfigResponse
+	// ListReferrers lists the references of the latest versions of configs
+	// to a config.
+	ListReferrers(ctx context.Context, schema, name string) []web.ConfigReferrerResponse
+	// ProposeConfig stores a draft of a new version of a config, validated
+	// like an update but not served until it is approved.
+	ProposeConfig(ctx context.Context, schema, name string, request web.DraftCreateRequest) web.DraftResponse
+	ListDrafts(ctx context.Context, request web.DraftListRequest) []web.DraftResponse
+	GetDraft(ctx context.Context, id int64) web.DraftResponse
+	ApproveDraft(ctx context.Context, id int64, request web.DraftReviewRequest) web.DraftResponse
+	RejectDraft(ctx context.Context, id int64, request web.DraftReviewRequest) web.DraftResponse
+	// ListLabels lists the labels naming versions of a config.
+	ListLabels(ctx context.Context, schema, name string) []web.ConfigLabelResponse
+	// SetLabel names a version of a config by label, moving the label when
+	// it named another version.
+	SetLabel(ctx context.Context, schema, name, label string, request web.ConfigLabelRequest) web.ConfigLabelResponse
+	// DeleteLabel removes a label of a config, override being the reason
+	// for removing it despite active freezes.
+	DeleteLabel(ctx context.Context, schema, name, label, override string)
 }
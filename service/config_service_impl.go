@@ -1,124 +1,307 @@
 package service
 
 import (
+	"config-service/auth"
+	"config-service/diff"
+	"config-service/environment"
 	"config-service/exception"
 	"config-service/helper"
 	"config-service/model/domain"
 	"config-service/model/web"
 	"config-service/repository"
+	"config-service/tenant"
+	"config-service/tracing"
 	"context"
 	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/go-playground/validator"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type ConfigServiceImpl struct {
 	ConfigRepository repository.ConfigRepository
 	DB               *sql.DB
 	Validate         *validator.Validate
+	Watcher          *ConfigWatcher
+	RoleBindings     RoleBindingService
+	Audit            AuditService
+	// Quotas limit the number of configs per tenant, see WithTenantQuotas
+	Quotas TenantQuotas
+	// Environments lists the environments besides environment.Default, nil
+	// accepts every valid name, see WithEnvironments
+	Environments []string
+	// Variables holds the variables placeholders are rendered with, nil
+	// renders none, see WithVariables
+	Variables repository.VariableRepository
+	// Drafts holds the proposed versions, Approvals the number of approvals
+	// they need per tenant and schema, see WithDrafts
+	Drafts    repository.DraftRepository
+	Approvals map[string]int
+	// Freezes lock configs against changes unless they are overridden with
+	// a reason, nil locks none, see WithFreezes
+	Freezes FreezeService
+	// Labels name versions that fetches and promotions can select them by,
+	// nil rejects selecting by label, see WithLabels
+	Labels repository.LabelRepository
 }
 
-func NewConfigService(configRepository repository.ConfigRepository, DB *sql.DB, validate *validator.Validate) ConfigService {
-	return &ConfigServiceImpl{
+// TenantQuotas limits the number of configs a tenant can create. Limits
+// override Default for single tenants, a limit of 0 means unlimited.
+type TenantQuotas struct {
+	Default int
+	Limits  map[string]int
+}
+
+// Limit returns the maximum number of configs of tenant, 0 for unlimited.
+func (quotas TenantQuotas) Limit(tenantName string) int {
+	if limit, ok := quotas.Limits[tenantName]; ok {
+		return limit
+	}
+	return quotas.Default
+}
+
+// ConfigServiceOption customizes a ConfigService created with NewConfigService.
+type ConfigServiceOption func(*ConfigServiceImpl)
+
+// WithAuditService records every change in the audit log, within the
+// transaction of the change. Without it changes are not audited.
+func WithAuditService(auditService AuditService) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Audit = auditService
+	}
+}
+
+// WithTenantQuotas rejects creating configs beyond the quota of a tenant.
+func WithTenantQuotas(quotas TenantQuotas) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Quotas = quotas
+	}
+}
+
+// WithEnvironments rejects requests for environments other than the given
+// ones and environment.Default.
+func WithEnvironments(environments []string) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Environments = environments
+	}
+}
+
+// WithVariables renders the ${name} placeholders of fetched configs with the
+// variables of their environment.
+func WithVariables(variableRepository repository.VariableRepository) ConfigServiceOption {
+	return func(service *ConfigServiceImpl) {
+		service.Variables = variableRepository
+	}
+}
+
+func NewConfigService(configRepository repository.ConfigRepository, DB *sql.DB, validate *validator.Validate, options ...ConfigServiceOption) ConfigService {
+	service := &ConfigServiceImpl{
 		ConfigRepository: configRepository,
 		DB:               DB,
 		Validate:         validate,
+		Watcher:          NewConfigWatcher(),
+		RoleBindings:     NewRoleBindingService(repository.NewRoleBindingRepository(), DB, validate, nil),
+	}
+
+	for _, option := range options {
+		option(service)
 	}
+
+	return service
 }
 
 func (service *ConfigServiceImpl) CreateConfig(ctx context.Context, schema, name string, request web.ConfigCreateRequest) web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.CreateConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkDirectWrite(ctx, schema)
+
 	// Validate incoming request payload
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	// Masks only keep the secret values of existing configs
+	if hasMaskedSecrets(tenant.FromContext(ctx), schema, request.Data) {
+		helper.PanicIfError(helper.ValidationError{Msg: "masked secret values can only be kept by updates"})
+	}
+
 	// Validate against schema
-	helper.ValidateAgainstSchema(schema, request.Data)
+	validateData(ctx, schema, request.Data)
+	effectiveAt := validateEffectiveAt(request.EffectiveAt)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
 
 	// Start transaction
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
 
 	// Create domain model
 	configRecord := domain.ConfigRecord{
-		Schema: schema,
-		Name:   name,
-		Data:   request.Data, // assuming Data is a map or json.RawMessage
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Data:        request.Data, // assuming Data is a map or json.RawMessage
+		CreatedBy:   auth.Subject(ctx),
+		EffectiveAt: effectiveAt,
 	}
 
+	defer service.afterCommit(ctx, "config created", &configRecord)
+	defer helper.CommitOrRollback(tx)
+
 	// Check whether config name exist
 	latest, err := service.ConfigRepository.GetLatest(ctx, tx, configRecord)
 	if latest.Schema == schema && latest.Name == name && latest.Version > 0 {
-		helper.PanicIfError(helper.ValidationError{Msg: "config name already exist"})
+		panic(exception.NewConflictError("config name already exist"))
 	}
 
+	// Check the quota of the tenant
+	service.checkQuota(ctx, tx, configRecord.Tenant)
+
 	newVersion := 1
 	configRecord.Version = newVersion
+	service.validateEffectiveData(ctx, tx, configRecord, false)
 
 	// Save new config version
 	configRecord = service.ConfigRepository.CreateNewVersion(ctx, tx, configRecord)
+	service.audit(ctx, tx, domain.AuditConfigCreated, nil, configRecord, scheduleDetail(configRecord))
 
 	// Map domain model to web response
 	return helper.ToConfigResponse(configRecord)
 }
 
 func (service *ConfigServiceImpl) UpdateConfig(ctx context.Context, schema, name string, request web.ConfigUpdateRequest) web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.UpdateConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkDirectWrite(ctx, schema)
+
 	// Validate incoming request payload
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
-	// Validate against schema
-	helper.ValidateAgainstSchema(schema, request.Data)
+	// Validate against schema, data keeping masked secret values once they
+	// have been restored
+	masked := hasMaskedSecrets(tenant.FromContext(ctx), schema, request.Data)
+	if masked {
+		helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+	} else {
+		validateData(ctx, schema, request.Data)
+	}
+	effectiveAt := validateEffectiveAt(request.EffectiveAt)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
 
 	// Start transaction
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
 
 	// Create domain model
 	configRecord := domain.ConfigRecord{
-		Schema: schema,
-		Name:   name,
-		Data:   request.Data, // assuming Data is a map or json.RawMessage
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Data:        request.Data, // assuming Data is a map or json.RawMessage
+		CreatedBy:   auth.Subject(ctx),
+		EffectiveAt: effectiveAt,
 	}
 
+	defer service.afterCommit(ctx, "config updated", &configRecord)
+	defer helper.CommitOrRollback(tx)
+
 	// Check whether config name exist
 	latest, err := service.ConfigRepository.GetLatest(ctx, tx, configRecord)
 	if err != nil {
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
+	if masked {
+		configRecord.Data = keepMaskedSecrets(configRecord.Tenant, schema, configRecord.Data, latest.Data)
+		validateData(ctx, schema, configRecord.Data)
+	}
+
+	// Check whether the config is frozen
+	override := service.checkFreeze(ctx, tx, configRecord, request.Override)
+	configRecord.Metadata = withFreezeOverride(configRecord.Metadata, override)
+
 	newVersion := latest.Version + 1
 	configRecord.Version = newVersion
+	service.validateEffectiveData(ctx, tx, configRecord, true)
 
 	// Save new config version
 	configRecord = service.ConfigRepository.CreateNewVersion(ctx, tx, configRecord)
+	service.audit(ctx, tx, domain.AuditConfigUpdated, latest.Data, configRecord, overrideDetail(scheduleDetail(configRecord), override))
 
 	return helper.ToConfigResponse(configRecord)
 }
 
 func (service *ConfigServiceImpl) FetchConfig(ctx context.Context, schema, name string, request web.ConfigFetchRequest) web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.FetchConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller, secret values are only revealed to approvers
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+	if request.Reveal {
+		service.RoleBindings.Authorize(ctx, auth.RoleApprover, schema, name)
+	}
+
 	// Validate incoming request payload
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
 	// Validate schema existence
-	helper.ValidateSchemaExistence(schema)
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
 
 	// Start transaction
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	return service.fetchConfig(ctx, tx, environmentName, schema, name, request)
+}
+
+// fetchConfig reads a version of a config as served to the caller, merged
+// with its ancestors, with its references and placeholders resolved and its
+// secret values masked as requested.
+func (service *ConfigServiceImpl) fetchConfig(ctx context.Context, tx *sql.Tx, environmentName, schema, name string, request web.ConfigFetchRequest) web.ConfigResponse {
+	return service.resolveConfig(ctx, tx, service.referenceResolver(ctx, tx, environmentName), schema, name, request)
+}
+
+// resolveConfig is fetchConfig resolving the config with resolver, which
+// records the configs it was resolved with.
+func (service *ConfigServiceImpl) resolveConfig(ctx context.Context, tx *sql.Tx, resolver *referenceResolver, schema, name string, request web.ConfigFetchRequest) web.ConfigResponse {
 	// Create domain model
 	configRecord := domain.ConfigRecord{
-		Schema: schema,
-		Name:   name,
+		Tenant:      tenant.FromContext(ctx),
+		Environment: resolver.environment,
+		Schema:      schema,
+		Name:        name,
 	}
 
+	// The latest version is the newest one active, scheduled versions are
+	// only fetched by their number
 	var fetchData domain.ConfigRecord
+	var err error
 	if *request.Version == 0 {
-		fetchData, err = service.ConfigRepository.GetLatest(ctx, tx, configRecord)
+		fetchData, err = service.ConfigRepository.GetActive(ctx, tx, configRecord, time.Now())
 	} else {
 		configRecord.Version = *request.Version
 		fetchData, err = service.ConfigRepository.GetByVersion(ctx, tx, configRecord)
@@ -132,29 +315,111 @@ func (service *ConfigServiceImpl) FetchConfig(ctx context.Context, schema, name
 		helper.PanicIfError(helper.ValidationError{Msg: "config name or its requested version doesn't exist"})
 	}
 
-	return helper.ToConfigResponse(fetchData)
+	// Inheriting configs are served with the data of their ancestors
+	layers, err := resolver.layers(fetchData)
+	if err != nil {
+		panic(exception.NewConflictError(err.Error()))
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("config.version", fetchData.Version), attribute.Int("config.layers", len(layers)))
+	slog.DebugContext(ctx, "config fetched", "schema", fetchData.Schema, "name", fetchData.Name, "version", fetchData.Version)
+
+	toResponse := helper.ToConfigResponse
+	if request.Reveal {
+		toResponse = helper.ToRevealedConfigResponse
+	}
+
+	response := helper.ToRevealedConfigResponse(fetchData)
+	if len(layers) > 1 {
+		response.Data = mergeLayers(layers)
+	}
+	if request.Layers {
+		for _, layer := range layers {
+			response.Layers = append(response.Layers, toResponse(layer))
+		}
+	}
+
+	// References are resolved unless requested otherwise, the referenced
+	// configs may have changed since the version was validated
+	refs, err := hasConfigRefs(response.Data)
+	if err != nil {
+		panic(exception.NewConflictError(err.Error()))
+	}
+	resolve := request.Resolve == nil || *request.Resolve
+	if refs && resolve {
+		key := schema + "/" + name
+		if *request.Version > 0 {
+			key = fmt.Sprintf("%s@%d", key, fetchData.Version)
+		}
+		response.Data, err = resolver.resolveDocument(key, response.Data)
+		if err != nil {
+			panic(exception.NewConflictError(err.Error()))
+		}
+	}
+
+	// Placeholders are rendered last, with the variables of the environment
+	// as they are now
+	placeholders := resolve && hasPlaceholders(response.Data)
+	if placeholders {
+		var missing []string
+		response.Data, missing = resolver.render(response.Data)
+		if len(missing) > 0 && request.Strict {
+			panic(exception.NewConflictError(undefinedVariablesError(missing).Error()))
+		}
+	}
+
+	if (refs && resolve) || placeholders {
+		if err := helper.CheckAgainstSchema(helper.ValidateSchemaExistence(fetchData.Tenant, schema), response.Data); err != nil {
+			panic(exception.NewConflictError(err.Error()))
+		}
+	}
+
+	// Secret values are masked last, the effective data may hold secrets of
+	// the ancestors
+	if !request.Reveal {
+		response.Data = helper.MaskSecrets(fetchData.Tenant, schema, response.Data)
+	} else if len(helper.SecretPaths(fetchData.Tenant, schema)) > 0 {
+		service.audit(ctx, tx, domain.AuditSecretsRevealed, nil, fetchData, "")
+	}
+
+	return response
 }
 
 func (service *ConfigServiceImpl) RollbackConfig(ctx context.Context, schema, name string, request web.ConfigRollbackRequest) web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.RollbackConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkDirectWrite(ctx, schema)
+
 	// Validate incoming request payload
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
 	// Validate schema existence
-	helper.ValidateSchemaExistence(schema)
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
 
 	// Start transaction
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
 
 	// Create domain model
 	configRecord := domain.ConfigRecord{
-		Schema:  schema,
-		Name:    name,
-		Version: request.Version, // assuming Data is a map or json.RawMessage
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+		Version:     request.Version, // assuming Data is a map or json.RawMessage
 	}
 
+	var rollbackData domain.ConfigRecord
+	defer service.afterCommit(ctx, "config rolled back", &rollbackData)
+	defer helper.CommitOrRollback(tx)
+
 	// Check whether fetched version exist
 	fetchData, err := service.ConfigRepository.GetByVersion(ctx, tx, configRecord)
 	if err != nil {
@@ -167,17 +432,140 @@ func (service *ConfigServiceImpl) RollbackConfig(ctx context.Context, schema, na
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
+	// Check whether the config is frozen
+	override := service.checkFreeze(ctx, tx, configRecord, request.Override)
+
 	// Rollback to specified version
+	slog.DebugContext(ctx, "rolling back config", "schema", schema, "name", name, "from_version", latest.Version, "to_version", request.Version)
 	fetchData.Version = latest.Version + 1
-	rollbackData := service.ConfigRepository.CreateNewVersion(ctx, tx, fetchData)
+	fetchData.CreatedAt = time.Now().UTC()
+	fetchData.CreatedBy = auth.Subject(ctx)
+	// The new version is active right away, whatever the schedule of the
+	// version rolled back to
+	fetchData.EffectiveAt = nil
+	fetchData.CancelledAt = nil
+	fetchData.Metadata = withFreezeOverride(nil, override)
+	service.validateEffectiveData(ctx, tx, fetchData, true)
+	rollbackData = service.ConfigRepository.CreateNewVersion(ctx, tx, fetchData)
+	service.audit(ctx, tx, domain.AuditConfigRolledBack, latest.Data, rollbackData, overrideDetail(fmt.Sprintf("rolled back to version %d", request.Version), override))
 
 	return helper.ToConfigResponse(rollbackData)
 }
 
+func (service *ConfigServiceImpl) PromoteConfig(ctx context.Context, schema, name string, request web.ConfigPromoteRequest) web.ConfigPromoteResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.PromoteConfig", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, schema, name)
+	service.checkDirectWrite(ctx, schema)
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Validate environments
+	service.validateEnvironment(request.From)
+	service.validateEnvironment(request.To)
+	if request.From == request.To {
+		helper.PanicIfError(helper.ValidationError{Msg: "source and target environment must differ"})
+	}
+	if request.Label != "" && request.Version != 0 {
+		helper.PanicIfError(helper.ValidationError{Msg: "select the source version either by number or by label"})
+	}
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+
+	// Create domain model
+	source := domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: request.From,
+		Schema:      schema,
+		Name:        name,
+		Version:     request.Version,
+	}
+	target := domain.ConfigRecord{
+		Tenant:      source.Tenant,
+		Environment: request.To,
+		Schema:      schema,
+		Name:        name,
+		CreatedBy:   auth.Subject(ctx),
+	}
+
+	var promoted domain.ConfigRecord
+	defer service.afterCommit(ctx, "config promoted", &promoted)
+	defer helper.CommitOrRollback(tx)
+
+	// Get the source version, the one named by the label or the latest one
+	// for version 0
+	if request.Label != "" {
+		source.Version = service.resolveLabel(ctx, tx, source, request.Label)
+	}
+	sourceData, err := service.ConfigRepository.GetByVersion(ctx, tx, source)
+	if err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	// The source may have been written before the latest schema change
+	validateData(ctx, schema, sourceData.Data)
+
+	response := web.ConfigPromoteResponse{DryRun: request.DryRun}
+	latest, err := service.ConfigRepository.GetLatest(ctx, tx, target)
+	if err == nil {
+		previous := helper.ToConfigResponse(latest)
+		response.Previous = &previous
+		target.Version = latest.Version + 1
+	} else {
+		service.checkQuota(ctx, tx, target.Tenant)
+		target.Version = 1
+	}
+
+	// Record where the version comes from
+	target.Data = sourceData.Data
+	promotedFrom := map[string]interface{}{
+		"environment": sourceData.Environment,
+		"version":     sourceData.Version,
+	}
+	if request.Label != "" {
+		promotedFrom["label"] = request.Label
+	}
+	target.Metadata = map[string]interface{}{"promoted_from": promotedFrom}
+	service.validateEffectiveData(ctx, tx, target, true)
+	response.Changes = maskChanges(target.Tenant, schema, latest.Data, target.Data, diff.Compare(latest.Data, target.Data))
+
+	if request.DryRun {
+		response.Config = helper.ToConfigResponse(target)
+		return response
+	}
+
+	// Check whether the target is frozen, dry runs only preview the changes
+	override := service.checkFreeze(ctx, tx, target, request.Override)
+	target.Metadata = withFreezeOverride(target.Metadata, override)
+
+	promoted = service.ConfigRepository.CreateNewVersion(ctx, tx, target)
+	service.audit(ctx, tx, domain.AuditConfigPromoted, latest.Data, promoted, overrideDetail(promotionDetail(sourceData, request.Label), override))
+
+	response.Config = helper.ToConfigResponse(promoted)
+	return response
+}
+
 func (service *ConfigServiceImpl) ListVersions(ctx context.Context, schema, name string) web.ConfigResponses {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListVersions", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
 
 	// Validate schema existence
-	helper.ValidateSchemaExistence(schema)
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
 
 	// Start transaction
 	tx, err := service.DB.Begin()
@@ -186,11 +574,303 @@ func (service *ConfigServiceImpl) ListVersions(ctx context.Context, schema, name
 
 	// Create domain model
 	configRecord := domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Schema:      schema,
+		Name:        name,
+	}
+
+	configRecords := service.ConfigRepository.ListVersions(ctx, tx, configRecord)
+
+	return helper.ToConfigResponses(configRecord.Environment, schema, name, configRecords)
+}
+
+func (service *ConfigServiceImpl) ListEnvironments(ctx context.Context) []web.EnvironmentResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListEnvironments")
+	defer tracing.End(span)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	counts := service.ConfigRepository.CountPerEnvironment(ctx, tx, tenant.FromContext(ctx))
+
+	// Configured environments come first, followed by the ones that are no
+	// longer configured but still hold configs
+	names := append([]string{environment.Default}, service.Environments...)
+	var unconfigured []string
+	for name := range counts {
+		if !slices.Contains(names, name) {
+			unconfigured = append(unconfigured, name)
+		}
+	}
+	sort.Strings(unconfigured)
+
+	environments := []web.EnvironmentResponse{}
+	for _, name := range append(names, unconfigured...) {
+		environments = append(environments, web.EnvironmentResponse{Name: name, Configs: counts[name]})
+	}
+
+	return environments
+}
+
+func (service *ConfigServiceImpl) ListConfigEnvironments(ctx context.Context, schema, name string) []web.ConfigResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListConfigEnvironments", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecords := service.ConfigRepository.LatestPerEnvironment(ctx, tx, domain.ConfigRecord{
+		Tenant: tenant.FromContext(ctx),
 		Schema: schema,
 		Name:   name,
+	})
+
+	configResponses := []web.ConfigResponse{}
+	for _, configRecord := range configRecords {
+		configResponses = append(configResponses, helper.ToConfigResponse(configRecord))
 	}
 
-	configRecords := service.ConfigRepository.ListVersions(ctx, tx, configRecord)
+	return configResponses
+}
+
+func (service *ConfigServiceImpl) ListReferrers(ctx context.Context, schema, name string) []web.ConfigReferrerResponse {
+	ctx, span := tracing.Start(ctx, "ConfigService.ListReferrers", tracing.ConfigAttributes(schema, name)...)
+	defer tracing.End(span)
+
+	// Authorize caller
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// Start transaction
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	configRecords := service.ConfigRepository.ListLatest(ctx, tx, domain.ConfigRecord{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+	})
+
+	referrers := []web.ConfigReferrerResponse{}
+	for _, configRecord := range configRecords {
+		var found []web.ConfigReferrerResponse
+		err := findConfigRefs("", configRecord.Data, func(pointer string, ref configRef) {
+			if ref.Schema == schema && ref.Name == name {
+				found = append(found, web.ConfigReferrerResponse{
+					Schema:  configRecord.Schema,
+					Name:    configRecord.Name,
+					Version: configRecord.Version,
+					Path:    pointer,
+					Ref:     ref.String(),
+				})
+			}
+		})
+		// Invalid references only exist in data written around the service
+		if err != nil {
+			slog.WarnContext(ctx, "skipping config with invalid references", "schema", configRecord.Schema, "name", configRecord.Name, "error", err)
+			continue
+		}
+
+		sort.Slice(found, func(i, j int) bool { return found[i].Path < found[j].Path })
+		referrers = append(referrers, found...)
+	}
+
+	return referrers
+}
+
+func (service *ConfigServiceImpl) Watch(ctx context.Context, schema, name string) (<-chan web.ConfigResponse, func() error) {
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, schema, name)
+
+	// Validate schema existence
+	helper.ValidateSchemaExistence(tenant.FromContext(ctx), schema)
+
+	// Validate environment
+	environmentName := service.environment(ctx)
+
+	// The changes of every config of the environment are received, the
+	// watched config is fetched again when it or one of the configs it is
+	// resolved with changed
+	subscription := service.Watcher.Subscribe(tenant.FromContext(ctx), environmentName)
+
+	responses := make(chan web.ConfigResponse)
+	var watchErr error
+	go func() {
+		defer close(responses)
+		defer subscription.Close()
+
+		watched := schema + "/" + name
+		last, dependencies, _ := service.watchedConfig(ctx, environmentName, schema, name)
+
+		for {
+			var configRecord domain.ConfigRecord
+			var open bool
+			select {
+			case <-ctx.Done():
+				return
+			case configRecord, open = <-subscription.Events():
+			}
+			if !open {
+				watchErr = subscription.Err()
+				return
+			}
+
+			changed := configRecord.Schema + "/" + configRecord.Name
+			if changed != watched && !dependencies[changed] {
+				continue
+			}
+
+			response, read, ok := service.watchedConfig(ctx, environmentName, schema, name)
+			if !ok {
+				continue
+			}
+			dependencies = read
+
+			// New versions of the watched config are sent once, changes of
+			// its parents and referenced configs when they change its data
+			if changed == watched && response.Version != configRecord.Version {
+				continue
+			}
+			if changed != watched && response.Version == last.Version && reflect.DeepEqual(response.Data, last.Data) {
+				continue
+			}
+			last = response
+
+			select {
+			case responses <- response:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return responses, func() error { return watchErr }
+}
+
+// watchedConfig fetches the active version of a watched config like
+// FetchConfig, returning the schema/name of the configs it was resolved
+// with. Failed fetches are logged and reported by ok.
+func (service *ConfigServiceImpl) watchedConfig(ctx context.Context, environmentName, schema, name string) (response web.ConfigResponse, dependencies map[string]bool, ok bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			switch exception.StatusCode(err) {
+			case http.StatusNotFound:
+				// The watched config doesn't exist yet
+			case 0:
+				slog.ErrorContext(ctx, "watched config not fetched", "schema", schema, "name", name, "error", exception.ErrorMessage(err))
+			default:
+				slog.WarnContext(ctx, "watched config not fetched", "schema", schema, "name", name, "error", exception.ErrorMessage(err))
+			}
+		}
+	}()
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	latest := 0
+	resolver := service.referenceResolver(ctx, tx, environmentName)
+	response = service.resolveConfig(ctx, tx, resolver, schema, name, web.ConfigFetchRequest{Version: &latest})
+	return response, resolver.read, true
+}
+
+// afterCommit logs and notifies watchers about a new version once the
+// surrounding transaction has been committed. It must be deferred before
+// helper.CommitOrRollback so that it runs after it.
+func (service *ConfigServiceImpl) afterCommit(ctx context.Context, message string, configRecord *domain.ConfigRecord) {
+	if err := recover(); err != nil {
+		panic(err)
+	}
+
+	if configRecord.Version > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int("config.version", configRecord.Version))
+		slog.InfoContext(ctx, message, "tenant", configRecord.Tenant, "environment", configRecord.Environment, "schema", configRecord.Schema, "name", configRecord.Name, "version", configRecord.Version)
+
+		// Scheduled versions are published once they become active, see
+		// PublishActivations
+		if !configRecord.Scheduled(time.Now()) {
+			service.Watcher.Publish(*configRecord)
+		}
+	}
+}
+
+// audit records a new version in the audit log, if auditing is enabled.
+func (service *ConfigServiceImpl) audit(ctx context.Context, tx *sql.Tx, action string, before map[string]interface{}, configRecord domain.ConfigRecord, detail string) {
+	if service.Audit == nil {
+		return
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action:      action,
+		Tenant:      configRecord.Tenant,
+		Environment: configRecord.Environment,
+		Schema:      configRecord.Schema,
+		Name:        configRecord.Name,
+		Version:     configRecord.Version,
+		BeforeHash:  helper.DataHash(before),
+		AfterHash:   helper.DataHash(configRecord.Data),
+		Detail:      detail,
+	})
+}
+
+// promotionDetail describes the source of a promotion in its audit event.
+func promotionDetail(source domain.ConfigRecord, label string) string {
+	detail := fmt.Sprintf("promoted from %s version %d", source.Environment, source.Version)
+	if label != "" {
+		detail += " labeled " + label
+	}
+	return detail
+}
+
+// checkQuota rejects creating another config once tenant reached its quota.
+func (service *ConfigServiceImpl) checkQuota(ctx context.Context, tx *sql.Tx, tenantName string) {
+	if limit := service.Quotas.Limit(tenantName); limit > 0 && service.ConfigRepository.CountConfigs(ctx, tx, tenantName) >= limit {
+		panic(exception.NewForbiddenError(fmt.Sprintf("tenant %s reached its quota of %d configs", tenantName, limit)))
+	}
+}
+
+// environment returns the environment requested by ctx, rejecting unknown
+// ones.
+func (service *ConfigServiceImpl) environment(ctx context.Context) string {
+	return service.validateEnvironment(environment.FromContext(ctx))
+}
+
+// validateEnvironment rejects invalid and unknown environment names.
+func (service *ConfigServiceImpl) validateEnvironment(name string) string {
+	return validateEnvironment(service.Environments, name)
+}
+
+// validateEnvironment rejects invalid names and, unless environments is nil,
+// names other than environments and environment.Default.
+func validateEnvironment(environments []string, name string) string {
+	if !environment.Valid(name) {
+		helper.PanicIfError(helper.ValidationError{Msg: "invalid environment name"})
+	}
+	if name != environment.Default && environments != nil && !slices.Contains(environments, name) {
+		helper.PanicIfError(helper.ValidationError{Msg: "unknown environment " + name})
+	}
+	return name
+}
+
+// validateAgainstSchema wraps helper.ValidateAgainstSchema in its own span.
+func validateAgainstSchema(ctx context.Context, schema string, data map[string]interface{}) {
+	_, span := tracing.Start(ctx, "schema.validate", attribute.String("config.schema", schema))
+	defer tracing.End(span)
 
-	return helper.ToConfigResponses(schema, name, configRecords)
+	helper.ValidateAgainstSchema(tenant.FromContext(ctx), schema, data)
 }
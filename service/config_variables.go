@@ -0,0 +1,123 @@
+package service
+
+import (
+	"config-service/helper"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// validVariableName reports whether name can be used in ${name} placeholders.
+func validVariableName(name string) bool {
+	if name == "" || len(name) > 128 {
+		return false
+	}
+	for i, c := range name {
+		letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !letter && (i == 0 || (c != '.' && (c < '0' || c > '9'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// renderValue replaces the ${name} placeholders in the strings of value with
+// the variables. A string only holding a placeholder takes the value of the
+// variable, whatever its type, other placeholders are replaced by the value
+// formatted as text. $${ is rendered as a literal ${. Placeholders of
+// undefined variables are left as they are and their names passed to missing.
+func renderValue(value interface{}, variables map[string]interface{}, missing func(name string)) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		rendered := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			rendered[key] = renderValue(item, variables, missing)
+		}
+		return rendered
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered[i] = renderValue(item, variables, missing)
+		}
+		return rendered
+	case string:
+		return renderString(v, variables, missing)
+	default:
+		return value
+	}
+}
+
+func renderString(text string, variables map[string]interface{}, missing func(name string)) interface{} {
+	if name, ok := strings.CutPrefix(text, "${"); ok && strings.HasSuffix(name, "}") && validVariableName(name[:len(name)-1]) {
+		if variable, ok := variables[name[:len(name)-1]]; ok {
+			return variable
+		}
+	}
+
+	var rendered strings.Builder
+	for i := 0; i < len(text); {
+		if strings.HasPrefix(text[i:], "$${") {
+			rendered.WriteString("${")
+			i += 3
+			continue
+		}
+
+		if strings.HasPrefix(text[i:], "${") {
+			if end := strings.IndexByte(text[i+2:], '}'); end >= 0 && validVariableName(text[i+2:i+2+end]) {
+				name := text[i+2 : i+2+end]
+				if variable, ok := variables[name]; ok {
+					rendered.WriteString(formatVariable(variable))
+				} else {
+					missing(name)
+					rendered.WriteString(text[i : i+3+end])
+				}
+				i += 3 + end
+				continue
+			}
+		}
+
+		rendered.WriteByte(text[i])
+		i++
+	}
+
+	return rendered.String()
+}
+
+// formatVariable returns the text of a variable within a string, strings as
+// they are and other values as JSON.
+func formatVariable(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	content, err := json.Marshal(value)
+	helper.PanicIfError(err)
+	return string(content)
+}
+
+// renderDocument renders data with the variables, returning the names of the
+// undefined variables it uses, sorted.
+func renderDocument(data map[string]interface{}, variables map[string]interface{}) (map[string]interface{}, []string) {
+	var missing []string
+	rendered, _ := renderValue(data, variables, func(name string) {
+		if !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}).(map[string]interface{})
+	slices.Sort(missing)
+
+	return rendered, missing
+}
+
+// hasPlaceholders reports whether data holds ${name} placeholders.
+func hasPlaceholders(data map[string]interface{}) bool {
+	found := false
+	renderValue(data, nil, func(string) { found = true })
+	return found
+}
+
+// undefinedVariablesError is returned by strict renders using undefined
+// variables.
+func undefinedVariablesError(names []string) error {
+	return helper.ValidationError{Msg: fmt.Sprintf("undefined variables: %s", strings.Join(names, ", "))}
+}
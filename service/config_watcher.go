@@ -0,0 +1,120 @@
+package service
+
+import (
+	"config-service/model/domain"
+	"errors"
+	"sync"
+)
+
+// ErrWatchFellBehind closes the subscriptions that did not keep up with the
+// published config versions. Their watchers have to subscribe again to
+// resync.
+var ErrWatchFellBehind = errors.New("watch fell behind the config changes, watch again to resync")
+
+// ConfigWatcher fans out the config versions becoming active to the
+// subscribers watching the configs of their tenant and environment.
+type ConfigWatcher struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[string]map[int]*Subscription
+}
+
+// Subscription receives the config versions published to the configs of an
+// environment until it is closed.
+type Subscription struct {
+	watcher *ConfigWatcher
+	key     string
+	id      int
+	events  chan domain.ConfigRecord
+	closed  bool
+	err     error
+}
+
+func NewConfigWatcher() *ConfigWatcher {
+	return &ConfigWatcher{
+		subscribers: map[string]map[int]*Subscription{},
+	}
+}
+
+func watchKey(tenant, environment string) string {
+	return tenant + "/" + environment
+}
+
+// Subscribe registers a subscriber to the configs of an environment.
+func (watcher *ConfigWatcher) Subscribe(tenant, environment string) *Subscription {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	key := watchKey(tenant, environment)
+	if watcher.subscribers[key] == nil {
+		watcher.subscribers[key] = map[int]*Subscription{}
+	}
+
+	id := watcher.nextID
+	watcher.nextID++
+
+	subscription := &Subscription{
+		watcher: watcher,
+		key:     key,
+		id:      id,
+		events:  make(chan domain.ConfigRecord, 64),
+	}
+	watcher.subscribers[key][id] = subscription
+
+	return subscription
+}
+
+// Publish delivers a config version to its subscribers. Slow subscribers
+// whose buffer is full are closed with ErrWatchFellBehind instead of
+// blocking the writer or missing the version.
+func (watcher *ConfigWatcher) Publish(config domain.ConfigRecord) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	for _, subscription := range watcher.subscribers[watchKey(config.Tenant, config.Environment)] {
+		select {
+		case subscription.events <- config:
+		default:
+			subscription.close(ErrWatchFellBehind)
+		}
+	}
+}
+
+// Events returns the channel receiving the published config versions, closed
+// with the subscription.
+func (subscription *Subscription) Events() <-chan domain.ConfigRecord {
+	return subscription.events
+}
+
+// Err returns ErrWatchFellBehind when the watcher closed the subscription,
+// nil otherwise.
+func (subscription *Subscription) Err() error {
+	subscription.watcher.mu.Lock()
+	defer subscription.watcher.mu.Unlock()
+
+	return subscription.err
+}
+
+// Close unregisters the subscription and closes its channel.
+func (subscription *Subscription) Close() {
+	subscription.watcher.mu.Lock()
+	defer subscription.watcher.mu.Unlock()
+
+	subscription.close(nil)
+}
+
+// close must be called with the watcher locked.
+func (subscription *Subscription) close(err error) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	subscription.err = err
+
+	subscribers := subscription.watcher.subscribers
+	delete(subscribers[subscription.key], subscription.id)
+	if len(subscribers[subscription.key]) == 0 {
+		delete(subscribers, subscription.key)
+	}
+	close(subscription.events)
+}
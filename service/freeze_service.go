@@ -0,0 +1,21 @@
+package service
+
+import (
+	"config-service/model/domain"
+	"config-service/model/web"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// FreezeService manages freezes and finds the ones locking configs when
+// they are changed.
+type FreezeService interface {
+	CreateFreeze(ctx context.Context, request web.FreezeCreateRequest) web.FreezeResponse
+	ListFreezes(ctx context.Context) []web.FreezeResponse
+	DeleteFreeze(ctx context.Context, id int64)
+
+	// Active returns the freezes locking config at the given time, read
+	// within the transaction changing it.
+	Active(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord, at time.Time) []domain.Freeze
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+type FreezeServiceImpl struct {
+	FreezeRepository repository.FreezeRepository
+	DB               *sql.DB
+	Validate         *validator.Validate
+	Audit            AuditService
+}
+
+// NewFreezeService creates the service. auditService may be nil when the
+// service is only used to check freezes.
+func NewFreezeService(freezeRepository repository.FreezeRepository, DB *sql.DB, validate *validator.Validate, auditService AuditService) FreezeService {
+	return &FreezeServiceImpl{
+		FreezeRepository: freezeRepository,
+		DB:               DB,
+		Validate:         validate,
+		Audit:            auditService,
+	}
+}
+
+func (service *FreezeServiceImpl) CreateFreeze(ctx context.Context, request web.FreezeCreateRequest) web.FreezeResponse {
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	for _, pattern := range []*string{&request.Tenant, &request.Environment, &request.NamePattern} {
+		if *pattern == "" {
+			*pattern = "*"
+		}
+	}
+	for _, pattern := range []string{request.Tenant, request.Environment, request.Schema, request.NamePattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("invalid pattern %q", pattern)})
+		}
+	}
+
+	freeze := domain.Freeze{
+		Tenant:      request.Tenant,
+		Environment: request.Environment,
+		Schema:      request.Schema,
+		NamePattern: request.NamePattern,
+		Reason:      request.Reason,
+		CreatedAt:   time.Now().UTC(),
+		CreatedBy:   auth.Subject(ctx),
+	}
+	if request.Window != nil {
+		freeze.Window = validateFreezeWindow(*request.Window)
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	freeze = service.FreezeRepository.Create(ctx, tx, freeze)
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditFreezeCreated,
+		Schema: freeze.Schema,
+		Detail: fmt.Sprintf("freeze %d locks %s/%s in tenant %s, environment %s: %s", freeze.ID, freeze.Schema, freeze.NamePattern,
+			freeze.Tenant, freeze.Environment, freeze.Reason),
+	})
+
+	slog.InfoContext(ctx, "freeze created", "freeze_id", freeze.ID, "tenant", freeze.Tenant, "environment", freeze.Environment,
+		"schema", freeze.Schema, "name_pattern", freeze.NamePattern, "recurring", freeze.Window != nil, "by", freeze.CreatedBy)
+
+	return helper.ToFreezeResponse(freeze, freeze.ActiveAt(time.Now()))
+}
+
+func (service *FreezeServiceImpl) ListFreezes(ctx context.Context) []web.FreezeResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	now := time.Now()
+	responses := []web.FreezeResponse{}
+	for _, freeze := range service.FreezeRepository.FindAll(ctx, tx) {
+		responses = append(responses, helper.ToFreezeResponse(freeze, freeze.ActiveAt(now)))
+	}
+
+	return responses
+}
+
+func (service *FreezeServiceImpl) DeleteFreeze(ctx context.Context, id int64) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if err := service.FreezeRepository.Delete(ctx, tx, id); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditFreezeDeleted,
+		Detail: fmt.Sprintf("freeze %d deleted", id),
+	})
+
+	slog.InfoContext(ctx, "freeze deleted", "freeze_id", id, "by", auth.Subject(ctx))
+}
+
+func (service *FreezeServiceImpl) Active(ctx context.Context, tx *sql.Tx, config domain.ConfigRecord, at time.Time) []domain.Freeze {
+	var active []domain.Freeze
+	for _, freeze := range service.FreezeRepository.FindAll(ctx, tx) {
+		if freeze.Matches(config.Tenant, config.Environment, config.Schema, config.Name) && freeze.ActiveAt(at) {
+			active = append(active, freeze)
+		}
+	}
+
+	return active
+}
+
+// validateFreezeWindow checks the times of day and the time zone of a
+// window, which the validator cannot.
+func validateFreezeWindow(window web.FreezeWindow) *domain.FreezeWindow {
+	for _, value := range []string{window.Start, window.End} {
+		if _, err := time.Parse("15:04", value); err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("window time %q must be a time of day such as 18:00", value)})
+		}
+	}
+	if _, err := time.LoadLocation(window.Timezone); err != nil {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("unknown time zone %q", window.Timezone)})
+	}
+
+	return &domain.FreezeWindow{
+		Days:     window.Days,
+		Start:    window.Start,
+		End:      window.End,
+		Timezone: window.Timezone,
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"config-service/model/web"
+	"context"
+)
+
+type HealthService interface {
+	Readiness(ctx context.Context) web.ReadinessResponse
+	Status(ctx context.Context) web.StatusResponse
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	statusUp   = "UP"
+	statusDown = "DOWN"
+)
+
+type HealthServiceImpl struct {
+	ConfigRepository  repository.ConfigRepository
+	DB                *sql.DB
+	PendingMigrations func(ctx context.Context) (int, error)
+	StartedAt         time.Time
+}
+
+func NewHealthService(configRepository repository.ConfigRepository, DB *sql.DB, pendingMigrations func(ctx context.Context) (int, error)) HealthService {
+	return &HealthServiceImpl{
+		ConfigRepository:  configRepository,
+		DB:                DB,
+		PendingMigrations: pendingMigrations,
+		StartedAt:         time.Now(),
+	}
+}
+
+func (service *HealthServiceImpl) Readiness(ctx context.Context) web.ReadinessResponse {
+	checks, _ := service.runChecks(ctx)
+
+	return web.ReadinessResponse{
+		Status: overallStatus(checks),
+		Checks: checks,
+	}
+}
+
+func (service *HealthServiceImpl) Status(ctx context.Context) web.StatusResponse {
+	checks, pendingMigrations := service.runChecks(ctx)
+
+	stats := service.DB.Stats()
+	database := web.DatabaseStatus{
+		OpenConnections:   stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDuration:      stats.WaitDuration.String(),
+		PendingMigrations: pendingMigrations,
+	}
+
+	if overallStatus(checks) == statusUp {
+		configStats := service.configStats(ctx)
+		database.Configs = configStats.Configs
+		database.Versions = configStats.Versions
+	}
+
+	schemas := []string{}
+	for _, tenantName := range domain.Tenants() {
+		for _, name := range domain.SchemaNames(tenantName) {
+			schemas = append(schemas, domain.QualifiedSchemaName(tenantName, name))
+		}
+	}
+	sort.Strings(schemas)
+
+	return web.StatusResponse{
+		Status:      overallStatus(checks),
+		Uptime:      time.Since(service.StartedAt).Round(time.Second).String(),
+		Build:       helper.BuildInfo(),
+		Database:    database,
+		SchemaCount: len(schemas),
+		Schemas:     schemas,
+		Checks:      checks,
+	}
+}
+
+func (service *HealthServiceImpl) configStats(ctx context.Context) domain.ConfigStats {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	return service.ConfigRepository.Stats(ctx, tx)
+}
+
+// runChecks verifies the database answers, schemas are loaded and no
+// migration is pending. It also returns the number of pending migrations.
+func (service *HealthServiceImpl) runChecks(ctx context.Context) ([]web.HealthCheck, int) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	database := web.HealthCheck{Name: "database", Healthy: true}
+	if err := service.DB.PingContext(ctx); err != nil {
+		database.Healthy = false
+		database.Error = err.Error()
+	}
+
+	schemas := web.HealthCheck{Name: "schemas", Healthy: len(domain.Schemas) > 0}
+	if !schemas.Healthy {
+		schemas.Error = "no schema loaded"
+	}
+
+	migrations := web.HealthCheck{Name: "migrations", Healthy: true}
+	pending := 0
+	if database.Healthy {
+		var err error
+		pending, err = service.PendingMigrations(ctx)
+		if err != nil {
+			migrations.Healthy = false
+			migrations.Error = err.Error()
+		} else if pending > 0 {
+			migrations.Healthy = false
+			migrations.Error = fmt.Sprintf("%d pending migration(s)", pending)
+		}
+	} else {
+		migrations.Healthy = false
+		migrations.Error = "database unavailable"
+	}
+
+	return []web.HealthCheck{database, schemas, migrations}, pending
+}
+
+func overallStatus(checks []web.HealthCheck) string {
+	for _, check := range checks {
+		if !check.Healthy {
+			return statusDown
+		}
+	}
+	return statusUp
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"config-service/model/web"
+	"context"
+)
+
+// RoleBindingService manages role bindings and checks them when configs are
+// accessed.
+type RoleBindingService interface {
+	CreateBinding(ctx context.Context, request web.RoleBindingCreateRequest) web.RoleBindingResponse
+	ListBindings(ctx context.Context, principal string) []web.RoleBindingResponse
+	DeleteBinding(ctx context.Context, id int64)
+
+	// Authorize panics with a ForbiddenError unless the caller holds role,
+	// globally or through a binding matching schema and name. Anonymous
+	// requests, only possible when authentication is optional, are allowed.
+	Authorize(ctx context.Context, role, schema, name string)
+	// Allowed reports whether Authorize lets the caller through.
+	Allowed(ctx context.Context, role, schema, name string) bool
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+type RoleBindingServiceImpl struct {
+	RoleBindingRepository repository.RoleBindingRepository
+	DB                    *sql.DB
+	Validate              *validator.Validate
+	Audit                 AuditService
+}
+
+// NewRoleBindingService creates the service. auditService may be nil when
+// the service is only used to authorize requests.
+func NewRoleBindingService(roleBindingRepository repository.RoleBindingRepository, DB *sql.DB, validate *validator.Validate, auditService AuditService) RoleBindingService {
+	return &RoleBindingServiceImpl{
+		RoleBindingRepository: roleBindingRepository,
+		DB:                    DB,
+		Validate:              validate,
+		Audit:                 auditService,
+	}
+}
+
+func (service *RoleBindingServiceImpl) CreateBinding(ctx context.Context, request web.RoleBindingCreateRequest) web.RoleBindingResponse {
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if request.NamePattern == "" {
+		request.NamePattern = "*"
+	}
+	for _, pattern := range []string{request.Schema, request.NamePattern} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("invalid pattern %q", pattern)})
+		}
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	binding, err := service.RoleBindingRepository.Create(ctx, tx, domain.RoleBinding{
+		Tenant:      tenant.FromContext(ctx),
+		Principal:   request.Principal,
+		Role:        request.Role,
+		Schema:      request.Schema,
+		NamePattern: request.NamePattern,
+		CreatedAt:   time.Now().UTC(),
+		CreatedBy:   auth.Subject(ctx),
+	})
+	if err != nil {
+		panic(exception.NewConflictError(err.Error()))
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditRoleBindingCreated,
+		Tenant: binding.Tenant,
+		Schema: binding.Schema,
+		Detail: fmt.Sprintf("binding %d grants %s to %s on %s/%s", binding.ID, binding.Role, binding.Principal, binding.Schema, binding.NamePattern),
+	})
+
+	slog.InfoContext(ctx, "role binding created", "binding_id", binding.ID, "tenant", binding.Tenant, "principal", binding.Principal, "role", binding.Role,
+		"schema", binding.Schema, "name_pattern", binding.NamePattern, "by", binding.CreatedBy)
+
+	return helper.ToRoleBindingResponse(binding)
+}
+
+func (service *RoleBindingServiceImpl) ListBindings(ctx context.Context, principal string) []web.RoleBindingResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	var bindings []domain.RoleBinding
+	if principal == "" {
+		bindings = service.RoleBindingRepository.FindAll(ctx, tx, tenant.FromContext(ctx))
+	} else {
+		bindings = service.RoleBindingRepository.FindByPrincipal(ctx, tx, tenant.FromContext(ctx), principal)
+	}
+
+	responses := []web.RoleBindingResponse{}
+	for _, binding := range bindings {
+		responses = append(responses, helper.ToRoleBindingResponse(binding))
+	}
+
+	return responses
+}
+
+func (service *RoleBindingServiceImpl) DeleteBinding(ctx context.Context, id int64) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tenantName := tenant.FromContext(ctx)
+	if err := service.RoleBindingRepository.Delete(ctx, tx, tenantName, id); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action: domain.AuditRoleBindingDeleted,
+		Tenant: tenantName,
+		Detail: fmt.Sprintf("binding %d deleted", id),
+	})
+
+	slog.InfoContext(ctx, "role binding deleted", "binding_id", id, "tenant", tenantName, "by", auth.Subject(ctx))
+}
+
+func (service *RoleBindingServiceImpl) Authorize(ctx context.Context, role, schema, name string) {
+	if service.Allowed(ctx, role, schema, name) {
+		return
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	slog.WarnContext(ctx, "access denied", "principal", principal.Subject, "role", role, "schema", schema, "name", name)
+	panic(exception.NewForbiddenError(fmt.Sprintf("%s requires the %s role on %s/%s", principal.Subject, role, schema, name)))
+}
+
+func (service *RoleBindingServiceImpl) Allowed(ctx context.Context, role, schema, name string) bool {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.HasRole(role) {
+		return true
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	for _, binding := range service.RoleBindingRepository.FindByPrincipal(ctx, tx, tenant.FromContext(ctx), principal.Subject) {
+		if auth.Grants(binding.Role, role) && matches(binding.Schema, schema) && matches(binding.NamePattern, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches reports whether value matches the glob pattern of a binding.
+func matches(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"config-service/model/web"
+	"context"
+)
+
+// VariableService manages the variables that ${name} placeholders in the
+// configs of an environment are rendered with.
+type VariableService interface {
+	ListVariables(ctx context.Context) []web.VariableResponse
+	// SetVariable creates a variable or replaces its value.
+	SetVariable(ctx context.Context, name string, request web.VariableSetRequest) web.VariableResponse
+	DeleteVariable(ctx context.Context, name string)
+}
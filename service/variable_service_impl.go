@@ -0,0 +1,139 @@
+package service
+
+import (
+	"config-service/auth"
+	"config-service/environment"
+	"config-service/exception"
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/tenant"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+type VariableServiceImpl struct {
+	VariableRepository repository.VariableRepository
+	DB                 *sql.DB
+	Validate           *validator.Validate
+	RoleBindings       RoleBindingService
+	Audit              AuditService
+	// Environments lists the environments besides environment.Default, nil
+	// accepts every valid name
+	Environments []string
+}
+
+func NewVariableService(variableRepository repository.VariableRepository, DB *sql.DB, validate *validator.Validate, auditService AuditService, environments []string) VariableService {
+	return &VariableServiceImpl{
+		VariableRepository: variableRepository,
+		DB:                 DB,
+		Validate:           validate,
+		RoleBindings:       NewRoleBindingService(repository.NewRoleBindingRepository(), DB, validate, nil),
+		Audit:              auditService,
+		Environments:       environments,
+	}
+}
+
+// Variables apply to every config of an environment, so they require a role
+// on every config.
+func (service *VariableServiceImpl) ListVariables(ctx context.Context) []web.VariableResponse {
+	service.RoleBindings.Authorize(ctx, auth.RoleReader, "*", "*")
+
+	environmentName := validateEnvironment(service.Environments, environment.FromContext(ctx))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	responses := []web.VariableResponse{}
+	for _, variable := range service.VariableRepository.FindAll(ctx, tx, tenant.FromContext(ctx), environmentName) {
+		responses = append(responses, helper.ToVariableResponse(variable))
+	}
+
+	return responses
+}
+
+func (service *VariableServiceImpl) SetVariable(ctx context.Context, name string, request web.VariableSetRequest) web.VariableResponse {
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, "*", "*")
+
+	// Validate incoming request payload
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if !validVariableName(name) {
+		helper.PanicIfError(helper.ValidationError{Msg: fmt.Sprintf("invalid variable name %q", name)})
+	}
+
+	environmentName := validateEnvironment(service.Environments, environment.FromContext(ctx))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	variable := domain.Variable{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Name:        name,
+		Value:       request.Value,
+		UpdatedAt:   time.Now().UTC(),
+		UpdatedBy:   auth.Subject(ctx),
+	}
+
+	var before map[string]interface{}
+	for _, current := range service.VariableRepository.FindAll(ctx, tx, variable.Tenant, variable.Environment) {
+		if current.Name == name {
+			before = map[string]interface{}{"value": current.Value}
+		}
+	}
+
+	variable = service.VariableRepository.Save(ctx, tx, variable)
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action:      domain.AuditVariableSet,
+		Tenant:      variable.Tenant,
+		Environment: variable.Environment,
+		Name:        variable.Name,
+		BeforeHash:  helper.DataHash(before),
+		AfterHash:   helper.DataHash(map[string]interface{}{"value": variable.Value}),
+		Detail:      fmt.Sprintf("variable %s set", variable.Name),
+	})
+
+	slog.InfoContext(ctx, "variable set", "environment", variable.Environment, "name", variable.Name, "by", variable.UpdatedBy)
+
+	return helper.ToVariableResponse(variable)
+}
+
+func (service *VariableServiceImpl) DeleteVariable(ctx context.Context, name string) {
+	service.RoleBindings.Authorize(ctx, auth.RoleWriter, "*", "*")
+
+	environmentName := validateEnvironment(service.Environments, environment.FromContext(ctx))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	variable := domain.Variable{
+		Tenant:      tenant.FromContext(ctx),
+		Environment: environmentName,
+		Name:        name,
+	}
+	if err := service.VariableRepository.Delete(ctx, tx, variable); err != nil {
+		panic(exception.NewNotFoundError(err.Error()))
+	}
+
+	service.Audit.Record(ctx, tx, domain.AuditEvent{
+		Action:      domain.AuditVariableDeleted,
+		Tenant:      variable.Tenant,
+		Environment: variable.Environment,
+		Name:        variable.Name,
+		Detail:      fmt.Sprintf("variable %s deleted", variable.Name),
+	})
+
+	slog.InfoContext(ctx, "variable deleted", "environment", variable.Environment, "name", variable.Name, "by", auth.Subject(ctx))
+}
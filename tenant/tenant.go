@@ -0,0 +1,39 @@
+// Package tenant holds the tenant a request operates on. Schemas, configs
+// and their versions are isolated per tenant.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Default is the tenant of requests that do not name one, and of the data
+// stored before tenants were introduced.
+const Default = "default"
+
+// Header selects the tenant of HTTP requests, and as lowercase metadata key
+// the tenant of gRPC requests.
+const Header = "X-Tenant"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Valid reports whether name can be used as a tenant: lowercase letters,
+// digits, underscores and dashes, starting with a letter or digit.
+func Valid(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+type tenantKey struct{}
+
+// With returns a copy of ctx operating on tenant.
+func With(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant of ctx, Default when none was resolved.
+func FromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return Default
+}
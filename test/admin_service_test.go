@@ -0,0 +1,198 @@
+package test
+
+import (
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdminService() service.AdminService {
+	return service.NewAdminService(newTestConfigRepository(testSettings()), repository.NewVariableRepository(), repository.NewLabelRepository(), repository.NewFreezeRepository(), db, newTestAuditService())
+}
+
+func createPaymentVersions(t *testing.T, maxLimits ...int) {
+	for i, maxLimit := range maxLimits {
+		method := http.MethodPut
+		if i == 0 {
+			method = http.MethodPost
+		}
+		body := strings.NewReader(`{"max_limit":` + strconv.Itoa(maxLimit) + `,"enabled":true}`)
+		_, res := performRequest(method, "/configs/payment_config/payments", body, i == 0)
+		assert.Less(t, res.StatusCode, 300)
+	}
+}
+
+func TestAdminValidateSchemas(t *testing.T) {
+	results := setupAdminService().ValidateSchemas()
+
+	assert.NotEmpty(t, results)
+	for _, result := range results {
+		assert.True(t, result.Valid, result.Error)
+	}
+}
+
+func TestAdminRevalidateConfigs(t *testing.T) {
+	createPaymentVersions(t, 100, 200)
+
+	// Sneak an invalid document past the API
+	_, err := db.Exec(`INSERT INTO configs (schema, name, version, data) VALUES ('payment_config', 'broken', 1, '{"max_limit":"x"}')`)
+	assert.NoError(t, err)
+
+	results := setupAdminService().RevalidateConfigs(context.Background(), false)
+	assert.Len(t, results, 2)
+
+	invalid := 0
+	for _, result := range results {
+		if !result.Valid {
+			invalid++
+			assert.Equal(t, "broken", result.Name)
+		}
+	}
+	assert.Equal(t, 1, invalid)
+
+	assert.Len(t, setupAdminService().RevalidateConfigs(context.Background(), true), 3)
+}
+
+func TestAdminExportImportRoundTrip(t *testing.T) {
+	createPaymentVersions(t, 100, 200)
+	admin := setupAdminService()
+
+	exported := admin.ExportConfigs(context.Background())
+	assert.Len(t, exported, 2)
+
+	truncateConfigs(db)
+	result := admin.ImportConfigs(context.Background(), exported)
+	assert.Equal(t, web.ImportResult{Imported: 2}, result)
+
+	// Importing the same export again is a no-op
+	result = admin.ImportConfigs(context.Background(), exported)
+	assert.Equal(t, web.ImportResult{Skipped: 2}, result)
+
+	fetchResp, fetchHTTP := performRequest(http.MethodGet, "/configs/payment_config/payments", nil, false)
+	assert.Equal(t, http.StatusOK, fetchHTTP.StatusCode)
+	assert.Equal(t, 2, int(fetchResp["version"].(float64)))
+}
+
+func TestAdminImportRejectsInvalidConfig(t *testing.T) {
+	truncateConfigs(db)
+	admin := setupAdminService()
+
+	configs := []web.ConfigResponse{
+		{Schema: "payment_config", Name: "payments", Version: 1, Data: map[string]interface{}{"max_limit": 1, "enabled": true}},
+		{Schema: "payment_config", Name: "payments", Version: 2, Data: map[string]interface{}{"max_limit": "x"}},
+	}
+
+	assert.Panics(t, func() {
+		admin.ImportConfigs(context.Background(), configs)
+	})
+
+	// The valid version must have been rolled back as well
+	assert.Empty(t, admin.ExportConfigs(context.Background()))
+}
+
+func TestAdminCompactKeepsNewestVersions(t *testing.T) {
+	createPaymentVersions(t, 100, 200, 300, 400)
+
+	result := setupAdminService().CompactConfigs(context.Background(), 2, "")
+	assert.Equal(t, 2, result.Deleted)
+
+	versionsResp, _ := performRequest(http.MethodGet, "/configs/payment_config/payments/versions", nil, false)
+	versions := versionsResp["configVersions"].([]interface{})
+	assert.Len(t, versions, 2)
+	assert.Equal(t, 3, int(versions[0].(map[string]interface{})["version"].(float64)))
+}
+
+func TestAdminCompactKeepsActiveVersionWhileScheduled(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/payments"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(2*time.Hour)), "", "", `{"max_limit":400,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":4}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Only version 1 goes, the active version 2 stays with the scheduled
+	// version 3 and the cancelled version 4
+	result := setupAdminService().CompactConfigs(context.Background(), 1, "")
+	assert.Equal(t, 1, result.Deleted)
+
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, decodeConfig(t, rec).Version)
+
+	rec = performTenantRequest(router, http.MethodGet, path+"/versions", "", "", "")
+	var versions web.ConfigResponses
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &versions))
+	assert.Len(t, versions.ConfigVersions, 3)
+}
+
+func TestAdminCompactSkipsFrozenConfigs(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	name := auditName()
+	for _, path := range []string{"/configs/payment_config/" + name, "/configs/payment_config/unfrozen"} {
+		rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":200,"enabled":true}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	freeze := createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", NamePattern: name, Reason: "release"})
+	defer deleteTestFreeze(t, freeze.ID)
+
+	result := setupAdminService().CompactConfigs(context.Background(), 1, "")
+	assert.Equal(t, web.CompactResult{Deleted: 1, Skipped: 1}, result)
+	rec := performTenantRequest(router, http.MethodGet, "/configs/payment_config/"+name+"?version=1", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Overriding the freeze is recorded with the deleted versions
+	result = setupAdminService().CompactConfigs(context.Background(), 1, "reclaim space")
+	assert.Equal(t, web.CompactResult{Deleted: 1}, result)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/"+name+"?version=1", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	events := auditEvents(t, domain.AuditFilter{Action: domain.AuditConfigDeleted, Name: name})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, fmt.Sprintf("compacted to the newest 1 version(s), overrode freezes [%d]: reclaim space", freeze.ID), events[0].Detail)
+	}
+}
+
+func TestAdminRevalidateConfigsChecksServedVersions(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/payments"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(2*time.Hour)), "", "", `{"max_limit":400,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The active version 1 and the scheduled version 2, not the cancelled one
+	var checked []int
+	for _, result := range setupAdminService().RevalidateConfigs(context.Background(), false) {
+		checked = append(checked, result.Version)
+	}
+	assert.Equal(t, []int{1, 2}, checked)
+}
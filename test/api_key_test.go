@@ -0,0 +1,144 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/repository"
+	"config-service/service"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authRouter builds a router that rejects anonymous requests.
+func authRouter() http.Handler {
+	settings := testSettings()
+	settings.AuthRequired = true
+	return setupRouterWithSettings(db, settings)
+}
+
+func createTestAPIKey(t *testing.T, name string, admin bool) web.APIKeyCreateResponse {
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(), db, validator.New(), newTestAuditService())
+	return apiKeyService.CreateKey(context.Background(), web.APIKeyCreateRequest{Name: name, Admin: admin})
+}
+
+func performAuthRequest(router http.Handler, method, path, apiKey string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIKeyRequired(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+
+	rec := performAuthRequest(router, http.MethodGet, "/configs/payment_config/payments", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "missing credentials")
+
+	rec = performAuthRequest(router, http.MethodGet, "/configs/payment_config/payments", "csk_unknown", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid api key")
+
+	// Probes stay public
+	rec = performAuthRequest(router, http.MethodGet, "/healthz", "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAPIKeyIdentityIsRecordedInVersions(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	key := createTestAPIKey(t, "deployer", false)
+	bindTestRole(t, "apikey:deployer", auth.RoleWriter, "payment_config", "*")
+	assert.True(t, strings.HasPrefix(key.Key, key.Prefix))
+
+	rec := performAuthRequest(router, http.MethodPost, "/configs/payment_config/payments", key.Key, strings.NewReader(`{"max_limit":1000,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/configs/payment_config/payments/versions", key.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var versions web.ConfigResponses
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &versions))
+	if assert.Len(t, versions.ConfigVersions, 1) {
+		assert.Equal(t, "apikey:deployer", versions.ConfigVersions[0].CreatedBy)
+	}
+}
+
+func TestAPIKeyAdminEndpoints(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	admin := createTestAPIKey(t, "ops", true)
+	reader := createTestAPIKey(t, "reader", false)
+
+	rec := performAuthRequest(router, http.MethodGet, "/admin/api-keys", reader.Key, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/api-keys", admin.Key, strings.NewReader(`{"name":"ci"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var created web.APIKeyCreateResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Key)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/api-keys", admin.Key, strings.NewReader(`{"name":"ci"}`))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/schemas/", created.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/api-keys", admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var keys []web.APIKeyResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &keys))
+	assert.Len(t, keys, 3)
+	assert.NotContains(t, rec.Body.String(), created.Key)
+	for _, key := range keys {
+		if key.Name == "ci" {
+			assert.NotNil(t, key.LastUsedAt)
+		}
+	}
+
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/api-keys/"+created.ID, admin.Key, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/schemas/", created.Key, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/api-keys/"+created.ID, admin.Key, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestGRPCAPIKeyAuthentication(t *testing.T) {
+	settings := testSettings()
+	settings.AuthRequired = true
+	client := setupGRPCClientWithSettings(t, settings)
+	key := createTestAPIKey(t, "grpc", false)
+	bindTestRole(t, "apikey:grpc", auth.RoleWriter, "*", "*")
+
+	request := &configpb.CreateConfigRequest{Schema: "payment_config", Name: "payments", Data: paymentData(t, 1000, true)}
+
+	_, err := client.CreateConfig(context.Background(), request)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), auth.APIKeyHeader, key.Key)
+	created, err := client.CreateConfig(ctx, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "apikey:grpc", created.CreatedBy)
+}
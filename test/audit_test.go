@@ -0,0 +1,213 @@
+package test
+
+import (
+	"config-service/helper"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// auditName returns a config name that is unique across runs, because the
+// append-only audit log is never truncated.
+func auditName() string {
+	return "audit-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+func auditEvents(t *testing.T, filter domain.AuditFilter) []web.AuditEventResponse {
+	return newTestAuditService().ListEvents(context.Background(), filter)
+}
+
+func TestAuditRecordsConfigChanges(t *testing.T) {
+	truncateConfigs(db)
+	name := auditName()
+	path := "/configs/payment_config/" + name
+
+	_, res := performRequest(http.MethodPost, path, strings.NewReader(`{"max_limit":1000,"enabled":true}`), false)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	_, res = performRequest(http.MethodPut, path, strings.NewReader(`{"max_limit":2000,"enabled":false}`), false)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	_, res = performRequest(http.MethodPost, path+"/rollback", strings.NewReader(`{"version":1}`), false)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	events := auditEvents(t, domain.AuditFilter{Schema: "payment_config", Name: name})
+	assert.Len(t, events, 3)
+
+	// Newest first
+	rollback, update, create := events[0], events[1], events[2]
+	v1 := helper.DataHash(map[string]interface{}{"max_limit": float64(1000), "enabled": true})
+	v2 := helper.DataHash(map[string]interface{}{"max_limit": float64(2000), "enabled": false})
+
+	assert.Equal(t, domain.AuditConfigCreated, create.Action)
+	assert.Equal(t, domain.AuditSuccess, create.Outcome)
+	assert.Equal(t, "anonymous", create.Actor)
+	assert.NotEmpty(t, create.Source)
+	assert.Equal(t, 1, create.Version)
+	assert.Empty(t, create.BeforeHash)
+	assert.Equal(t, v1, create.AfterHash)
+
+	assert.Equal(t, domain.AuditConfigUpdated, update.Action)
+	assert.Equal(t, v1, update.BeforeHash)
+	assert.Equal(t, v2, update.AfterHash)
+
+	assert.Equal(t, domain.AuditConfigRolledBack, rollback.Action)
+	assert.Equal(t, 3, rollback.Version)
+	assert.Equal(t, v2, rollback.BeforeHash)
+	assert.Equal(t, v1, rollback.AfterHash)
+	assert.Equal(t, "rolled back to version 1", rollback.Detail)
+
+	// Every entry links to its predecessor
+	assert.Equal(t, create.Hash, update.PrevHash)
+	assert.Equal(t, update.Hash, rollback.PrevHash)
+}
+
+func TestAuditSkipsFailedChanges(t *testing.T) {
+	truncateConfigs(db)
+	name := auditName()
+
+	_, res := performRequest(http.MethodPost, "/configs/payment_config/"+name, strings.NewReader(`{"max_limit":"many"}`), false)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	assert.Empty(t, auditEvents(t, domain.AuditFilter{Schema: "payment_config", Name: name}))
+}
+
+func TestAuditRecordsDeniedRequests(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	name := auditName()
+
+	rec := performAuthRequest(router, http.MethodGet, "/configs/payment_config/"+name, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	key := createTestAPIKey(t, "auditor", false)
+	rec = performAuthRequest(router, http.MethodPut, "/configs/payment_config/"+name, key.Key, strings.NewReader(`{"max_limit":1,"enabled":true}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	events := auditEvents(t, domain.AuditFilter{Outcome: domain.AuditDenied, Schema: "payment_config", Name: name})
+	assert.Len(t, events, 2)
+	assert.Equal(t, domain.AuditRequestDenied, events[0].Action)
+	assert.Equal(t, "apikey:auditor", events[0].Actor)
+	assert.Contains(t, events[0].Detail, "requires the writer role")
+	assert.Equal(t, "anonymous", events[1].Actor)
+	assert.Equal(t, "missing credentials", events[1].Detail)
+}
+
+func TestAuditEndpoint(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	admin := createTestAPIKey(t, "admin", true)
+	name := auditName()
+
+	rec := performAuthRequest(router, http.MethodPost, "/configs/payment_config/"+name, admin.Key, strings.NewReader(`{"max_limit":1000,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit?action=config.created&actor=apikey:admin&name="+name, admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var events []web.AuditEventResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	assert.Len(t, events, 1)
+	assert.Equal(t, name, events[0].Name)
+
+	// Time filters exclude the event
+	since := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit?name="+name+"&since="+since, admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit?since=yesterday", admin.Key, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit?limit=1&before_id="+strconv.FormatInt(events[0].ID, 10), admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	assert.Len(t, events, 1)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit/verify", admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var verify web.AuditVerifyResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &verify))
+	assert.True(t, verify.Valid, verify.Error)
+	assert.Greater(t, verify.Checked, 0)
+
+	// Only admins may read the audit log
+	writer := createTestAPIKey(t, "writer", false)
+	rec = performAuthRequest(router, http.MethodGet, "/admin/audit", writer.Key, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAuditLogIsAppendOnly(t *testing.T) {
+	truncateConfigs(db)
+	_, res := performRequest(http.MethodPost, "/configs/payment_config/"+auditName(), strings.NewReader(`{"max_limit":1000,"enabled":true}`), false)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	_, err := db.Exec("UPDATE audit_log SET actor = 'mallory'")
+	assert.ErrorContains(t, err, "append-only")
+
+	_, err = db.Exec("DELETE FROM audit_log")
+	assert.ErrorContains(t, err, "append-only")
+}
+
+func TestAuditVerifyDetectsTampering(t *testing.T) {
+	truncateConfigs(db)
+	name := auditName()
+	_, res := performRequest(http.MethodPost, "/configs/payment_config/"+name, strings.NewReader(`{"max_limit":1000,"enabled":true}`), false)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	event := auditEvents(t, domain.AuditFilter{Name: name})[0]
+
+	// Bypass the trigger like an attacker with file access would, and
+	// restore the entry and the trigger afterwards
+	var trigger string
+	assert.NoError(t, db.QueryRow("SELECT sql FROM sqlite_master WHERE name = 'audit_log_no_update'").Scan(&trigger))
+	_, err := db.Exec("DROP TRIGGER audit_log_no_update")
+	assert.NoError(t, err)
+	defer func() {
+		_, err := db.Exec("UPDATE audit_log SET actor = ? WHERE id = ?", event.Actor, event.ID)
+		assert.NoError(t, err)
+		_, err = db.Exec(trigger)
+		assert.NoError(t, err)
+	}()
+
+	_, err = db.Exec("UPDATE audit_log SET actor = 'mallory' WHERE id = ?", event.ID)
+	assert.NoError(t, err)
+
+	result := newTestAuditService().Verify(context.Background())
+	assert.False(t, result.Valid)
+	assert.Equal(t, event.ID, result.FirstInvalidID)
+	assert.Equal(t, "entry does not match its hash", result.Error)
+}
+
+func TestAuditRecordsSchemaChanges(t *testing.T) {
+	auditService := newTestAuditService()
+	ctx := context.Background()
+	auditService.RecordSchemaChanges(ctx)
+
+	name := "audit_schema_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	domain.Schemas[tenant.Default][name] = `{"type":"object"}`
+	auditService.RecordSchemaChanges(ctx)
+	domain.Schemas[tenant.Default][name] = `{"type":"object","required":["id"]}`
+	auditService.RecordSchemaChanges(ctx)
+	delete(domain.Schemas[tenant.Default], name)
+	auditService.RecordSchemaChanges(ctx)
+	auditService.RecordSchemaChanges(ctx)
+
+	events := auditEvents(t, domain.AuditFilter{Schema: name})
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, domain.AuditSchemaRemoved, events[0].Action)
+		assert.Equal(t, domain.AuditSchemaChanged, events[1].Action)
+		assert.Equal(t, domain.AuditSchemaCreated, events[2].Action)
+		assert.Equal(t, "system", events[2].Actor)
+		assert.Equal(t, events[2].AfterHash, events[1].BeforeHash)
+		assert.Equal(t, events[1].AfterHash, events[0].BeforeHash)
+	}
+}
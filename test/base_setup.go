@@ -6,35 +6,41 @@ import (
 	"config-service/helper"
 	"config-service/model/domain"
 	"config-service/repository"
+	"config-service/secret"
 	"config-service/service"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator"
 )
 
-var db *sql.DB
+var (
+	db     *sql.DB
+	dbPath string
+)
 
+// setupTestDB opens a fresh database in a temporary directory, removed by
+// TestMain once the tests ran.
 func setupTestDB() {
-	var err error
-	db, err = sql.Open("sqlite3", "config_database_testing.db")
+	dir, err := os.MkdirTemp("", "config-service-test-")
+	helper.PanicIfError(err)
+
+	dbPath = filepath.Join(dir, "config_database_testing.db")
+	db, err = sql.Open("sqlite3", dbPath)
 	helper.PanicIfError(err)
 
-	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS configs (
-        id INTEGER PRIMARY KEY AUTOINCREMENT,
-		schema TEXT NOT NULL,
-        name TEXT NOT NULL,
-        version INTEGER NOT NULL,
-        data TEXT NOT NULL,
-        created_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    );`)
+	_, err = app.Migrate(context.Background(), db)
 	helper.PanicIfError(err)
 
 	db.SetMaxIdleConns(5)
@@ -45,19 +51,81 @@ func setupTestDB() {
 }
 
 func setupRouter(db *sql.DB) http.Handler {
+	return setupRouterWithSettings(db, testSettings())
+}
+
+func newTestAuditService() service.AuditService {
+	return service.NewAuditService(repository.NewAuditRepository(), db)
+}
+
+// testSecretKey is the base64 encoded key encrypting secret values in tests.
+const testSecretKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func newTestKeyring(settings app.Settings) *secret.Keyring {
+	keyring, err := secret.NewKeyring(settings.SecretKeys, settings.SecretKeyID)
+	helper.PanicIfError(err)
+	return keyring
+}
+
+func newTestConfigRepository(settings app.Settings) repository.ConfigRepository {
+	return repository.NewSecretConfigRepository(repository.NewConfigRepository(), newTestKeyring(settings))
+}
+
+func setupRouterWithSettings(db *sql.DB, settings app.Settings) http.Handler {
 	validate := validator.New()
-	configRepository := repository.NewConfigRepository()
-	configService := service.NewConfigService(configRepository, db, validate)
+	auditService := newTestAuditService()
+	configRepository := newTestConfigRepository(settings)
+	variableRepository := repository.NewVariableRepository()
+	freezeService := service.NewFreezeService(repository.NewFreezeRepository(), db, validate, auditService)
+	configService := service.NewConfigService(configRepository, db, validate,
+		service.WithAuditService(auditService),
+		service.WithTenantQuotas(service.TenantQuotas{Default: settings.TenantMaxConfigs, Limits: settings.TenantQuotas}),
+		service.WithEnvironments(settings.Environments),
+		service.WithVariables(variableRepository),
+		service.WithDrafts(repository.NewSecretDraftRepository(repository.NewDraftRepository(), newTestKeyring(settings)), settings.RequiredApprovals),
+		service.WithFreezes(freezeService),
+		service.WithLabels(repository.NewLabelRepository()),
+	)
 	configController := controller.NewConfigController(configService)
 	schemaController := controller.NewSchemaController()
+	healthService := service.NewHealthService(configRepository, db, app.MigrationStatus(db))
+	healthController := controller.NewHealthController(healthService)
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(), db, validate, auditService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	roleBindingController := controller.NewRoleBindingController(service.NewRoleBindingService(repository.NewRoleBindingRepository(), db, validate, auditService))
+	freezeController := controller.NewFreezeController(freezeService)
+	auditController := controller.NewAuditController(auditService)
+	variableController := controller.NewVariableController(service.NewVariableService(variableRepository, db, validate, auditService, settings.Environments))
+	draftController := controller.NewDraftController(configService)
+
+	authenticator, err := app.NewAuthenticator(context.Background(), settings, apiKeyService, auditService)
+	helper.PanicIfError(err)
 
-	router := app.NewRouter(configController, schemaController)
+	router := app.NewRouter(settings, authenticator, configController, schemaController, healthController, apiKeyController, roleBindingController, freezeController, auditController, variableController, draftController)
 
 	return router
 }
 
+func testSettings() app.Settings {
+	settings := app.DefaultSettings()
+	settings.DBPath = dbPath
+	settings.SchemaDir = "../schemas"
+	settings.GinMode = gin.TestMode
+	settings.AuthRequired = false
+	settings.SecretKeys = map[string]string{"test": testSecretKey}
+	settings.SecretKeyID = "test"
+	return settings
+}
+
 func truncateConfigs(db *sql.DB) {
 	db.Exec("DELETE from configs")
+	db.Exec("DELETE from api_keys")
+	db.Exec("DELETE from role_bindings")
+	db.Exec("DELETE from variables")
+	db.Exec("DELETE from draft_reviews")
+	db.Exec("DELETE from drafts")
+	db.Exec("DELETE from freezes")
+	db.Exec("DELETE from config_labels")
 	db.Exec("VACUUM")
 }
 
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUpdatesCreateAndUpdateConfigs(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs?env=staging", "", "", `{"items":[
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true}},
+		{"schema":"payment_config","name":"refunds","data":{"max_limit":50,"enabled":false},"expected_version":0}
+	]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var batch web.ConfigBatchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &batch))
+	// The batch writes to the environment of the request
+	assert.Equal(t, "staging", batch.Environment)
+	if assert.Len(t, batch.Configs, 2) {
+		assert.Equal(t, 1, batch.Configs[0].Version)
+		assert.Equal(t, "refunds", batch.Configs[1].Name)
+		assert.Equal(t, 1, batch.Configs[1].Version)
+	}
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":300,"enabled":true},"expected_version":1},
+		{"schema":"payment_config","name":"refunds","data":{"max_limit":60,"enabled":true}}
+	]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &batch))
+	if assert.Len(t, batch.Configs, 2) {
+		assert.Equal(t, 2, batch.Configs[0].Version)
+		assert.Equal(t, float64(300), batch.Configs[0].Data["max_limit"])
+		assert.Equal(t, 1, batch.Configs[1].Version)
+	}
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/checkout", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, decodeConfig(t, rec).Version)
+
+	events := newTestAuditService().ListEvents(context.Background(), domain.AuditFilter{Action: domain.AuditConfigUpdated, Schema: "payment_config", Name: "checkout"})
+	if assert.NotEmpty(t, events) {
+		assert.Equal(t, "batch of 2 configs", events[0].Detail)
+	}
+}
+
+func TestBatchUpdatesRollBackWhenAnItemFails(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// The second item does not match the schema
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true}},
+		{"schema":"payment_config","name":"refunds","data":{"max_limit":"many","enabled":true}}
+	]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorData(t, rec), "items[1] payment_config/refunds")
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/checkout", "", "", "")
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/refunds", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Expected versions guard against concurrent changes
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[
+		{"schema":"payment_config","name":"refunds","data":{"max_limit":50,"enabled":true}},
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true},"expected_version":2}
+	]}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "items[1] payment_config/checkout: expected version 2, the latest is 1")
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true},"expected_version":0}
+	]}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/refunds", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Items must name distinct configs
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true}},
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":300,"enabled":true}}
+	]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorData(t, rec), "items[1] repeats payment_config/checkout")
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", `{"items":[]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBatchUpdatesRespectFreezes(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	freeze := createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", NamePattern: "checkout", Reason: "release"})
+
+	body := `{"items":[
+		{"schema":"payment_config","name":"refunds","data":{"max_limit":50,"enabled":true}},
+		{"schema":"payment_config","name":"checkout","data":{"max_limit":200,"enabled":true}}
+	]}`
+	rec = performTenantRequest(router, http.MethodPost, "/configs", "", "", body)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Contains(t, errorData(t, rec), "items[1] payment_config/checkout")
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/refunds", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs"+overrideQuery("urgent fix"), "", "", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var batch web.ConfigBatchResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &batch))
+	if assert.Len(t, batch.Configs, 2) {
+		assert.NotContains(t, batch.Configs[0].Metadata, "freeze_override")
+		assert.Contains(t, batch.Configs[1].Metadata, "freeze_override")
+	}
+
+	deleteTestFreeze(t, freeze.ID)
+}
+
+func TestClientBatchUpdates(t *testing.T) {
+	server := setupClientServer(t, nil)
+	c := client.New(server.URL, client.WithEnvironment("staging"))
+	ctx := context.Background()
+	created := 0
+
+	batch, err := c.BatchUpdateConfigs(ctx, []web.ConfigBatchItem{
+		{Schema: "payment_config", Name: "checkout", Data: map[string]interface{}{"max_limit": 100, "enabled": true}, ExpectedVersion: &created},
+		{Schema: "payment_config", Name: "refunds", Data: map[string]interface{}{"max_limit": 50, "enabled": true}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", batch.Environment)
+	assert.Len(t, batch.Configs, 2)
+
+	_, err = c.BatchUpdateConfigs(ctx, []web.ConfigBatchItem{
+		{Schema: "payment_config", Name: "checkout", Data: map[string]interface{}{"max_limit": 200, "enabled": true}, ExpectedVersion: &created},
+	})
+	assert.True(t, client.IsConflict(err))
+
+	config, err := c.FetchConfig(ctx, "payment_config", "checkout", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, config.Version)
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeBulk(t *testing.T, body []byte) web.ConfigBulkResponse {
+	var bulk web.ConfigBulkResponse
+	assert.NoError(t, json.Unmarshal(body, &bulk))
+	return bulk
+}
+
+func TestBulkFetchReturnsResultsPerSelector(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/payment_config/checkout", "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/refunds", "", "", `{"max_limit":50,"enabled":false}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, "/configs/payment_config/checkout/labels/stable", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs?select=payment_config/checkout&select=payment_config/checkout@1"+
+		"&select=payment_config/refunds&select=payment_config/missing&select=unknown/checkout"+
+		"&select=payment_config/checkout@stable&select=checkout&select=payment_config/refunds@stable&select=payment_config/checkout@-1", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	bulk := decodeBulk(t, rec.Body.Bytes())
+	assert.Equal(t, "default", bulk.Environment)
+	if !assert.Len(t, bulk.Results, 9) {
+		return
+	}
+
+	assert.Equal(t, "payment_config/checkout", bulk.Results[0].Selector)
+	if assert.NotNil(t, bulk.Results[0].Config) {
+		assert.Equal(t, 2, bulk.Results[0].Config.Version)
+		assert.Equal(t, float64(200), bulk.Results[0].Config.Data["max_limit"])
+	}
+	if assert.NotNil(t, bulk.Results[1].Config) {
+		assert.Equal(t, 1, bulk.Results[1].Config.Version)
+		assert.Equal(t, float64(100), bulk.Results[1].Config.Data["max_limit"])
+	}
+	if assert.NotNil(t, bulk.Results[2].Config) {
+		assert.Equal(t, "refunds", bulk.Results[2].Config.Name)
+		assert.Empty(t, bulk.Results[2].Error)
+	}
+
+	assert.Nil(t, bulk.Results[3].Config)
+	assert.Equal(t, http.StatusNotFound, bulk.Results[3].Code)
+	assert.Equal(t, http.StatusBadRequest, bulk.Results[4].Code)
+	assert.Equal(t, "unknown schema", bulk.Results[4].Error)
+
+	// Versions are selected by number or by label
+	if assert.NotNil(t, bulk.Results[5].Config) {
+		assert.Equal(t, 1, bulk.Results[5].Config.Version)
+	}
+	assert.Equal(t, http.StatusBadRequest, bulk.Results[6].Code)
+	assert.Contains(t, bulk.Results[6].Error, "must be schema/name")
+	assert.Equal(t, http.StatusNotFound, bulk.Results[7].Code)
+	assert.Contains(t, bulk.Results[7].Error, "label stable of payment_config/refunds is not found")
+	assert.Equal(t, http.StatusBadRequest, bulk.Results[8].Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBulkFetchServesTheRequestedEnvironment(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout?env=staging", "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs?env=staging&select=payment_config/checkout", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	bulk := decodeBulk(t, rec.Body.Bytes())
+	assert.Equal(t, "staging", bulk.Environment)
+	if assert.Len(t, bulk.Results, 1) && assert.NotNil(t, bulk.Results[0].Config) {
+		assert.Equal(t, float64(300), bulk.Results[0].Config.Data["max_limit"])
+	}
+}
+
+func TestBulkFetchAuthorizesEverySelector(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	writer := createTestAPIKey(t, "writer", false)
+	reader := createTestAPIKey(t, "reader", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "*", "*")
+	bindTestRole(t, "apikey:reader", auth.RoleReader, "payment_config", "public-*")
+
+	body := `{"max_limit":100,"enabled":true}`
+	for _, name := range []string{"public-checkout", "internal"} {
+		rec := performAuthRequest(router, http.MethodPost, "/configs/payment_config/"+name, writer.Key, strings.NewReader(body))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+
+	rec := performAuthRequest(router, http.MethodGet, "/configs?select=payment_config/public-checkout&select=payment_config/internal", reader.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	bulk := decodeBulk(t, rec.Body.Bytes())
+	if assert.Len(t, bulk.Results, 2) {
+		assert.NotNil(t, bulk.Results[0].Config)
+		assert.Nil(t, bulk.Results[1].Config)
+		assert.Equal(t, http.StatusForbidden, bulk.Results[1].Code)
+	}
+}
+
+func TestClientBulkFetch(t *testing.T) {
+	server := setupClientServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.CreateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 100, "enabled": true})
+	assert.NoError(t, err)
+	_, err = c.UpdateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 200, "enabled": true})
+	assert.NoError(t, err)
+
+	bulk, err := c.BulkFetch(ctx, "payment_config/checkout", "payment_config/checkout@1", "payment_config/missing")
+	assert.NoError(t, err)
+	if assert.Len(t, bulk.Results, 3) {
+		assert.Equal(t, 2, bulk.Results[0].Config.Version)
+		assert.Equal(t, 1, bulk.Results[1].Config.Version)
+		assert.Equal(t, http.StatusNotFound, bulk.Results[2].Code)
+	}
+
+	_, err = c.BulkFetch(ctx)
+	assert.True(t, client.IsValidationError(err))
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"config-service/client"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type paymentConfig struct {
+	MaxLimit int  `json:"max_limit"`
+	Enabled  bool `json:"enabled"`
+}
+
+// statusRecorder captures the status code written by the router.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func setupClientServer(t *testing.T, handler http.Handler) *httptest.Server {
+	truncateConfigs(db)
+
+	if handler == nil {
+		handler = setupRouter(db)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientCreateUpdateFetchSuccess(t *testing.T) {
+	server := setupClientServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	created, err := c.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 1000, Enabled: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.Version)
+
+	updated, err := c.UpdateConfig(ctx, "payment_config", "payments", map[string]interface{}{"max_limit": 2000, "enabled": false})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+
+	first, err := client.Fetch[paymentConfig](ctx, c, "payment_config", "payments", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, paymentConfig{MaxLimit: 1000, Enabled: true}, first.Data)
+
+	latest, err := c.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+	assert.Equal(t, float64(2000), latest.Data["max_limit"])
+
+	rolledBack, err := c.RollbackConfig(ctx, "payment_config", "payments", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, rolledBack.Version)
+
+	versions, err := c.ListVersions(ctx, "payment_config", "payments")
+	assert.NoError(t, err)
+	assert.Len(t, versions.ConfigVersions, 3)
+}
+
+func TestClientErrors(t *testing.T) {
+	server := setupClientServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.True(t, client.IsNotFound(err))
+
+	_, err = c.CreateConfig(ctx, "payment_config", "payments", map[string]interface{}{"max_limit": 1000})
+	assert.True(t, client.IsValidationError(err))
+}
+
+func TestClientRevalidatesWithETag(t *testing.T) {
+	router := setupRouter(db)
+	var notModified int32
+	server := setupClientServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		router.ServeHTTP(rec, r)
+		if rec.status == http.StatusNotModified {
+			atomic.AddInt32(&notModified, 1)
+		}
+	}))
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 1000, Enabled: true})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		fetched, err := client.Fetch[paymentConfig](ctx, c, "payment_config", "payments", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, fetched.Data.MaxLimit)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&notModified))
+}
+
+func TestClientRetriesUnavailableService(t *testing.T) {
+	router := setupRouter(db)
+	var calls int32
+	server := setupClientServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	c := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+	ctx := context.Background()
+
+	_, err := c.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 1000, Enabled: true})
+	assert.NoError(t, err)
+
+	fetched, err := c.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fetched.Version)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestClientServesFallbackWhenUnreachable(t *testing.T) {
+	server := setupClientServer(t, nil)
+	fallbackPath := filepath.Join(t.TempDir(), "fallback.json")
+	c := client.New(server.URL, client.WithFallbackFile(fallbackPath), client.WithRetries(1, time.Millisecond))
+	ctx := context.Background()
+
+	_, err := c.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 1000, Enabled: true})
+	assert.NoError(t, err)
+
+	_, err = c.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+
+	server.Close()
+
+	// A fresh client only knows the fallback file
+	offline := client.New(server.URL, client.WithFallbackFile(fallbackPath), client.WithRetries(1, time.Millisecond))
+	fetched, err := client.Fetch[paymentConfig](ctx, offline, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, fetched.Data.MaxLimit)
+
+	_, err = offline.FetchConfig(ctx, "payment_config", "unknown", 0)
+	assert.True(t, client.IsUnavailable(err))
+}
@@ -201,3 +201,14 @@ func TestRollbackPaymentFunctionSuccess(t *testing.T) {
 	assert.True(t, props["enabled"].(bool))
 	assert.Equal(t, 1000, int(props["max_limit"].(float64)))
 }
+
+func TestCreateExistingPaymentConfigConflict(t *testing.T) {
+
+	requestBody := strings.NewReader(`{"max_limit":1000,"enabled":true}`)
+	_, createHTTP := performRequest(http.MethodPost, "/configs/payment_config/payments", requestBody, true)
+	assert.Equal(t, http.StatusCreated, createHTTP.StatusCode)
+
+	secondRequestBody := strings.NewReader(`{"max_limit":1000,"enabled":true}`)
+	_, secondCreateHTTP := performRequest(http.MethodPost, "/configs/payment_config/payments", secondRequestBody, false)
+	assert.Equal(t, http.StatusConflict, secondCreateHTTP.StatusCode)
+}
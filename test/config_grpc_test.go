@@ -0,0 +1,128 @@
+package test
+
+import (
+	"config-service/app"
+	"config-service/controller"
+	"config-service/proto/configpb"
+	"config-service/repository"
+	"config-service/service"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func setupGRPCClient(t *testing.T) configpb.ConfigServiceClient {
+	return setupGRPCClientWithSettings(t, testSettings())
+}
+
+func setupGRPCClientWithSettings(t *testing.T, settings app.Settings) configpb.ConfigServiceClient {
+	truncateConfigs(db)
+
+	validate := validator.New()
+	auditService := newTestAuditService()
+	configService := service.NewConfigService(newTestConfigRepository(settings), db, validate, service.WithAuditService(auditService), service.WithEnvironments(settings.Environments), service.WithVariables(repository.NewVariableRepository()), service.WithFreezes(service.NewFreezeService(repository.NewFreezeRepository(), db, validate, auditService)), service.WithLabels(repository.NewLabelRepository()))
+	apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(), db, validate, auditService)
+	authenticator, err := app.NewAuthenticator(context.Background(), settings, apiKeyService, auditService)
+	assert.NoError(t, err)
+	server := app.NewGRPCServer(authenticator, controller.NewConfigGRPCController(configService))
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return configpb.NewConfigServiceClient(conn)
+}
+
+func paymentData(t *testing.T, maxLimit int, enabled bool) *structpb.Struct {
+	data, err := structpb.NewStruct(map[string]interface{}{"max_limit": maxLimit, "enabled": enabled})
+	if err != nil {
+		t.Fatalf("failed to build struct: %v", err)
+	}
+	return data
+}
+
+func TestGRPCCreateAndFetchConfigSuccess(t *testing.T) {
+	client := setupGRPCClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateConfig(ctx, &configpb.CreateConfigRequest{
+		Schema: "payment_config",
+		Name:   "payments",
+		Data:   paymentData(t, 1000, true),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), created.GetVersion())
+
+	_, err = client.UpdateConfig(ctx, &configpb.UpdateConfigRequest{
+		Schema: "payment_config",
+		Name:   "payments",
+		Data:   paymentData(t, 2000, false),
+	})
+	assert.NoError(t, err)
+
+	fetched, err := client.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments", Version: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), fetched.GetVersion())
+	assert.Equal(t, float64(1000), fetched.GetData().AsMap()["max_limit"])
+
+	versions, err := client.ListVersions(ctx, &configpb.ListVersionsRequest{Schema: "payment_config", Name: "payments"})
+	assert.NoError(t, err)
+	assert.Len(t, versions.GetConfigVersions(), 2)
+}
+
+func TestGRPCErrorCodes(t *testing.T) {
+	client := setupGRPCClient(t)
+	ctx := context.Background()
+
+	_, err := client.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreateConfig(ctx, &configpb.CreateConfigRequest{Schema: "unknown_config", Name: "payments", Data: paymentData(t, 1, true)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCWatchReceivesNewVersions(t *testing.T) {
+	client := setupGRPCClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &configpb.WatchRequest{Schema: "payment_config", Name: "payments"})
+	assert.NoError(t, err)
+
+	// Wait until the subscription is registered before writing
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = client.CreateConfig(ctx, &configpb.CreateConfigRequest{
+		Schema: "payment_config",
+		Name:   "payments",
+		Data:   paymentData(t, 1000, true),
+	})
+	assert.NoError(t, err)
+
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", event.GetName())
+	assert.Equal(t, int32(1), event.GetVersion())
+}
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +31,11 @@ func (m *mockConfigRepository) GetLatest(ctx context.Context, tx *sql.Tx, record
 	return args.Get(0).(domain.ConfigRecord), nil
 }
 
+func (m *mockConfigRepository) GetActive(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord, at time.Time) (domain.ConfigRecord, error) {
+	args := m.Called(ctx, tx, record, at)
+	return args.Get(0).(domain.ConfigRecord), nil
+}
+
 func (m *mockConfigRepository) CreateNewVersion(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) domain.ConfigRecord {
 	args := m.Called(ctx, tx, record)
 	return args.Get(0).(domain.ConfigRecord)
@@ -45,6 +51,53 @@ func (m *mockConfigRepository) ListVersions(ctx context.Context, tx *sql.Tx, rec
 	return args.Get(0).([]domain.ConfigRecord)
 }
 
+func (m *mockConfigRepository) FindAll(ctx context.Context, tx *sql.Tx) []domain.ConfigRecord {
+	args := m.Called(ctx, tx)
+	return args.Get(0).([]domain.ConfigRecord)
+}
+
+func (m *mockConfigRepository) DeleteVersion(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) {
+	m.Called(ctx, tx, record)
+}
+
+func (m *mockConfigRepository) ReplaceData(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) {
+	m.Called(ctx, tx, record)
+}
+
+func (m *mockConfigRepository) CancelVersion(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) {
+	m.Called(ctx, tx, record)
+}
+
+func (m *mockConfigRepository) ListActivations(ctx context.Context, tx *sql.Tx, after, until time.Time) []domain.ConfigRecord {
+	args := m.Called(ctx, tx, after, until)
+	return args.Get(0).([]domain.ConfigRecord)
+}
+
+func (m *mockConfigRepository) Stats(ctx context.Context, tx *sql.Tx) domain.ConfigStats {
+	args := m.Called(ctx, tx)
+	return args.Get(0).(domain.ConfigStats)
+}
+
+func (m *mockConfigRepository) CountConfigs(ctx context.Context, tx *sql.Tx, tenant string) int {
+	args := m.Called(ctx, tx, tenant)
+	return args.Int(0)
+}
+
+func (m *mockConfigRepository) LatestPerEnvironment(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) []domain.ConfigRecord {
+	args := m.Called(ctx, tx, record)
+	return args.Get(0).([]domain.ConfigRecord)
+}
+
+func (m *mockConfigRepository) CountPerEnvironment(ctx context.Context, tx *sql.Tx, tenant string) map[string]int {
+	args := m.Called(ctx, tx, tenant)
+	return args.Get(0).(map[string]int)
+}
+
+func (m *mockConfigRepository) ListLatest(ctx context.Context, tx *sql.Tx, record domain.ConfigRecord) []domain.ConfigRecord {
+	args := m.Called(ctx, tx, record)
+	return args.Get(0).([]domain.ConfigRecord)
+}
+
 func TestCreateConfig(t *testing.T) {
 
 	db, sqlmock := fakeDB(t)
@@ -99,6 +152,8 @@ func TestUpdateConfig(t *testing.T) {
 		Data:    map[string]interface{}{"max_limit": 500, "enabled": true},
 	})
 
+	repo.On("ListLatest", mock.Anything, mock.Anything, mock.Anything).Return([]domain.ConfigRecord{})
+
 	resp := svc.UpdateConfig(context.Background(), "payment_config", "payment", req)
 	assert.Equal(t, 2, resp.Version)
 	repo.AssertExpectations(t)
@@ -117,7 +172,7 @@ func TestFetchConfigLatest(t *testing.T) {
 	sqlmock.ExpectBegin()
 	sqlmock.ExpectCommit()
 
-	repo.On("GetLatest", mock.Anything, mock.Anything, mock.Anything).Return(domain.ConfigRecord{
+	repo.On("GetActive", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.ConfigRecord{
 		Schema:  "payment_config",
 		Name:    "payment",
 		Version: 5,
@@ -182,6 +237,8 @@ func TestRollbackConfig(t *testing.T) {
 		Version: 6,
 	})
 
+	repo.On("ListLatest", mock.Anything, mock.Anything, mock.Anything).Return([]domain.ConfigRecord{})
+
 	resp := svc.RollbackConfig(context.Background(), "payment_config", "payment", req)
 	assert.Equal(t, 6, resp.Version)
 	repo.AssertExpectations(t)
@@ -0,0 +1,317 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeDraft(t *testing.T, body []byte) web.DraftResponse {
+	var draft web.DraftResponse
+	assert.NoError(t, json.Unmarshal(body, &draft))
+	return draft
+}
+
+func TestDraftsAreNotServedUntilApproved(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	writer := createTestAPIKey(t, "writer", false)
+	approver := createTestAPIKey(t, "approver", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "payment_config", "*")
+	bindTestRole(t, "apikey:approver", auth.RoleApprover, "payment_config", "*")
+	path := "/configs/payment_config/checkout"
+
+	rec := performAuthRequest(router, http.MethodPost, path, writer.Key, strings.NewReader(`{"max_limit":100,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, path+"/drafts", writer.Key, strings.NewReader(`{"data":{"max_limit":"lots","enabled":true}}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, path+"/drafts", writer.Key, strings.NewReader(`{"data":{"max_limit":500,"enabled":true},"comment":"raise the limit"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	draft := decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "pending", draft.Status)
+	assert.Equal(t, 1, draft.BaseVersion)
+	assert.Equal(t, "apikey:writer", draft.CreatedBy)
+	assert.Equal(t, 1, draft.RequiredApprovals)
+
+	rec = performAuthRequest(router, http.MethodGet, path, writer.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(100), decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performAuthRequest(router, http.MethodGet, "/drafts", approver.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var drafts []web.DraftResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &drafts))
+	if assert.Len(t, drafts, 1) {
+		assert.Equal(t, draft.ID, drafts[0].ID)
+	}
+
+	rec = performAuthRequest(router, http.MethodGet, fmt.Sprintf("/drafts/%d", draft.ID), approver.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if changes := decodeDraft(t, rec.Body.Bytes()).Changes; assert.Len(t, changes, 1) {
+		assert.Equal(t, "max_limit", changes[0].Path)
+	}
+
+	// Authors and writers can't approve
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), writer.Key, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approver.Key, strings.NewReader(`{"comment":"looks good"}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	draft = decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "published", draft.Status)
+	assert.Equal(t, 2, draft.PublishedVersion)
+
+	rec = performAuthRequest(router, http.MethodGet, path, writer.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Equal(t, 2, config.Version)
+	assert.Equal(t, float64(500), config.Data["max_limit"])
+	assert.Equal(t, "apikey:writer", config.CreatedBy)
+	assert.Equal(t, map[string]interface{}{"id": float64(draft.ID), "approved_by": []interface{}{"apikey:approver"}}, config.Metadata["draft"])
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approver.Key, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/drafts", approver.Key, nil)
+	assert.Equal(t, "[]", strings.TrimSpace(rec.Body.String()))
+}
+
+func TestRequiredApprovalsBlockDirectWrites(t *testing.T) {
+	truncateConfigs(db)
+	settings := testSettings()
+	settings.AuthRequired = true
+	settings.RequiredApprovals = map[string]int{"payment_config": 2}
+	router := setupRouterWithSettings(db, settings)
+	writer := createTestAPIKey(t, "writer", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "payment_config", "*")
+	var approvers []string
+	for _, name := range []string{"alice", "bob"} {
+		approvers = append(approvers, createTestAPIKey(t, name, false).Key)
+		bindTestRole(t, "apikey:"+name, auth.RoleApprover, "payment_config", "*")
+	}
+	path := "/configs/payment_config/checkout"
+
+	rec := performAuthRequest(router, http.MethodPost, path, writer.Key, strings.NewReader(`{"max_limit":100,"enabled":true}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "require 2 approval(s), propose a draft instead")
+
+	rec = performAuthRequest(router, http.MethodPost, path+"/drafts", writer.Key, strings.NewReader(`{"data":{"max_limit":100,"enabled":true}}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	draft := decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, 0, draft.BaseVersion)
+	assert.Equal(t, 2, draft.RequiredApprovals)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[0], nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	draft = decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "pending", draft.Status)
+	assert.Equal(t, 1, draft.Approvals)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[0], nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, path, writer.Key, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[1], nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, decodeDraft(t, rec.Body.Bytes()).PublishedVersion)
+
+	rec = performAuthRequest(router, http.MethodGet, path, writer.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+}
+
+func TestRequiredApprovalsAreTenantScoped(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	settings := testSettings()
+	settings.RequiredApprovals = map[string]int{"acme/payment_config": 2}
+	router := setupRouterWithSettings(db, settings)
+	path := "/configs/payment_config/checkout"
+
+	// Only the payment_config schema of acme needs approvals
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/drafts", "", "", `{"data":{"max_limit":200,"enabled":true}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, decodeDraft(t, rec.Body.Bytes()).RequiredApprovals)
+
+	rec = performTenantRequest(router, http.MethodPost, path, "acme", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "require 2 approval(s), propose a draft instead")
+	rec = performTenantRequest(router, http.MethodPost, path+"/drafts", "acme", "", `{"data":{"max_limit":100,"enabled":true}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 2, decodeDraft(t, rec.Body.Bytes()).RequiredApprovals)
+}
+
+func TestDraftsSupersededWhenTheirConfigChanges(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	writer := createTestAPIKey(t, "writer", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "payment_config", "*")
+	var approvers []string
+	for _, name := range []string{"alice", "bob", "carol"} {
+		approvers = append(approvers, createTestAPIKey(t, name, false).Key)
+		bindTestRole(t, "apikey:"+name, auth.RoleApprover, "payment_config", "*")
+	}
+	path := "/configs/payment_config/checkout"
+
+	rec := performAuthRequest(router, http.MethodPost, path, writer.Key, strings.NewReader(`{"max_limit":100,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performAuthRequest(router, http.MethodPost, path+"/drafts", writer.Key, strings.NewReader(`{"data":{"max_limit":200,"enabled":true}}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	draft := decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, 1, draft.BaseVersion)
+
+	// The config changes between the proposal and the approval
+	rec = performAuthRequest(router, http.MethodPut, path, writer.Key, strings.NewReader(`{"max_limit":150,"enabled":true}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[0], nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "the draft is superseded, propose it again")
+
+	// The approval is kept and the draft closed, later approvals don't
+	// retry the publication
+	rec = performAuthRequest(router, http.MethodGet, fmt.Sprintf("/drafts/%d", draft.ID), writer.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	draft = decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "superseded", draft.Status)
+	assert.Equal(t, 0, draft.PublishedVersion)
+	assert.Equal(t, 1, draft.Approvals)
+
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[1], nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "is superseded")
+
+	rec = performAuthRequest(router, http.MethodGet, "/drafts", writer.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), fmt.Sprintf(`"id":%d`, draft.ID))
+
+	// Proposing it again on the new version publishes it
+	rec = performAuthRequest(router, http.MethodPost, path+"/drafts", writer.Key, strings.NewReader(`{"data":{"max_limit":200,"enabled":true}}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	draft = decodeDraft(t, rec.Body.Bytes())
+	rec = performAuthRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), approvers[2], nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 3, decodeDraft(t, rec.Body.Bytes()).PublishedVersion)
+}
+
+func TestRejectedAndStaleDrafts(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/drafts", "", "", `{"data":{"max_limit":200,"enabled":true}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rejected := decodeDraft(t, rec.Body.Bytes())
+
+	rec = performTenantRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/reject", rejected.ID), "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "rejections need a comment", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/reject", rejected.ID), "", "", `{"comment":"too high"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rejected = decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "rejected", rejected.Status)
+	if assert.Len(t, rejected.Reviews, 1) {
+		assert.Equal(t, "too high", rejected.Reviews[0].Comment)
+	}
+
+	rec = performTenantRequest(router, http.MethodGet, "/drafts?status=rejected", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too high")
+
+	rec = performTenantRequest(router, http.MethodGet, "/drafts?status=merged", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Drafts of outdated versions are not published
+	rec = performTenantRequest(router, http.MethodPost, path+"/drafts", "", "", `{"data":{"max_limit":300,"enabled":true}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	stale := decodeDraft(t, rec.Body.Bytes())
+
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":150,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", stale.ID), "", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "changed since draft")
+
+	rec = performTenantRequest(router, http.MethodGet, fmt.Sprintf("/drafts/%d", stale.ID), "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	stale = decodeDraft(t, rec.Body.Bytes())
+	assert.Equal(t, "superseded", stale.Status)
+	assert.Len(t, stale.Reviews, 1)
+
+	rec = performTenantRequest(router, http.MethodGet, "/drafts?status=superseded", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_limit":300`)
+
+	rec = performTenantRequest(router, http.MethodGet, "/drafts/999999", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDraftSecretsAreMasked(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/database/orders/drafts", "", "", `{"data":{"host":"db.example.com","password":"hunter2"}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+	draft := decodeDraft(t, rec.Body.Bytes())
+
+	var stored string
+	assert.NoError(t, db.QueryRow(`SELECT data FROM drafts WHERE id = ?`, draft.ID).Scan(&stored))
+	assert.NotContains(t, stored, "hunter2")
+
+	rec = performTenantRequest(router, http.MethodPost, fmt.Sprintf("/drafts/%d/approve", draft.ID), "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/database/orders?reveal=true", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hunter2", decodeConfig(t, rec).Data["password"])
+}
+
+func TestDraftClient(t *testing.T) {
+	truncateConfigs(db)
+	ctx := context.Background()
+	server := setupClientServer(t, nil)
+	configClient := client.New(server.URL)
+
+	draft, err := configClient.ProposeConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 100, "enabled": true}, "first version")
+	assert.NoError(t, err)
+	assert.Equal(t, "first version", draft.Comment)
+
+	drafts, err := configClient.ListDrafts(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, drafts, 1)
+
+	draft, err = configClient.ApproveDraft(ctx, draft.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, draft.PublishedVersion)
+
+	config, err := configClient.FetchConfig(ctx, "payment_config", "checkout", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(100), config.Data["max_limit"])
+
+	_, err = configClient.RejectDraft(ctx, draft.ID, "too late")
+	var apiErr *client.APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	}
+}
@@ -0,0 +1,189 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/environment"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestEnvironmentsKeepSeparateHistories(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/payments"
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "staging", decodeConfig(t, rec).Environment)
+
+	rec = performTenantRequest(router, http.MethodPut, path+"?env=staging", "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, decodeConfig(t, rec).Version)
+
+	// The same config starts over in another environment
+	rec = performTenantRequest(router, http.MethodPut, path+"?env=prod", "", "", `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"?env=prod", "", "", `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=staging", "", "", "")
+	assert.Equal(t, float64(200), decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=prod&version=1", "", "", "")
+	assert.Equal(t, float64(1000), decodeConfig(t, rec).Data["max_limit"])
+
+	// The header selects the environment as well
+	req := httptest.NewRequest(http.MethodGet, path+"/versions", nil)
+	req.Header.Set(environment.Header, "staging")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var versions web.ConfigResponses
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &versions))
+	assert.Equal(t, "staging", versions.Environment)
+	assert.Len(t, versions.ConfigVersions, 2)
+
+	// Nothing was written to the default environment
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// ETags differ between environments
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=staging&version=1", "", "", "")
+	staging := rec.Header().Get("ETag")
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=prod&version=1", "", "", "")
+	assert.NotEqual(t, staging, rec.Header().Get("ETag"))
+}
+
+func TestUnknownEnvironmentIsRejected(t *testing.T) {
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments?env=qa", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown environment qa")
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?env=Prod!", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid environment name")
+
+	// Configured environments are accepted
+	settings := testSettings()
+	settings.Environments = []string{"qa"}
+	router = setupRouterWithSettings(db, settings)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?env=qa", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestListEnvironments(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	body := `{"max_limit":100,"enabled":true}`
+
+	for _, env := range []string{"dev", "staging", "prod"} {
+		rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments?env="+env, "", "", body)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+	rec := performTenantRequest(router, http.MethodPut, "/configs/payment_config/payments?env=prod", "", "", `{"max_limit":500,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/refunds?env=dev", "", "", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/environments", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[
+		{"name":"default","configs":0},
+		{"name":"dev","configs":2},
+		{"name":"staging","configs":1},
+		{"name":"prod","configs":1}
+	]`, rec.Body.String())
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments/environments", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var configs []web.ConfigResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &configs))
+	if assert.Len(t, configs, 3) {
+		assert.Equal(t, "dev", configs[0].Environment)
+		assert.Equal(t, "prod", configs[1].Environment)
+		assert.Equal(t, 2, configs[1].Version)
+		assert.Equal(t, float64(500), configs[1].Data["max_limit"])
+		assert.Equal(t, "staging", configs[2].Environment)
+	}
+
+	// Environments removed from the settings are listed while they hold configs
+	settings := testSettings()
+	settings.Environments = []string{"prod"}
+	rec = performTenantRequest(setupRouterWithSettings(db, settings), http.MethodGet, "/environments", "", "", "")
+	assert.JSONEq(t, `[
+		{"name":"default","configs":0},
+		{"name":"prod","configs":1},
+		{"name":"dev","configs":2},
+		{"name":"staging","configs":1}
+	]`, rec.Body.String())
+}
+
+func TestGRPCEnvironmentMetadata(t *testing.T) {
+	grpcClient := setupGRPCClient(t)
+
+	staging := metadata.AppendToOutgoingContext(context.Background(), "x-environment", "staging")
+	created, err := grpcClient.CreateConfig(staging, &configpb.CreateConfigRequest{Schema: "payment_config", Name: "payments", Data: paymentData(t, 1000, true)})
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", created.GetEnvironment())
+
+	_, err = grpcClient.FetchConfig(context.Background(), &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	unknown := metadata.AppendToOutgoingContext(context.Background(), "x-environment", "qa")
+	_, err = grpcClient.FetchConfig(unknown, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestClientWithEnvironment(t *testing.T) {
+	server := setupClientServer(t, nil)
+	ctx := context.Background()
+	prod := client.New(server.URL, client.WithEnvironment("prod"))
+	dev := client.New(server.URL, client.WithEnvironment("dev"))
+
+	_, err := prod.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 1000, Enabled: true})
+	assert.NoError(t, err)
+	_, err = dev.CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 5, Enabled: false})
+	assert.NoError(t, err)
+
+	fetched, err := client.Fetch[paymentConfig](ctx, prod, "payment_config", "payments", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", fetched.Environment)
+	assert.Equal(t, 1000, fetched.Data.MaxLimit)
+
+	fetched, err = client.Fetch[paymentConfig](ctx, dev, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, fetched.Data.MaxLimit)
+
+	environments, err := prod.ListConfigEnvironments(ctx, "payment_config", "payments")
+	assert.NoError(t, err)
+	assert.Len(t, environments, 2)
+}
+
+func TestSettingsEnvironments(t *testing.T) {
+	settings, err := loadTestSettings()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev", "staging", "prod"}, settings.Environments)
+
+	settings, err = loadTestSettings("-environments", "qa, prod")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"qa", "prod"}, settings.Environments)
+
+	_, err = loadTestSettings("-environments", "qa,Prod")
+	assert.ErrorContains(t, err, `invalid environment "Prod"`)
+
+	_, err = loadTestSettings("-environments", "qa,qa")
+	assert.ErrorContains(t, err, "listed twice")
+}
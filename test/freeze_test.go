@@ -0,0 +1,291 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/repository"
+	"config-service/service"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func createTestFreeze(t *testing.T, request web.FreezeCreateRequest) web.FreezeResponse {
+	freezeService := service.NewFreezeService(repository.NewFreezeRepository(), db, validator.New(), newTestAuditService())
+	return freezeService.CreateFreeze(context.Background(), request)
+}
+
+func deleteTestFreeze(t *testing.T, id int64) {
+	freezeService := service.NewFreezeService(repository.NewFreezeRepository(), db, validator.New(), newTestAuditService())
+	freezeService.DeleteFreeze(context.Background(), id)
+}
+
+func overrideQuery(reason string) string {
+	return "?override=" + url.QueryEscape(reason)
+}
+
+func TestFreezesLockChangesUnlessOverridden(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	freeze := createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", NamePattern: "check*", Reason: "Black Friday"})
+	assert.True(t, freeze.Active)
+	assert.Equal(t, "*", freeze.Environment)
+
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Contains(t, errorData(t, rec), "Black Friday")
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/rollback", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+
+	// Dry runs only preview the changes
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout?env=staging", "", "", `{"max_limit":400,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"default","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"default"}`)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+
+	// New configs and configs outside the freeze can still be changed
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/checkout-eu", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/refunds", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/payment_config/refunds", "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, path+overrideQuery("hotfix for checkout outage"), "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Equal(t, 3, config.Version)
+	assert.Equal(t, map[string]interface{}{
+		"reason":  "hotfix for checkout outage",
+		"by":      "",
+		"freezes": []interface{}{float64(freeze.ID)},
+	}, config.Metadata["freeze_override"])
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/rollback"+overrideQuery("revert the hotfix"), "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config = decodeConfig(t, rec)
+	assert.Equal(t, 4, config.Version)
+	assert.Equal(t, float64(200), config.Data["max_limit"])
+	assert.Contains(t, config.Metadata, "freeze_override")
+
+	events := newTestAuditService().ListEvents(context.Background(), domain.AuditFilter{Action: domain.AuditConfigRolledBack, Schema: "payment_config"})
+	if assert.NotEmpty(t, events) {
+		assert.Contains(t, events[0].Detail, "revert the hotfix")
+	}
+
+	deleteTestFreeze(t, freeze.ID)
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":500,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, decodeConfig(t, rec).Metadata, "freeze_override")
+}
+
+func TestFreezesLockCancellationsAndDraftPublications(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/drafts", "", "", `{"data":{"max_limit":300,"enabled":true}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var draft web.DraftResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &draft))
+
+	createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", Reason: "release"})
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel"+overrideQuery("wrong limit"), "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	approve := "/drafts/" + strconv.FormatInt(draft.ID, 10) + "/approve"
+	rec = performTenantRequest(router, http.MethodPost, approve, "", "", "")
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, approve+overrideQuery("approved exception"), "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, path+"?version=3", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Contains(t, config.Metadata, "draft")
+	assert.Contains(t, config.Metadata, "freeze_override")
+}
+
+func TestFreezeWindows(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	// Weekend nights, from Friday 18:00 to Monday 06:00 in Berlin
+	weekend := domain.FreezeWindow{Days: []string{"fri", "sat", "sun"}, Start: "18:00", End: "06:00", Timezone: "Europe/Berlin"}
+	for at, contained := range map[time.Time]bool{
+		time.Date(2024, 6, 7, 17, 59, 0, 0, berlin):   false, // Friday
+		time.Date(2024, 6, 7, 18, 0, 0, 0, berlin):    true,
+		time.Date(2024, 6, 8, 3, 0, 0, 0, berlin):     true, // Saturday
+		time.Date(2024, 6, 8, 12, 0, 0, 0, berlin):    false,
+		time.Date(2024, 6, 10, 5, 59, 0, 0, berlin):   true, // Monday
+		time.Date(2024, 6, 10, 6, 0, 0, 0, berlin):    false,
+		time.Date(2024, 6, 7, 16, 30, 0, 0, time.UTC): true, // 18:30 in Berlin
+	} {
+		assert.Equal(t, contained, weekend.Contains(at), at.String())
+	}
+
+	// Office hours on every day, in UTC
+	daily := domain.FreezeWindow{Start: "09:00", End: "17:00"}
+	assert.True(t, daily.Contains(time.Date(2024, 6, 9, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, daily.Contains(time.Date(2024, 6, 9, 17, 0, 0, 0, time.UTC)))
+
+	// Windows ending when they start last all day long
+	allDay := domain.FreezeWindow{Days: []string{"sun"}, Start: "00:00", End: "00:00"}
+	assert.True(t, allDay.Contains(time.Date(2024, 6, 9, 23, 59, 0, 0, time.UTC)))
+	assert.False(t, allDay.Contains(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, domain.Freeze{}.ActiveAt(time.Now()))
+	assert.False(t, domain.Freeze{Window: &domain.FreezeWindow{Start: "25:00", End: "06:00"}}.ActiveAt(time.Now()))
+}
+
+func TestFreezeMatchesEveryPattern(t *testing.T) {
+	// Equal patterns are still checked against their own values
+	freeze := domain.Freeze{Tenant: "*", Environment: "prod", Schema: "payment_config", NamePattern: "prod"}
+	assert.True(t, freeze.Matches("acme", "prod", "payment_config", "prod"))
+	assert.False(t, freeze.Matches("acme", "prod", "payment_config", "checkout"))
+	assert.False(t, freeze.Matches("acme", "staging", "payment_config", "prod"))
+
+	freeze = domain.Freeze{Tenant: "payment_config", Environment: "*", Schema: "payment_config", NamePattern: "*"}
+	assert.True(t, freeze.Matches("payment_config", "prod", "payment_config", "checkout"))
+	assert.False(t, freeze.Matches("acme", "prod", "payment_config", "checkout"))
+}
+
+func TestFreezeAdministration(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	admin := createTestAPIKey(t, "admin", true)
+	writer := createTestAPIKey(t, "writer", false)
+	approver := createTestAPIKey(t, "approver", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "payment_config", "*")
+	bindTestRole(t, "apikey:approver", auth.RoleApprover, "payment_config", "*")
+	path := "/configs/payment_config/checkout?env=prod"
+
+	rec := performAuthRequest(router, http.MethodPost, path, writer.Key, strings.NewReader(`{"max_limit":100,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", writer.Key, strings.NewReader(`{"schema":"payment_config","reason":"release"}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", admin.Key, strings.NewReader(`{"schema":"payment_config","reason":"nightly","window":{"start":"24:00","end":"06:00"}}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", admin.Key, strings.NewReader(`{"schema":"payment_config","reason":"nightly","window":{"start":"22:00","end":"06:00","timezone":"Mars/Olympus"}}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", admin.Key, strings.NewReader(`{"schema":"payment_config","reason":"nightly","window":{"days":["someday"],"start":"22:00","end":"06:00"}}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// A window that never contains the present and a lock of prod
+	now := time.Now().UTC()
+	window := `{"days":["` + domain.Weekdays[now.AddDate(0, 0, 3).Weekday()] + `"],"start":"00:00","end":"00:01"}`
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", admin.Key, strings.NewReader(`{"schema":"payment_config","reason":"maintenance","window":`+window+`}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performAuthRequest(router, http.MethodPost, "/admin/freezes", admin.Key, strings.NewReader(`{"environment":"prod","schema":"payment_*","reason":"release 42"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var lock web.FreezeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &lock))
+	assert.Equal(t, "apikey:admin", lock.CreatedBy)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/freezes", admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var freezes []web.FreezeResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &freezes))
+	if assert.Len(t, freezes, 2) {
+		assert.False(t, freezes[0].Active)
+		assert.NotNil(t, freezes[0].Window)
+		assert.True(t, freezes[1].Active)
+		assert.Nil(t, freezes[1].Window)
+	}
+
+	rec = performAuthRequest(router, http.MethodPut, path, writer.Key, strings.NewReader(`{"max_limit":200,"enabled":true}`))
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	assert.Contains(t, errorData(t, rec), "release 42")
+
+	// Overriding a freeze takes the approver role
+	rec = performAuthRequest(router, http.MethodPut, path+"&override=urgent", writer.Key, strings.NewReader(`{"max_limit":200,"enabled":true}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = performAuthRequest(router, http.MethodPut, path+"&override=urgent", approver.Key, strings.NewReader(`{"max_limit":200,"enabled":true}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "apikey:approver", decodeConfig(t, rec).Metadata["freeze_override"].(map[string]interface{})["by"])
+
+	// Other environments are not locked
+	rec = performAuthRequest(router, http.MethodPost, "/configs/payment_config/checkout?env=staging", writer.Key, strings.NewReader(`{"max_limit":100,"enabled":true}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performAuthRequest(router, http.MethodPut, "/configs/payment_config/checkout?env=staging", writer.Key, strings.NewReader(`{"max_limit":200,"enabled":true}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/freezes/"+strconv.FormatInt(lock.ID, 10), admin.Key, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/freezes/"+strconv.FormatInt(lock.ID, 10), admin.Key, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPut, path, writer.Key, strings.NewReader(`{"max_limit":300,"enabled":true}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	events := newTestAuditService().ListEvents(context.Background(), domain.AuditFilter{Action: domain.AuditFreezeCreated})
+	if assert.NotEmpty(t, events) {
+		assert.Equal(t, "apikey:admin", events[0].Actor)
+		assert.Contains(t, events[0].Detail, "release 42")
+	}
+}
+
+func TestFreezeClients(t *testing.T) {
+	truncateConfigs(db)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	server := setupClientServer(t, nil)
+	configClient := client.New(server.URL)
+
+	_, err := configClient.CreateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 100, "enabled": true})
+	assert.NoError(t, err)
+	createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", Reason: "release"})
+
+	_, err = configClient.UpdateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 200, "enabled": true})
+	assert.True(t, client.IsLocked(err))
+
+	overriding := client.New(server.URL, client.WithFreezeOverride("urgent fix"))
+	config, err := overriding.UpdateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 200, "enabled": true})
+	assert.NoError(t, err)
+	assert.Contains(t, config.Metadata, "freeze_override")
+
+	data, err := structpb.NewStruct(map[string]interface{}{"max_limit": 300, "enabled": true})
+	assert.NoError(t, err)
+	_, err = grpcClient.UpdateConfig(ctx, &configpb.UpdateConfigRequest{Schema: "payment_config", Name: "checkout", Data: data})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	updated, err := grpcClient.UpdateConfig(ctx, &configpb.UpdateConfigRequest{Schema: "payment_config", Name: "checkout", Data: data, Override: "urgent fix"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), updated.GetVersion())
+
+	_, err = grpcClient.RollbackConfig(ctx, &configpb.RollbackConfigRequest{Schema: "payment_config", Name: "checkout", Version: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
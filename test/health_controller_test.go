@@ -0,0 +1,67 @@
+package test
+
+import (
+	"config-service/app"
+	"config-service/controller"
+	"config-service/repository"
+	"config-service/service"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthzSuccess(t *testing.T) {
+	response, res := performRequest(http.MethodGet, "/healthz", nil, false)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "UP", response["status"])
+}
+
+func TestReadyzSuccess(t *testing.T) {
+	response, res := performRequest(http.MethodGet, "/readyz", nil, false)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "UP", response["status"])
+	assert.Len(t, response["checks"], 3)
+}
+
+func TestStatusReportsConfigsAndSchemas(t *testing.T) {
+	requestBody := strings.NewReader(`{"max_limit":1000,"enabled":true}`)
+	_, createHTTP := performRequest(http.MethodPost, "/configs/payment_config/payments", requestBody, true)
+	assert.Equal(t, http.StatusCreated, createHTTP.StatusCode)
+
+	response, res := performRequest(http.MethodGet, "/status", nil, false)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.Equal(t, float64(1), response["schema_count"])
+
+	database := response["database"].(map[string]interface{})
+	assert.Equal(t, float64(1), database["configs"])
+	assert.Equal(t, float64(0), database["pending_migrations"])
+
+	build := response["build"].(map[string]interface{})
+	assert.NotEmpty(t, build["go_version"])
+}
+
+func TestReadyzFailsWithoutDatabase(t *testing.T) {
+	closedDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "closed.db"))
+	assert.NoError(t, err)
+	closedDB.Close()
+
+	healthService := service.NewHealthService(repository.NewConfigRepository(), closedDB, app.MigrationStatus(closedDB))
+	configController := controller.NewConfigController(nil)
+	router := app.NewRouter(testSettings(), &app.Authenticator{}, configController, controller.NewSchemaController(), controller.NewHealthController(healthService), controller.NewAPIKeyController(nil), controller.NewRoleBindingController(nil), controller.NewFreezeController(nil), controller.NewAuditController(nil), controller.NewVariableController(nil), controller.NewDraftController(nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
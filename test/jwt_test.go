@@ -0,0 +1,191 @@
+package test
+
+import (
+	"config-service/app"
+	"config-service/auth"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "config-service"
+)
+
+// testIssuerKeys is a local identity provider serving its keys as a JWKS.
+type testIssuerKeys struct {
+	t    *testing.T
+	mu   sync.Mutex
+	keys map[string]*rsa.PrivateKey
+}
+
+func newTestIssuerKeys(t *testing.T, kids ...string) *testIssuerKeys {
+	issuer := &testIssuerKeys{t: t, keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		issuer.rotate(kid)
+	}
+	return issuer
+}
+
+func (issuer *testIssuerKeys) rotate(kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(issuer.t, err)
+
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	issuer.keys[kid] = key
+}
+
+func (issuer *testIssuerKeys) jwks() []byte {
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+
+	keys := []map[string]string{}
+	for kid, key := range issuer.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	content, err := json.Marshal(map[string]interface{}{"keys": keys})
+	assert.NoError(issuer.t, err)
+	return content
+}
+
+func (issuer *testIssuerKeys) server() *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(issuer.jwks())
+	}))
+	issuer.t.Cleanup(server.Close)
+	return server
+}
+
+func (issuer *testIssuerKeys) token(kid string, claims jwt.MapClaims) string {
+	base := jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for key, value := range claims {
+		base[key] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, base)
+	token.Header["kid"] = kid
+
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	signed, err := token.SignedString(issuer.keys[kid])
+	assert.NoError(issuer.t, err)
+	return signed
+}
+
+func jwtSettings(jwks string) app.Settings {
+	settings := testSettings()
+	settings.AuthRequired = true
+	settings.JWTJWKS = jwks
+	settings.JWTIssuer = testIssuer
+	settings.JWTAudience = testAudience
+	settings.JWTRoleClaim = "groups"
+	settings.JWTRoleMapping = map[string]string{"payments-team": auth.RoleWriter, "platform": auth.RoleAdmin}
+	return settings
+}
+
+func performBearerRequest(router http.Handler, method, path, token string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestJWTBearerAuthentication(t *testing.T) {
+	truncateConfigs(db)
+	issuer := newTestIssuerKeys(t, "key-1")
+	router := setupRouterWithSettings(db, jwtSettings(issuer.server().URL))
+
+	token := issuer.token("key-1", jwt.MapClaims{"groups": []string{"payments-team", "unrelated"}})
+	rec := performBearerRequest(router, http.MethodPost, "/configs/payment_config/payments", token, `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var created map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "jwt:alice", created["created_by"])
+
+	// Writers are not admins
+	rec = performBearerRequest(router, http.MethodGet, "/admin/api-keys", token, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	admin := issuer.token("key-1", jwt.MapClaims{"groups": "platform"})
+	rec = performBearerRequest(router, http.MethodGet, "/admin/api-keys", admin, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestJWTRejectedTokens(t *testing.T) {
+	issuer := newTestIssuerKeys(t, "key-1")
+	router := setupRouterWithSettings(db, jwtSettings(issuer.server().URL))
+	groups := []string{"payments-team"}
+
+	unauthorized := map[string]string{
+		"expired":        issuer.token("key-1", jwt.MapClaims{"groups": groups, "exp": time.Now().Add(-time.Hour).Unix()}),
+		"wrong audience": issuer.token("key-1", jwt.MapClaims{"groups": groups, "aud": "other-service"}),
+		"wrong issuer":   issuer.token("key-1", jwt.MapClaims{"groups": groups, "iss": "https://evil.example.com"}),
+		"malformed":      "not-a-token",
+	}
+	for name, token := range unauthorized {
+		rec := performBearerRequest(router, http.MethodGet, "/schemas/", token, "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+		assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer", name)
+		assert.Contains(t, rec.Body.String(), "invalid bearer token", name)
+	}
+
+	// Tokens signed by another key with the same kid are rejected
+	other := newTestIssuerKeys(t, "key-1")
+	rec := performBearerRequest(router, http.MethodGet, "/schemas/", other.token("key-1", jwt.MapClaims{"groups": groups}), "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Valid tokens without a mapped role are forbidden
+	rec = performBearerRequest(router, http.MethodGet, "/schemas/", issuer.token("key-1", jwt.MapClaims{"groups": []string{"marketing"}}), "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "token grants no roles")
+}
+
+func TestJWKSFromFile(t *testing.T) {
+	issuer := newTestIssuerKeys(t, "file-key")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(file, issuer.jwks(), 0o644))
+
+	router := setupRouterWithSettings(db, jwtSettings(file))
+
+	rec := performBearerRequest(router, http.MethodGet, "/schemas/", issuer.token("file-key", jwt.MapClaims{"groups": "platform"}), "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestJWKSUnavailableAtStartup(t *testing.T) {
+	_, err := app.NewAuthenticator(context.Background(), jwtSettings("http://127.0.0.1:1/jwks.json"), nil, nil)
+	assert.ErrorContains(t, err, "load jwks")
+}
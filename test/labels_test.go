@@ -0,0 +1,189 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeLabel(t *testing.T, rec *httptest.ResponseRecorder) web.ConfigLabelResponse {
+	var label web.ConfigLabelResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &label))
+	return label
+}
+
+func TestLabelsNameVersions(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	name := auditName()
+	path := "/configs/payment_config/" + name
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/stable", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	label := decodeLabel(t, rec)
+	assert.Equal(t, "stable", label.Label)
+	assert.Equal(t, 1, label.Version)
+	assert.Equal(t, "default", label.Environment)
+
+	// Setting a label again moves it
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/stable", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/canary", "", "", `{"version":3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, path+"/labels", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var labels []web.ConfigLabelResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &labels))
+	if assert.Len(t, labels, 2) {
+		assert.Equal(t, "canary", labels[0].Label)
+		assert.Equal(t, 3, labels[0].Version)
+		assert.Equal(t, "stable", labels[1].Label)
+		assert.Equal(t, 2, labels[1].Version)
+	}
+
+	// Labels belong to the environment they were set in
+	rec = performTenantRequest(router, http.MethodGet, path+"/labels?env=staging", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+
+	events := auditEvents(t, domain.AuditFilter{Action: domain.AuditLabelSet, Name: name})
+	if assert.Len(t, events, 3) {
+		assert.Equal(t, "label canary set to version 3", events[0].Detail)
+		assert.Equal(t, "label stable moved from version 1 to version 2", events[1].Detail)
+	}
+
+	rec = performTenantRequest(router, http.MethodDelete, path+"/labels/canary", "", "", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = performTenantRequest(router, http.MethodDelete, path+"/labels/canary", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs?select=payment_config/"+name+"@canary", "", "", "")
+	bulk := decodeBulk(t, rec.Body.Bytes())
+	if assert.Len(t, bulk.Results, 1) {
+		assert.Equal(t, http.StatusNotFound, bulk.Results[0].Code)
+		assert.Contains(t, bulk.Results[0].Error, "label canary of payment_config/"+name+" is not found")
+	}
+
+	events = auditEvents(t, domain.AuditFilter{Action: domain.AuditLabelDeleted, Name: name})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "label canary deleted from version 3", events[0].Detail)
+	}
+}
+
+func TestLabelsValidation(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Labels can't be mistaken for version numbers
+	for _, label := range []string{"2", "Stable", "-beta"} {
+		rec = performTenantRequest(router, http.MethodPut, path+"/labels/"+label, "", "", `{"version":1}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, label)
+	}
+
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/stable", "", "", `{"version":5}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/stable", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/payment_config/missing/labels/stable", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestLabelsLockedByFreezes(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	name := auditName()
+	path := "/configs/payment_config/" + name
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/stable", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	freeze := createTestFreeze(t, web.FreezeCreateRequest{Schema: "payment_config", Reason: "release"})
+	defer deleteTestFreeze(t, freeze.ID)
+
+	// Moving a label changes what fetches selecting it are served
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/canary", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusLocked, rec.Code)
+	rec = performTenantRequest(router, http.MethodDelete, path+"/labels/stable", "", "", "")
+	assert.Equal(t, http.StatusLocked, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodDelete, path+"/labels/stable"+overrideQuery("retire stable"), "", "", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	events := auditEvents(t, domain.AuditFilter{Action: domain.AuditLabelDeleted, Name: name})
+	if assert.Len(t, events, 1) {
+		assert.Contains(t, events[0].Detail, "retire stable")
+	}
+}
+
+func TestAdminCompactKeepsLabeledVersions(t *testing.T) {
+	createPaymentVersions(t, 100, 200, 300, 400)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPut, "/configs/payment_config/payments/labels/stable", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	result := setupAdminService().CompactConfigs(context.Background(), 1, "")
+	assert.Equal(t, 2, result.Deleted)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?version=1", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?version=2", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs?select=payment_config/payments@stable", "", "", "")
+	bulk := decodeBulk(t, rec.Body.Bytes())
+	if assert.Len(t, bulk.Results, 1) && assert.NotNil(t, bulk.Results[0].Config) {
+		assert.Equal(t, 1, bulk.Results[0].Config.Version)
+	}
+}
+
+func TestClientLabels(t *testing.T) {
+	server := setupClientServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	_, err := c.CreateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 100, "enabled": true})
+	assert.NoError(t, err)
+	_, err = c.UpdateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 200, "enabled": true})
+	assert.NoError(t, err)
+
+	label, err := c.SetLabel(ctx, "payment_config", "checkout", "stable", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, label.Version)
+
+	labels, err := c.ListLabels(ctx, "payment_config", "checkout")
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+
+	bulk, err := c.BulkFetch(ctx, "payment_config/checkout@stable")
+	assert.NoError(t, err)
+	if assert.Len(t, bulk.Results, 1) {
+		assert.Equal(t, 1, bulk.Results[0].Config.Version)
+	}
+
+	assert.NoError(t, c.DeleteLabel(ctx, "payment_config", "checkout", "stable"))
+	assert.True(t, client.IsNotFound(c.DeleteLabel(ctx, "payment_config", "checkout", "stable")))
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withServiceSchema registers a schema with nested objects, whose debug
+// flag requires a log level.
+func withServiceSchema(t *testing.T) {
+	domain.Schemas[tenant.Default]["service_config"] = `{
+		"type": "object",
+		"properties": {
+			"timeouts": {
+				"type": "object",
+				"properties": {"connect": {"type": "integer"}, "read": {"type": "integer"}},
+				"required": ["connect", "read"],
+				"additionalProperties": false
+			},
+			"hosts": {"type": "array", "items": {"type": "string"}},
+			"debug": {"type": "boolean"},
+			"log_level": {"type": "string"}
+		},
+		"required": ["timeouts", "hosts"],
+		"dependencies": {"debug": ["log_level"]},
+		"additionalProperties": false
+	}`
+	t.Cleanup(func() { delete(domain.Schemas[tenant.Default], "service_config") })
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	content, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+// errorData returns the message of an error response.
+func errorData(t *testing.T, rec *httptest.ResponseRecorder) string {
+	var response web.WebResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	message, _ := response.Data.(string)
+	return message
+}
+
+func TestLayeredConfigIsMerged(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/service_config/base", "", "", `{"timeouts":{"connect":5,"read":10},"hosts":["a","b"],"log_level":"info"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// Only the overrides are stored, null removes an inherited key
+	rec = performTenantRequest(router, http.MethodPost, "/configs/service_config/eu", "", "", `{"$parent":"base","timeouts":{"read":30},"hosts":["eu"],"log_level":null}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "base", decodeConfig(t, rec).Data["$parent"])
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/service_config/eu", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"timeouts":{"connect":5,"read":30},"hosts":["eu"]}`, mustJSON(t, decodeConfig(t, rec).Data))
+	etag := rec.Header().Get("ETag")
+
+	// Grandchildren inherit from the whole chain
+	rec = performTenantRequest(router, http.MethodPost, "/configs/service_config/eu-west", "", "", `{"$parent":"eu","debug":true,"log_level":"debug"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/service_config/eu-west?layers=true", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.JSONEq(t, `{"timeouts":{"connect":5,"read":30},"hosts":["eu"],"debug":true,"log_level":"debug"}`, mustJSON(t, config.Data))
+	if assert.Len(t, config.Layers, 3) {
+		assert.Equal(t, "eu-west", config.Layers[0].Name)
+		assert.Equal(t, "eu", config.Layers[1].Name)
+		assert.Equal(t, "base", config.Layers[2].Name)
+		assert.Equal(t, "eu", config.Layers[0].Data["$parent"])
+	}
+
+	// Changes of the parent show through, and change the ETag
+	rec = performTenantRequest(router, http.MethodPut, "/configs/service_config/base", "", "", `{"timeouts":{"connect":2,"read":10},"hosts":["a"],"log_level":"info"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/service_config/eu", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+	assert.JSONEq(t, `{"timeouts":{"connect":2,"read":30},"hosts":["eu"]}`, mustJSON(t, decodeConfig(t, rec).Data))
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/service_config/eu?layers=maybe", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestLayeredConfigValidation(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/service_config/base", "", "", `{"timeouts":{"connect":5,"read":10},"hosts":["a"],"log_level":"info"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"merged result is validated", `{"$parent":"base","timeouts":{"read":"slow"}}`, "schema validation failed"},
+		{"removing a required key", `{"$parent":"base","hosts":null}`, "hosts is required"},
+		{"unknown parent", `{"$parent":"missing"}`, "parent config missing of child doesn't exist"},
+		{"parent must be a name", `{"$parent":1}`, "$parent must be the name of a config"},
+		{"config inherits from itself", `{"$parent":"child"}`, "config inheritance cycle: child -> child"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := performTenantRequest(router, http.MethodPost, "/configs/service_config/child", "", "", test.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, errorData(t, rec), test.err)
+		})
+	}
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/service_config/child", "", "", `{"$parent":"base","debug":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// Cycles through other configs are rejected
+	rec = performTenantRequest(router, http.MethodPut, "/configs/service_config/base", "", "", `{"$parent":"child"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorData(t, rec), "config inheritance cycle: base -> child -> base")
+
+	// Parents can't break the configs inheriting from them
+	rec = performTenantRequest(router, http.MethodPut, "/configs/service_config/base", "", "", `{"timeouts":{"connect":5,"read":10},"hosts":["a"]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorData(t, rec), "config child inherits from base")
+
+	// Parents are looked up in the same environment
+	rec = performTenantRequest(router, http.MethodPost, "/configs/service_config/child?env=prod", "", "", `{"$parent":"base","debug":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, errorData(t, rec), "parent config base of child doesn't exist")
+}
+
+func TestLayeredConfigWithBrokenChain(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	router := setupRouter(db)
+
+	// Written around the service, e.g. by an old release
+	_, err := db.Exec(`INSERT INTO configs (tenant, environment, schema, name, version, data, created_at) VALUES ('default', 'default', 'service_config', 'orphan', 1, '{"$parent":"gone"}', CURRENT_TIMESTAMP)`)
+	assert.NoError(t, err)
+
+	rec := performTenantRequest(router, http.MethodGet, "/configs/service_config/orphan", "", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "parent config gone of orphan doesn't exist")
+}
+
+func TestLayeredConfigClients(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	configClient := client.New(setupClientServer(t, nil).URL)
+
+	_, err := configClient.CreateConfig(ctx, "service_config", "base", map[string]interface{}{"timeouts": map[string]interface{}{"connect": 5, "read": 10}, "hosts": []string{"a"}})
+	assert.NoError(t, err)
+	_, err = configClient.CreateConfig(ctx, "service_config", "eu", map[string]interface{}{"$parent": "base", "hosts": []string{"eu"}})
+	assert.NoError(t, err)
+
+	config, err := configClient.FetchLayers(ctx, "service_config", "eu", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"eu"}, config.Data["hosts"])
+	assert.Len(t, config.Layers, 2)
+
+	fetched, err := grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "service_config", Name: "eu", Layers: true})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), fetched.GetData().AsMap()["timeouts"].(map[string]interface{})["connect"])
+	assert.Len(t, fetched.GetLayers(), 2)
+}
+
+func TestAdminRevalidatesMergedConfigs(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/service_config/base", "", "", `{"timeouts":{"connect":5,"read":10},"hosts":["a"]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/service_config/eu", "", "", `{"$parent":"base","hosts":["eu"]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	results := setupAdminService().RevalidateConfigs(context.Background(), false)
+	if assert.Len(t, results, 2) {
+		assert.True(t, results[0].Valid, results[0].Error)
+		assert.True(t, results[1].Valid, results[1].Error)
+	}
+
+	// Exports of inheriting configs can be imported again
+	exported := setupAdminService().ExportConfigs(context.Background())
+	truncateConfigs(db)
+	result := setupAdminService().ImportConfigs(context.Background(), exported)
+	assert.Equal(t, 2, result.Imported)
+}
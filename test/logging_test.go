@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"config-service/logging"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureLogs routes the default logger into a buffer for the test duration.
+func captureLogs(t *testing.T, level string) *bytes.Buffer {
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	var buf bytes.Buffer
+	assert.NoError(t, logging.Setup(&buf, level))
+	return &buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestIDIsPropagated(t *testing.T) {
+	router := setupRouter(db)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, "req-123", rec.Header().Get(logging.RequestIDHeader))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Len(t, rec.Header().Get(logging.RequestIDHeader), 32)
+}
+
+func TestLogsAreStructuredWithRequestID(t *testing.T) {
+	truncateConfigs(db)
+	buf := captureLogs(t, "debug")
+	router := setupRouter(db)
+
+	req := httptest.NewRequest(http.MethodPost, "/configs/payment_config/payments", strings.NewReader(`{"max_limit":1000,"enabled":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logging.RequestIDHeader, "req-create")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodPut, "/configs/payment_config/unknown", strings.NewReader(`{"max_limit":1000,"enabled":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(logging.RequestIDHeader, "req-missing")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var created, failed map[string]interface{}
+	for _, line := range logLines(t, buf) {
+		switch line["msg"] {
+		case "config created":
+			created = line
+		case "request failed":
+			failed = line
+		}
+	}
+
+	if assert.NotNil(t, created) {
+		assert.Equal(t, "req-create", created["request_id"])
+		assert.Equal(t, "payment_config", created["schema"])
+		assert.Equal(t, "payments", created["name"])
+		assert.Equal(t, float64(1), created["version"])
+	}
+
+	if assert.NotNil(t, failed) {
+		assert.Equal(t, "req-missing", failed["request_id"])
+		assert.Equal(t, "WARN", failed["level"])
+		assert.Equal(t, "requested config is not found", failed["error"])
+	}
+}
+
+func TestLogLevelFiltersRecords(t *testing.T) {
+	buf := captureLogs(t, "warn")
+
+	slog.Info("hidden")
+	slog.Warn("visible")
+
+	lines := logLines(t, buf)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, "visible", lines[0]["msg"])
+
+	assert.Error(t, logging.Setup(buf, "verbose"))
+}
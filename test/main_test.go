@@ -0,0 +1,15 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	db.Close()
+	os.RemoveAll(filepath.Dir(dbPath))
+	os.Exit(code)
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	router := setupRouter(db)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
+
+func TestMetricsEndpointExposesServiceMetrics(t *testing.T) {
+	requestBody := strings.NewReader(`{"max_limit":1000,"enabled":true}`)
+	_, createHTTP := performRequest(http.MethodPost, "/configs/payment_config/payments", requestBody, true)
+	assert.Equal(t, http.StatusCreated, createHTTP.StatusCode)
+
+	invalidBody := strings.NewReader(`{"max_limit":1000}`)
+	_, invalidHTTP := performRequest(http.MethodPut, "/configs/payment_config/payments", invalidBody, false)
+	assert.Equal(t, http.StatusBadRequest, invalidHTTP.StatusCode)
+
+	_, fetchHTTP := performRequest(http.MethodGet, "/configs/payment_config/payments", nil, false)
+	assert.Equal(t, http.StatusOK, fetchHTTP.StatusCode)
+
+	body := scrapeMetrics(t)
+
+	assert.Contains(t, body, `config_service_http_requests_total{method="POST",route="/configs/:schema/:name",status="201"}`)
+	assert.Contains(t, body, `config_service_http_requests_total{method="PUT",route="/configs/:schema/:name",status="400"}`)
+	assert.Contains(t, body, `config_service_http_request_duration_seconds_bucket{method="GET",route="/configs/:schema/:name",status="200"`)
+	assert.Contains(t, body, `config_service_repository_query_duration_seconds_count{method="CreateNewVersion"}`)
+	assert.Contains(t, body, `config_service_validation_failures_total{schema="payment_config"}`)
+	assert.Contains(t, body, `config_service_versions_created_total{schema="payment_config"}`)
+	assert.Contains(t, body, `config_service_cache_requests_total{result="miss"}`)
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/diff"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodePromotion(t *testing.T, rec *httptest.ResponseRecorder) web.ConfigPromoteResponse {
+	var result web.ConfigPromoteResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return result
+}
+
+func TestPromoteConfig(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	name := auditName()
+	path := "/configs/payment_config/" + name
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"?env=staging", "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The first promotion creates the config in the target
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","version":1,"to":"prod"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	result := decodePromotion(t, rec)
+	assert.False(t, result.DryRun)
+	assert.Nil(t, result.Previous)
+	assert.Equal(t, "prod", result.Config.Environment)
+	assert.Equal(t, 1, result.Config.Version)
+	assert.Equal(t, float64(100), result.Config.Data["max_limit"])
+
+	// Provenance is stored with the version
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=prod", "", "", "")
+	assert.Equal(t, map[string]interface{}{
+		"promoted_from": map[string]interface{}{"environment": "staging", "version": float64(1)},
+	}, decodeConfig(t, rec).Metadata)
+
+	// Promoting the latest version adds a version to the target
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"prod"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	result = decodePromotion(t, rec)
+	assert.Equal(t, 2, result.Config.Version)
+	assert.Equal(t, 1, result.Previous.Version)
+	assert.Equal(t, []diff.Change{{Path: "max_limit", Op: "change", Old: float64(100), New: float64(200)}}, result.Changes)
+
+	events := auditEvents(t, domain.AuditFilter{Action: domain.AuditConfigPromoted, Name: name})
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "prod", events[0].Environment)
+		assert.Equal(t, "promoted from staging version 2", events[0].Detail)
+	}
+
+	// Rolling back does not copy the provenance
+	rec = performTenantRequest(router, http.MethodPost, path+"/rollback?env=prod", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, decodeConfig(t, rec).Metadata)
+}
+
+func TestPromoteConfigDryRun(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/payments"
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"max_limit":100,"enabled":false}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"?env=prod", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"prod","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	result := decodePromotion(t, rec)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.Config.Version)
+	assert.Equal(t, []diff.Change{{Path: "enabled", Op: "change", Old: true, New: false}}, result.Changes)
+
+	// Nothing was written
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=prod", "", "", "")
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+
+	// Dry runs into an empty target add every value
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"dev","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, decodePromotion(t, rec).Changes, 2)
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=dev", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPromoteConfigValidation(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/payments"
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	for body, status := range map[string]int{
+		`{"from":"staging","to":"staging"}`:          http.StatusBadRequest,
+		`{"from":"staging","to":"qa"}`:               http.StatusBadRequest,
+		`{"from":"staging"}`:                         http.StatusBadRequest,
+		`{"from":"dev","to":"prod"}`:                 http.StatusNotFound,
+		`{"from":"staging","version":7,"to":"prod"}`: http.StatusNotFound,
+	} {
+		rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", body)
+		assert.Equal(t, status, rec.Code, body)
+	}
+
+	// The source is validated against the current schema
+	schema := domain.Schemas[tenant.Default]["payment_config"]
+	domain.Schemas[tenant.Default]["payment_config"] = `{"type":"object","required":["currency"]}`
+	defer func() { domain.Schemas[tenant.Default]["payment_config"] = schema }()
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"prod"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "currency")
+
+	rec = performTenantRequest(router, http.MethodGet, path+"?env=prod", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPromoteConfigFromLabel(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	name := auditName()
+	path := "/configs/payment_config/" + name
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"?env=staging", "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+"/labels/tested?env=staging", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","label":"tested","to":"prod"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	result := decodePromotion(t, rec)
+	assert.Equal(t, float64(100), result.Config.Data["max_limit"])
+	assert.Equal(t, map[string]interface{}{
+		"promoted_from": map[string]interface{}{"environment": "staging", "version": float64(1), "label": "tested"},
+	}, result.Config.Metadata)
+
+	events := auditEvents(t, domain.AuditFilter{Action: domain.AuditConfigPromoted, Name: name})
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "promoted from staging version 1 labeled tested", events[0].Detail)
+	}
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","label":"missing","to":"prod"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","version":2,"label":"tested","to":"prod"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPromoteConfigRequiresWriter(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	reader := createTestAPIKey(t, "reader", false)
+	bindTestRole(t, "apikey:reader", "reader", "payment_config", "*")
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments/promote", "", reader.Key, `{"from":"staging","to":"prod"}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestClientPromoteConfig(t *testing.T) {
+	server := setupClientServer(t, nil)
+	ctx := context.Background()
+
+	_, err := client.New(server.URL, client.WithEnvironment("staging")).CreateConfig(ctx, "payment_config", "payments", paymentConfig{MaxLimit: 10, Enabled: true})
+	assert.NoError(t, err)
+
+	c := client.New(server.URL)
+	result, err := c.PromoteConfig(ctx, "payment_config", "payments", web.ConfigPromoteRequest{From: "staging", To: "prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, "prod", result.Config.Environment)
+
+	_, err = c.PromoteConfig(ctx, "payment_config", "payments", web.ConfigPromoteRequest{From: "staging", To: "staging"})
+	var apiErr *client.APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	}
+}
@@ -0,0 +1,242 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withEndpointsSchema registers a schema accepting any object, for configs
+// shared through references.
+func withEndpointsSchema(t *testing.T) {
+	domain.Schemas[tenant.Default]["endpoints"] = `{"type": "object"}`
+	t.Cleanup(func() { delete(domain.Schemas[tenant.Default], "endpoints") })
+}
+
+func TestConfigReferencesAreResolved(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/endpoints/shared", "", "", `{"payments":{"url":"https://pay.example.com","limit":500}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":{"$configRef":"endpoints/shared#/payments/limit"},"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/pinned", "", "", `{"max_limit":{"$configRef":"endpoints/shared@1#/payments/limit"},"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(500), decodeConfig(t, rec).Data["max_limit"])
+	etag := rec.Header().Get("ETag")
+
+	// References follow the latest version unless pinned
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/shared", "", "", `{"payments":{"url":"https://pay.example.com","limit":700}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, float64(700), decodeConfig(t, rec).Data["max_limit"])
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/pinned", "", "", "")
+	assert.Equal(t, float64(500), decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?resolve=false", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]interface{}{"$configRef": "endpoints/shared#/payments/limit"}, decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments?resolve=later", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Changes of referenced configs that break a config surface on fetch
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/shared", "", "", `{"payments":{"limit":"unlimited"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "schema validation failed")
+}
+
+func TestConfigReferenceValidation(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/endpoints/shared", "", "", `{"payments":{"url":"https://pay.example.com","limit":500}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	tests := []struct {
+		name string
+		ref  string
+		err  string
+	}{
+		{"resolved value is validated", `{"$configRef":"endpoints/shared#/payments/url"}`, "schema validation failed"},
+		{"unknown config", `{"$configRef":"endpoints/missing#/limit"}`, "referenced config endpoints/missing doesn't exist"},
+		{"unknown version", `{"$configRef":"endpoints/shared@9#/payments/limit"}`, "referenced config endpoints/shared@9 doesn't exist"},
+		{"unknown value", `{"$configRef":"endpoints/shared#/billing/limit"}`, "referenced config endpoints/shared has no value at /billing/limit"},
+		{"missing schema", `{"$configRef":"shared#/payments/limit"}`, "expected schema/name[@version][#pointer]"},
+		{"invalid version", `{"$configRef":"endpoints/shared@latest"}`, "the version must be a positive integer"},
+		{"extra keys", `{"$configRef":"endpoints/shared#/payments/limit","default":1}`, "$configRef objects must only hold a reference string"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":`+test.ref+`,"enabled":true}`)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, errorData(t, rec), test.err)
+		})
+	}
+}
+
+func TestConfigReferenceCycles(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/endpoints/a", "", "", `{"x":1}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/b", "", "", `{"x":{"$configRef":"endpoints/a#/x"}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/a", "", "", `{"x":{"$configRef":"endpoints/b#/x"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "config reference cycle: endpoints/a -> endpoints/b -> endpoints/a", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/a", "", "", `{"x":{"$configRef":"endpoints/a"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "config reference cycle: endpoints/a -> endpoints/a", errorData(t, rec))
+
+	// Older versions don't form a cycle
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/a", "", "", `{"x":2,"y":{"$configRef":"endpoints/a@1#/x"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/a", "", "", "")
+	assert.JSONEq(t, `{"x":2,"y":1}`, mustJSON(t, decodeConfig(t, rec).Data))
+}
+
+func TestAdminCompactKeepsPinnedVersions(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/endpoints/base", "", "", `{"limit":500}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/base", "", "", `{"limit":900}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/shared", "", "", `{"payments":{"limit":{"$configRef":"endpoints/base@1#/limit"}}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/shared", "", "", `{"payments":{"limit":700}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/pinned", "", "", `{"max_limit":{"$configRef":"endpoints/shared@1#/payments/limit"},"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/unused", "", "", `{"limit":1}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/unused", "", "", `{"limit":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// endpoints/shared@1 is pinned by the kept version of payment_config/pinned,
+	// endpoints/base@1 by endpoints/shared@1 in turn
+	result := setupAdminService().CompactConfigs(context.Background(), 1, "")
+	assert.Equal(t, 1, result.Deleted)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/pinned", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(500), decodeConfig(t, rec).Data["max_limit"])
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/unused?version=1", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestConfigReferrers(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/endpoints/shared", "", "", `{"payments":{"limit":500},"urls":["https://a"]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":{"$configRef":"endpoints/shared@1#/payments/limit"},"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/copy", "", "", `{"all":{"$configRef":"endpoints/shared"},"list":[{"$configRef":"endpoints/shared#/urls/0"}]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/other", "", "", `{"a":{"$configRef":"endpoints/copy#/all"}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared/referrers", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var referrers []web.ConfigReferrerResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &referrers))
+	assert.Equal(t, []web.ConfigReferrerResponse{
+		{Schema: "endpoints", Name: "copy", Version: 1, Path: "/all", Ref: "endpoints/shared"},
+		{Schema: "endpoints", Name: "copy", Version: 1, Path: "/list/0", Ref: "endpoints/shared#/urls/0"},
+		{Schema: "payment_config", Name: "payments", Version: 1, Path: "/max_limit", Ref: "endpoints/shared@1#/payments/limit"},
+	}, referrers)
+
+	// Only the latest versions count, in the requested environment
+	rec = performTenantRequest(router, http.MethodPut, "/configs/endpoints/copy", "", "", `{}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared/referrers", "", "", "")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &referrers))
+	assert.Len(t, referrers, 1)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared/referrers?env=prod", "", "", "")
+	assert.JSONEq(t, `[]`, rec.Body.String())
+}
+
+func TestConfigReferencesRequireReadAccess(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := authRouter()
+	admin := createTestAPIKey(t, "admin", true)
+	key := createTestAPIKey(t, "payments", false)
+	bindTestRole(t, "apikey:payments", auth.RoleWriter, "payment_config", "*")
+
+	rec := performAuthRequest(router, http.MethodPost, "/configs/endpoints/secret", admin.Key, strings.NewReader(`{"limit":500}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/configs/payment_config/payments", key.Key, strings.NewReader(`{"max_limit":{"$configRef":"endpoints/secret#/limit"},"enabled":true}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requires the reader role on endpoints/secret")
+}
+
+func TestConfigReferenceClients(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	configClient := client.New(setupClientServer(t, nil).URL)
+
+	_, err := configClient.CreateConfig(ctx, "endpoints", "shared", map[string]interface{}{"limit": 500})
+	assert.NoError(t, err)
+	_, err = configClient.CreateConfig(ctx, "payment_config", "payments", map[string]interface{}{"max_limit": map[string]interface{}{"$configRef": "endpoints/shared#/limit"}, "enabled": true})
+	assert.NoError(t, err)
+
+	config, err := configClient.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(500), config.Data["max_limit"])
+
+	config, err = configClient.FetchUnresolved(ctx, "payment_config", "payments", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"$configRef": "endpoints/shared#/limit"}, config.Data["max_limit"])
+
+	referrers, err := configClient.ListReferrers(ctx, "endpoints", "shared")
+	assert.NoError(t, err)
+	assert.Len(t, referrers, 1)
+
+	resolve := false
+	fetched, err := grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments", Resolve: &resolve})
+	assert.NoError(t, err)
+	assert.Contains(t, fetched.GetData().AsMap()["max_limit"], "$configRef")
+
+	fetched, err = grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(500), fetched.GetData().AsMap()["max_limit"])
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/service"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func bindTestRole(t *testing.T, principal, role, schema, namePattern string) web.RoleBindingResponse {
+	return bindTenantRole(t, tenant.Default, principal, role, schema, namePattern)
+}
+
+func bindTenantRole(t *testing.T, tenantName, principal, role, schema, namePattern string) web.RoleBindingResponse {
+	roleBindingService := service.NewRoleBindingService(repository.NewRoleBindingRepository(), db, validator.New(), newTestAuditService())
+	return roleBindingService.CreateBinding(tenant.With(context.Background(), tenantName), web.RoleBindingCreateRequest{
+		Principal:   principal,
+		Role:        role,
+		Schema:      schema,
+		NamePattern: namePattern,
+	})
+}
+
+func TestRoleBindingsScopeWrites(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	key := createTestAPIKey(t, "payments", false)
+	bindTestRole(t, "apikey:payments", auth.RoleWriter, "payment_config", "payments-*")
+
+	body := `{"max_limit":1000,"enabled":true}`
+	rec := performAuthRequest(router, http.MethodPost, "/configs/payment_config/payments-eu", key.Key, strings.NewReader(body))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// Writers can read what they can write
+	rec = performAuthRequest(router, http.MethodGet, "/configs/payment_config/payments-eu", key.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/configs/payment_config/billing", key.Key, strings.NewReader(body))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requires the writer role on payment_config/billing")
+}
+
+func TestReaderCannotWrite(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	writer := createTestAPIKey(t, "writer", false)
+	reader := createTestAPIKey(t, "reader", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "*", "*")
+	bindTestRole(t, "apikey:reader", auth.RoleReader, "payment_config", "*")
+
+	body := `{"max_limit":1000,"enabled":true}`
+	rec := performAuthRequest(router, http.MethodPost, "/configs/payment_config/payments", writer.Key, strings.NewReader(body))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/configs/payment_config/payments/versions", reader.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPut, "/configs/payment_config/payments", reader.Key, strings.NewReader(body))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/configs/payment_config/payments/rollback", reader.Key, strings.NewReader(`{"version":1}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestRoleBindingEndpoints(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	admin := createTestAPIKey(t, "ops", true)
+
+	request := `{"principal":"jwt:alice","role":"approver","schema":"payment_config"}`
+	rec := performAuthRequest(router, http.MethodPost, "/admin/role-bindings", admin.Key, strings.NewReader(request))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var binding web.RoleBindingResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &binding))
+	assert.Equal(t, "*", binding.NamePattern)
+	assert.Equal(t, "apikey:ops", binding.CreatedBy)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/role-bindings", admin.Key, strings.NewReader(request))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/role-bindings", admin.Key, strings.NewReader(`{"principal":"jwt:bob","role":"owner","schema":"*"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/role-bindings", admin.Key, strings.NewReader(`{"principal":"jwt:bob","role":"reader","schema":"[payment"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/admin/role-bindings?principal=jwt:alice", admin.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var bindings []web.RoleBindingResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &bindings))
+	assert.Len(t, bindings, 1)
+
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/role-bindings/"+strconv.FormatInt(binding.ID, 10), admin.Key, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodDelete, "/admin/role-bindings/"+strconv.FormatInt(binding.ID, 10), admin.Key, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRoleBindingsAreTenantScoped(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	router := authRouter()
+	admin := createTestAPIKey(t, "ops", true)
+	key := createTestAPIKey(t, "deployer", false)
+	binding := bindTenantRole(t, "acme", "apikey:deployer", auth.RoleWriter, "payment_config", "*")
+	assert.Equal(t, "acme", binding.Tenant)
+
+	body := `{"max_limit":1000,"enabled":true}`
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "acme", key.Key, body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	// The binding grants nothing in other tenants
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", tenant.Default, key.Key, body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/globex/configs/payment_config/payments", "", key.Key, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Admins see and delete the bindings of the tenant they address
+	var bindings []web.RoleBindingResponse
+	rec = performTenantRequest(router, http.MethodGet, "/admin/role-bindings", tenant.Default, admin.Key, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &bindings))
+	assert.Empty(t, bindings)
+
+	rec = performTenantRequest(router, http.MethodGet, "/admin/role-bindings", "acme", admin.Key, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &bindings))
+	assert.Len(t, bindings, 1)
+
+	path := "/admin/role-bindings/" + strconv.FormatInt(binding.ID, 10)
+	rec = performTenantRequest(router, http.MethodDelete, path, tenant.Default, admin.Key, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = performTenantRequest(router, http.MethodDelete, path, "acme", admin.Key, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "acme", key.Key, body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// The same binding may exist once per tenant
+	request := `{"principal":"apikey:deployer","role":"writer","schema":"payment_config"}`
+	for _, tenantName := range []string{"acme", tenant.Default} {
+		rec = performTenantRequest(router, http.MethodPost, "/admin/role-bindings", tenantName, admin.Key, request)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+}
+
+func TestRoleHierarchy(t *testing.T) {
+	assert.True(t, auth.Grants(auth.RoleAdmin, auth.RoleReader))
+	assert.True(t, auth.Grants(auth.RoleApprover, auth.RoleWriter))
+	assert.False(t, auth.Grants(auth.RoleWriter, auth.RoleApprover))
+	assert.False(t, auth.Grants("owner", auth.RoleReader))
+}
@@ -0,0 +1,250 @@
+package test
+
+import (
+	"config-service/client"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/service"
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func effectiveAtQuery(at time.Time) string {
+	return "?effective_at=" + url.QueryEscape(at.Format(time.RFC3339))
+}
+
+// activateVersion moves the activation time of a scheduled version into
+// the past, as if it was reached.
+func activateVersion(t *testing.T, schema, name string, version int, at time.Time) {
+	_, err := db.Exec(`UPDATE configs SET effective_at = ? WHERE schema = ? AND name = ? AND version = ?`, at.UTC(), schema, name, version)
+	assert.NoError(t, err)
+}
+
+func nextEvent(t *testing.T, events <-chan web.ConfigResponse) web.ConfigResponse {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no config version published")
+		return web.ConfigResponse{}
+	}
+}
+
+func TestScheduledVersionsAreServedOnceActive(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+	effectiveAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(effectiveAt), "", "", `{"max_limit":500,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	scheduled := decodeConfig(t, rec)
+	assert.Equal(t, 2, scheduled.Version)
+	if assert.NotNil(t, scheduled.EffectiveAt) {
+		assert.True(t, effectiveAt.Equal(*scheduled.EffectiveAt))
+	}
+
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Equal(t, 1, config.Version)
+	assert.Equal(t, float64(100), config.Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, path+"?version=2", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(500), decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, path+"/environments", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"version":1`)
+	assert.NotContains(t, rec.Body.String(), `"version":2`)
+
+	// The next version follows the scheduled one
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"default","to":"prod","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"max_limit":100`)
+
+	activateVersion(t, "payment_config", "checkout", 2, time.Now().Add(-time.Second))
+
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, decodeConfig(t, rec).Version)
+
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(-time.Minute)), "", "", `{"max_limit":700,"enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "effective_at must be in the future", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPut, path+"?effective_at=tonight", "", "", `{"max_limit":700,"enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Scheduled new configs are not found until they are active
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/refunds"+effectiveAtQuery(effectiveAt), "", "", `{"max_limit":10,"enabled":false}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/refunds", "", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestScheduledVersionsCanBeCancelled(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":500,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":1}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "version 1 is not scheduled, it is already active", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotNil(t, decodeConfig(t, rec).CancelledAt)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "version 2 is already cancelled", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":9}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Cancelled versions never become active
+	activateVersion(t, "payment_config", "checkout", 2, time.Now().Add(-time.Second))
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+
+	rec = performTenantRequest(router, http.MethodPut, path, "", "", `{"max_limit":200,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 3, decodeConfig(t, rec).Version)
+
+	rec = performTenantRequest(router, http.MethodGet, path+"/versions", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "cancelled_at")
+}
+
+func TestRollbackToScheduledVersionsIsActive(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+	path := "/configs/payment_config/checkout"
+
+	rec := performTenantRequest(router, http.MethodPost, path, "", "", `{"max_limit":100,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":500,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"/cancel", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, path+effectiveAtQuery(time.Now().Add(time.Hour)), "", "", `{"max_limit":300,"enabled":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Rolling back to the cancelled version neither cancels nor schedules
+	// the new one
+	rec = performTenantRequest(router, http.MethodPost, path+"/rollback", "", "", `{"version":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Equal(t, 4, config.Version)
+	assert.Nil(t, config.EffectiveAt)
+	assert.Nil(t, config.CancelledAt)
+	assert.WithinDuration(t, time.Now(), config.CreatedAt, time.Minute)
+
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config = decodeConfig(t, rec)
+	assert.Equal(t, 4, config.Version)
+	assert.Equal(t, float64(500), config.Data["max_limit"])
+	assert.WithinDuration(t, time.Now(), config.CreatedAt, time.Minute)
+
+	// Rolling back to the pending scheduled version activates it now
+	rec = performTenantRequest(router, http.MethodPost, path+"/rollback", "", "", `{"version":3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, decodeConfig(t, rec).EffectiveAt)
+
+	rec = performTenantRequest(router, http.MethodGet, path, "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config = decodeConfig(t, rec)
+	assert.Equal(t, 5, config.Version)
+	assert.Equal(t, float64(300), config.Data["max_limit"])
+}
+
+func TestActivationsArePublishedToWatchers(t *testing.T) {
+	truncateConfigs(db)
+	configService := service.NewConfigService(newTestConfigRepository(testSettings()), db, validator.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, _ := configService.Watch(ctx, "payment_config", "checkout")
+
+	start := time.Now().Add(-time.Minute)
+	configService.CreateConfig(ctx, "payment_config", "checkout", web.ConfigCreateRequest{Data: map[string]interface{}{"max_limit": 100, "enabled": true}})
+	assert.Equal(t, 1, nextEvent(t, events).Version)
+
+	effectiveAt := time.Now().Add(time.Hour)
+	configService.UpdateConfig(ctx, "payment_config", "checkout", web.ConfigUpdateRequest{Data: map[string]interface{}{"max_limit": 500, "enabled": true}, EffectiveAt: &effectiveAt})
+	configService.UpdateConfig(ctx, "payment_config", "checkout", web.ConfigUpdateRequest{Data: map[string]interface{}{"max_limit": 600, "enabled": true}, EffectiveAt: &effectiveAt})
+	select {
+	case event := <-events:
+		t.Fatalf("scheduled version %d published before its activation", event.Version)
+	default:
+	}
+
+	assert.Equal(t, 0, configService.PublishActivations(ctx, start, time.Now()))
+
+	// Version 3 supersedes version 2 once both are active
+	activateVersion(t, "payment_config", "checkout", 2, time.Now().Add(-2*time.Second))
+	activateVersion(t, "payment_config", "checkout", 3, time.Now().Add(-time.Second))
+	assert.Equal(t, 1, configService.PublishActivations(ctx, start, time.Now()))
+	event := nextEvent(t, events)
+	assert.Equal(t, 3, event.Version)
+	assert.Equal(t, float64(600), event.Data["max_limit"])
+
+	assert.Equal(t, 0, configService.PublishActivations(ctx, time.Now(), time.Now().Add(time.Second)))
+}
+
+func TestScheduleClients(t *testing.T) {
+	truncateConfigs(db)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	server := setupClientServer(t, nil)
+	configClient := client.New(server.URL)
+	effectiveAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	_, err := configClient.CreateConfig(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 100, "enabled": true})
+	assert.NoError(t, err)
+
+	scheduled, err := configClient.UpdateConfigAt(ctx, "payment_config", "checkout", map[string]interface{}{"max_limit": 500, "enabled": true}, effectiveAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, scheduled.Version)
+
+	config, err := configClient.FetchConfig(ctx, "payment_config", "checkout", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, config.Version)
+
+	cancelled, err := configClient.CancelConfig(ctx, "payment_config", "checkout", 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, cancelled.CancelledAt)
+
+	data, err := structpb.NewStruct(map[string]interface{}{"max_limit": 700, "enabled": true})
+	assert.NoError(t, err)
+	updated, err := grpcClient.UpdateConfig(ctx, &configpb.UpdateConfigRequest{Schema: "payment_config", Name: "checkout", Data: data, EffectiveAt: timestamppb.New(effectiveAt)})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), updated.GetVersion())
+	assert.True(t, effectiveAt.Equal(updated.GetEffectiveAt().AsTime()))
+
+	fetched, err := grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "checkout"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), fetched.GetVersion())
+
+	cancelledPB, err := grpcClient.CancelConfig(ctx, &configpb.CancelConfigRequest{Schema: "payment_config", Name: "checkout", Version: 3})
+	assert.NoError(t, err)
+	assert.NotNil(t, cancelledPB.GetCancelledAt())
+}
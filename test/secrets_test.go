@@ -0,0 +1,231 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/repository"
+	"config-service/secret"
+	"config-service/service"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withDatabaseSchema(t *testing.T) {
+	domain.Schemas[tenant.Default]["database"] = `{
+		"type": "object",
+		"properties": {
+			"host": {"type": "string"},
+			"password": {"type": "string", "x-secret": true},
+			"replicas": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"properties": {"host": {"type": "string"}, "token": {"type": "string", "x-secret": true}}
+				}
+			}
+		},
+		"required": ["host", "password"]
+	}`
+	t.Cleanup(func() { delete(domain.Schemas[tenant.Default], "database") })
+}
+
+func storedData(t *testing.T, schema, name string, version int) string {
+	var data string
+	err := db.QueryRow(`SELECT data FROM configs WHERE schema = ? AND name = ? AND version = ?`, schema, name, version).Scan(&data)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestSecretValuesAreEncryptedAndMasked(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/database/orders", "", "", `{"host":"db.example.com","password":"hunter2","replicas":[{"host":"r1","token":"t0ken"}]}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, secret.Mask, decodeConfig(t, rec).Data["password"])
+
+	stored := storedData(t, "database", "orders", 1)
+	assert.NotContains(t, stored, "hunter2")
+	assert.NotContains(t, stored, "t0ken")
+	assert.Contains(t, stored, "db.example.com")
+	assert.Contains(t, stored, secret.EnvelopeKey)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/database/orders", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config := decodeConfig(t, rec)
+	assert.Equal(t, "db.example.com", config.Data["host"])
+	assert.Equal(t, secret.Mask, config.Data["password"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"host": "r1", "token": secret.Mask}}, config.Data["replicas"])
+	maskedETag := rec.Header().Get("ETag")
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/database/orders?reveal=true", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	config = decodeConfig(t, rec)
+	assert.Equal(t, "hunter2", config.Data["password"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"host": "r1", "token": "t0ken"}}, config.Data["replicas"])
+	assert.NotEqual(t, maskedETag, rec.Header().Get("ETag"))
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/database/orders/versions", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+
+	events := newTestAuditService().ListEvents(context.Background(), domain.AuditFilter{Action: domain.AuditSecretsRevealed, Schema: "database"})
+	if assert.NotEmpty(t, events) {
+		assert.Equal(t, "orders", events[0].Name)
+	}
+
+	// Masks keep the stored secret values
+	rec = performTenantRequest(router, http.MethodPut, "/configs/database/orders", "", "", `{"host":"db2.example.com","password":"********","replicas":[{"host":"r1","token":"********"}]}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/database/orders?reveal=true", "", "", "")
+	config = decodeConfig(t, rec)
+	assert.Equal(t, "db2.example.com", config.Data["host"])
+	assert.Equal(t, "hunter2", config.Data["password"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"host": "r1", "token": "t0ken"}}, config.Data["replicas"])
+
+	rec = performTenantRequest(router, http.MethodPut, "/configs/database/orders", "", "", `{"host":"db2.example.com","password":"hunter2","replicas":[{"host":"r1","token":"t0ken"},{"host":"r2","token":"********"}]}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "no secret value is stored at /replicas/1/token to keep", errorData(t, rec))
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/database/billing", "", "", `{"host":"db.example.com","password":"********"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/database/billing", "", "", `{"host":"db.example.com","password":{"$secret":"v1:test:a:b"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRevealingSecretsRequiresApproverRole(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	router := authRouter()
+	writer := createTestAPIKey(t, "writer", false)
+	approver := createTestAPIKey(t, "approver", false)
+	bindTestRole(t, "apikey:writer", auth.RoleWriter, "database", "*")
+	bindTestRole(t, "apikey:approver", auth.RoleApprover, "database", "*")
+
+	rec := performAuthRequest(router, http.MethodPost, "/configs/database/orders", writer.Key, strings.NewReader(`{"host":"db.example.com","password":"hunter2"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodGet, "/configs/database/orders?reveal=true", writer.Key, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requires the approver role on database/orders")
+
+	rec = performAuthRequest(router, http.MethodGet, "/configs/database/orders?reveal=true", approver.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hunter2", decodeConfig(t, rec).Data["password"])
+}
+
+func TestSecretValuesCannotBeReferenced(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/database/orders", "", "", `{"host":"db.example.com","password":"hunter2"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/orders", "", "", `{"db":{"$configRef":"database/orders#/host"}}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/leak", "", "", `{"db":{"$configRef":"database/orders"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "reference database/orders points to secret values", errorData(t, rec))
+}
+
+func TestPromotionDiffMasksSecrets(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	router := setupRouter(db)
+	path := "/configs/database/orders"
+
+	rec := performTenantRequest(router, http.MethodPost, path+"?env=staging", "", "", `{"host":"db.example.com","password":"staging-pw"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, path+"?env=prod", "", "", `{"host":"db.example.com","password":"prod-pw"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, path+"/promote", "", "", `{"from":"staging","to":"prod","dry_run":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "-pw")
+
+	var result web.ConfigPromoteResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	if assert.Len(t, result.Changes, 1) {
+		assert.Equal(t, "password", result.Changes[0].Path)
+		assert.Equal(t, secret.Mask, result.Changes[0].Old)
+		assert.Equal(t, secret.Mask, result.Changes[0].New)
+	}
+}
+
+func TestSecretKeysCanBeRotated(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/database/orders", "", "", `{"host":"db.example.com","password":"hunter2"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, storedData(t, "database", "orders", 1), "v1:test:")
+
+	settings := testSettings()
+	settings.SecretKeys["next"] = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+	settings.SecretKeyID = "next"
+	keyring, err := secret.NewKeyring(settings.SecretKeys, settings.SecretKeyID)
+	assert.NoError(t, err)
+
+	adminService := service.NewAdminService(repository.NewSecretConfigRepository(repository.NewConfigRepository(), keyring), repository.NewVariableRepository(), repository.NewLabelRepository(), repository.NewFreezeRepository(), db, newTestAuditService())
+	assert.Equal(t, 1, adminService.RotateSecrets(context.Background()).Rotated)
+	assert.Equal(t, 0, adminService.RotateSecrets(context.Background()).Rotated)
+
+	stored := storedData(t, "database", "orders", 1)
+	assert.Contains(t, stored, "v1:next:")
+	assert.NotContains(t, stored, "hunter2")
+
+	// The old key is no longer needed
+	delete(settings.SecretKeys, "test")
+	keyring, err = secret.NewKeyring(settings.SecretKeys, settings.SecretKeyID)
+	assert.NoError(t, err)
+	rotated := repository.NewSecretConfigRepository(repository.NewConfigRepository(), keyring)
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	defer tx.Rollback()
+	configRecord, err := rotated.GetLatest(context.Background(), tx, domain.ConfigRecord{Tenant: tenant.Default, Environment: "default", Schema: "database", Name: "orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", configRecord.Data["password"])
+}
+
+func TestSecretClients(t *testing.T) {
+	truncateConfigs(db)
+	withDatabaseSchema(t)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	server := setupClientServer(t, nil)
+	configClient := client.New(server.URL)
+
+	_, err := configClient.CreateConfig(ctx, "database", "orders", map[string]interface{}{"host": "db.example.com", "password": "hunter2"})
+	assert.NoError(t, err)
+
+	config, err := configClient.FetchConfig(ctx, "database", "orders", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Mask, config.Data["password"])
+
+	config, err = configClient.FetchSecrets(ctx, "database", "orders", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", config.Data["password"])
+
+	fetched, err := grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "database", Name: "orders"})
+	assert.NoError(t, err)
+	assert.Equal(t, secret.Mask, fetched.GetData().AsMap()["password"])
+
+	fetched, err = grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "database", Name: "orders", Reveal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", fetched.GetData().AsMap()["password"])
+}
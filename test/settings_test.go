@@ -0,0 +1,85 @@
+package test
+
+import (
+	"config-service/app"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadTestSettings(args ...string) (app.Settings, error) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	return app.LoadSettings(flags, append([]string{"-schema-dir", "../schemas"}, args...))
+}
+
+func TestSettingsDefaults(t *testing.T) {
+	settings, err := loadTestSettings()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ":3000", settings.HTTPAddr)
+	assert.Equal(t, "config_database.db", settings.DBPath)
+	assert.Equal(t, 5*time.Second, settings.ShutdownTimeout)
+}
+
+func TestSettingsPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.yaml")
+	err := os.WriteFile(file, []byte("http_addr: \":4000\"\ndb_path: file.db\nshutdown_timeout: 10s\n"), 0o644)
+	assert.NoError(t, err)
+
+	t.Setenv("CONFIG_SERVICE_CONFIG", file)
+	t.Setenv("CONFIG_SERVICE_DB_PATH", "env.db")
+	t.Setenv("CONFIG_SERVICE_HTTP_ADDR", ":5000")
+
+	settings, err := loadTestSettings("-http-addr", ":6000")
+	assert.NoError(t, err)
+
+	// flag > env > file > default
+	assert.Equal(t, ":6000", settings.HTTPAddr)
+	assert.Equal(t, "env.db", settings.DBPath)
+	assert.Equal(t, 10*time.Second, settings.ShutdownTimeout)
+	assert.Equal(t, ":3001", settings.GRPCAddr)
+}
+
+func TestSettingsValidation(t *testing.T) {
+	_, err := loadTestSettings("-http-addr", "3000")
+	assert.ErrorContains(t, err, "http_addr")
+
+	_, err = loadTestSettings("-grpc-addr", ":3000")
+	assert.ErrorContains(t, err, "must differ")
+
+	_, err = loadTestSettings("-shutdown-timeout", "soon")
+	assert.ErrorContains(t, err, "shutdown-timeout")
+
+	_, err = loadTestSettings("-schema-dir", "missing")
+	assert.ErrorContains(t, err, "schema_dir")
+
+	file := filepath.Join(t.TempDir(), "settings.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte("htp_addr: \":4000\"\n"), 0o644))
+	_, err = loadTestSettings("-config", file)
+	assert.ErrorContains(t, err, "htp_addr")
+}
+
+func TestSettingsTraceExporter(t *testing.T) {
+	settings, err := loadTestSettings("-trace-exporter", "stdout")
+	assert.NoError(t, err)
+	assert.Equal(t, "stdout", settings.TraceExporter)
+
+	_, err = loadTestSettings("-trace-exporter", "jaeger")
+	assert.ErrorContains(t, err, "trace_exporter")
+}
+
+func TestSettingsJWT(t *testing.T) {
+	settings, err := loadTestSettings("-jwt-jwks", "jwks.json", "-jwt-issuer", "https://idp", "-jwt-audience", "config-service", "-jwt-role-mapping", "platform=admin, payments=writer")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"platform": "admin", "payments": "writer"}, settings.JWTRoleMapping)
+
+	_, err = loadTestSettings("-jwt-jwks", "jwks.json")
+	assert.ErrorContains(t, err, "jwt_issuer and jwt_audience are required")
+
+	_, err = loadTestSettings("-jwt-role-mapping", "platform=root")
+	assert.ErrorContains(t, err, "unknown role")
+}
@@ -0,0 +1,229 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"config-service/tenant"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withTenantSchemas gives tenant a copy of the default payment_config schema
+// for the duration of the test.
+func withTenantSchemas(t *testing.T, tenantName string) {
+	schema, ok := domain.LookupSchema(tenant.Default, "payment_config")
+	assert.True(t, ok)
+
+	domain.Schemas[tenantName] = map[string]string{"payment_config": schema}
+	t.Cleanup(func() { delete(domain.Schemas, tenantName) })
+}
+
+func performTenantRequest(router http.Handler, method, path, tenantName, apiKey, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if tenantName != "" {
+		req.Header.Set(tenant.Header, tenantName)
+	}
+	if apiKey != "" {
+		req.Header.Set(auth.APIKeyHeader, apiKey)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeConfig(t *testing.T, rec *httptest.ResponseRecorder) web.ConfigResponse {
+	var config web.ConfigResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config))
+	return config
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, tenant.Default, decodeConfig(t, rec).Tenant)
+
+	// The same name is free in another tenant
+	rec = performTenantRequest(router, http.MethodPost, "/tenants/acme/configs/payment_config/payments", "", "", `{"max_limit":5,"enabled":false}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "acme", decodeConfig(t, rec).Tenant)
+	assert.Equal(t, 1, decodeConfig(t, rec).Version)
+
+	// The header selects the tenant as well
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "acme", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(5), decodeConfig(t, rec).Data["max_limit"])
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, float64(1000), decodeConfig(t, rec).Data["max_limit"])
+
+	// Tenants without the schema cannot use it
+	rec = performTenantRequest(router, http.MethodPost, "/tenants/globex/configs/payment_config/payments", "", "", `{"max_limit":1,"enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/globex/schemas/", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "null", rec.Body.String())
+
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/acme/schemas/", "", "", "")
+	assert.Contains(t, rec.Body.String(), "payment_config")
+}
+
+func TestTenantNameIsValidated(t *testing.T) {
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "Not A Tenant", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid tenant name")
+
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/ACME/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTenantBoundAPIKey(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	router := authRouter()
+
+	admin := createTestAPIKey(t, "admin", true)
+	rec := performAuthRequest(router, http.MethodPost, "/admin/api-keys", admin.Key, strings.NewReader(`{"name":"acme-deployer","tenant":"acme"}`))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var key web.APIKeyCreateResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &key))
+	assert.Equal(t, "acme", key.Tenant)
+	bindTenantRole(t, "acme", "apikey:acme-deployer", auth.RoleWriter, "payment_config", "*")
+
+	// Requests of the key go to its tenant
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", key.Key, `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "acme", decodeConfig(t, rec).Tenant)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "acme", key.Key, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Other tenants are off limits
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", tenant.Default, key.Key, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/globex/configs/payment_config/payments", "", key.Key, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Unbound keys reach every tenant
+	rec = performTenantRequest(router, http.MethodGet, "/tenants/acme/configs/payment_config/payments", "", admin.Key, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = performAuthRequest(router, http.MethodPost, "/admin/api-keys", admin.Key, strings.NewReader(`{"name":"acme-admin","admin":true,"tenant":"acme"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performAuthRequest(router, http.MethodPost, "/admin/api-keys", admin.Key, strings.NewReader(`{"name":"bad","tenant":"Bad Tenant"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTenantQuotas(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	settings := testSettings()
+	settings.TenantMaxConfigs = 2
+	settings.TenantQuotas = map[string]int{"acme": 1}
+	router := setupRouterWithSettings(db, settings)
+
+	body := `{"max_limit":1000,"enabled":true}`
+	rec := performTenantRequest(router, http.MethodPost, "/configs/payment_config/one", "acme", "", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/two", "acme", "", body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "tenant acme reached its quota of 1 configs")
+
+	// New versions of existing configs do not count
+	rec = performTenantRequest(router, http.MethodPut, "/configs/payment_config/one", "acme", "", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Other tenants use the default quota
+	for _, name := range []string{"one", "two"} {
+		rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/"+name, "", "", body)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	}
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/three", "", "", body)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestJWTTenantClaim(t *testing.T) {
+	truncateConfigs(db)
+	withTenantSchemas(t, "acme")
+	issuer := newTestIssuerKeys(t, "key-1")
+	router := setupRouterWithSettings(db, jwtSettings(issuer.server().URL))
+
+	token := issuer.token("key-1", jwt.MapClaims{"groups": "payments-team", "tenant": "acme"})
+	rec := performBearerRequest(router, http.MethodPost, "/configs/payment_config/payments", token, `{"max_limit":1000,"enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "acme", decodeConfig(t, rec).Tenant)
+
+	rec = performBearerRequest(router, http.MethodGet, "/tenants/default/configs/payment_config/payments", token, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Tenant-bound admins cannot use the admin endpoints
+	admin := issuer.token("key-1", jwt.MapClaims{"groups": "platform", "tenant": "acme"})
+	rec = performBearerRequest(router, http.MethodGet, "/admin/api-keys", admin, "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	invalid := issuer.token("key-1", jwt.MapClaims{"groups": "payments-team", "tenant": 42})
+	rec = performBearerRequest(router, http.MethodGet, "/schemas/", invalid, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestGRPCTenantMetadata(t *testing.T) {
+	withTenantSchemas(t, "acme")
+	client := setupGRPCClient(t)
+
+	acme := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+	created, err := client.CreateConfig(acme, &configpb.CreateConfigRequest{Schema: "payment_config", Name: "payments", Data: paymentData(t, 1000, true)})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", created.GetTenant())
+
+	_, err = client.FetchConfig(context.Background(), &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	fetched, err := client.FetchConfig(acme, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), fetched.GetVersion())
+}
+
+func TestLoadSchemasPerTenant(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "shared.json"), []byte(`{"type":"object"}`), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "acme"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "acme", "private.json"), []byte(`{"type":"object"}`), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Not A Tenant"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Not A Tenant", "ignored.json"), []byte(`{}`), 0o644))
+
+	loaded := domain.Schemas
+	domain.Schemas = map[string]map[string]string{}
+	defer func() { domain.Schemas = loaded }()
+
+	assert.NoError(t, domain.LoadSchemas(dir))
+	assert.Equal(t, []string{"acme", tenant.Default}, domain.Tenants())
+	assert.Equal(t, []string{"shared"}, domain.SchemaNames(tenant.Default))
+	assert.Equal(t, []string{"private"}, domain.SchemaNames("acme"))
+
+	_, ok := domain.LookupSchema("acme", "shared")
+	assert.False(t, ok)
+}
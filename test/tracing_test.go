@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// captureSpans installs an in-memory tracer provider for the test duration.
+func captureSpans(t *testing.T) *tracetest.InMemoryExporter {
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func TestTraceContextIsPropagated(t *testing.T) {
+	truncateConfigs(db)
+	exporter := captureSpans(t)
+	router := setupRouter(db)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/configs/payment_config/payments", strings.NewReader(`{"max_limit":1000,"enabled":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	names := map[string]bool{}
+	for _, span := range exporter.GetSpans() {
+		assert.Equal(t, traceID, span.SpanContext.TraceID().String(), span.Name)
+		names[span.Name] = true
+	}
+
+	assert.True(t, names["/configs/:schema/:name"])
+	assert.True(t, names["ConfigService.CreateConfig"])
+	assert.True(t, names["schema.validate"])
+	assert.True(t, names["ConfigRepository.GetLatest"])
+	assert.True(t, names["ConfigRepository.CreateNewVersion"])
+}
+
+func TestSpansRecordErrors(t *testing.T) {
+	truncateConfigs(db)
+	exporter := captureSpans(t)
+	router := setupRouter(db)
+
+	req := httptest.NewRequest(http.MethodPut, "/configs/payment_config/payments", strings.NewReader(`{"max_limit":1000}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var failed bool
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "schema.validate" {
+			failed = span.Status.Code.String() == "Error"
+		}
+	}
+	assert.True(t, failed)
+}
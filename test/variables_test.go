@@ -0,0 +1,192 @@
+package test
+
+import (
+	"config-service/auth"
+	"config-service/client"
+	"config-service/model/web"
+	"config-service/proto/configpb"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigPlaceholdersAreRendered(t *testing.T) {
+	truncateConfigs(db)
+	withEndpointsSchema(t)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPut, "/variables/limit", "", "", `{"value":500}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPut, "/variables/host", "", "", `{"value":"pay.example.com"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Placeholders are stored as they are
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":"${limit}","enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "${limit}", decodeConfig(t, rec).Data["max_limit"])
+
+	// Whole string placeholders take the type of the variable
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(500), decodeConfig(t, rec).Data["max_limit"])
+	etag := rec.Header().Get("ETag")
+
+	rec = performTenantRequest(router, http.MethodPost, "/configs/endpoints/shared", "", "", `{"url":"https://${host}/v1?limit=${limit}","literal":"$${host}","missing":"${nope}"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"url":"https://pay.example.com/v1?limit=500","literal":"${host}","missing":"${nope}"}`, mustJSON(t, decodeConfig(t, rec).Data))
+
+	// Strict fetches fail on undefined variables
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared?strict=true", "", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "undefined variables: nope")
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared?strict=maybe", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodGet, "/configs/endpoints/shared?resolve=false", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://${host}/v1?limit=${limit}", decodeConfig(t, rec).Data["url"])
+
+	// Changed variables show through, and change the ETag
+	rec = performTenantRequest(router, http.MethodPut, "/variables/limit", "", "", `{"value":700}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, float64(700), decodeConfig(t, rec).Data["max_limit"])
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	// Rendered configs are validated against their schema
+	rec = performTenantRequest(router, http.MethodPut, "/variables/limit", "", "", `{"value":"lots"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodGet, "/configs/payment_config/payments", "", "", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, errorData(t, rec), "schema validation failed")
+}
+
+func TestConfigPlaceholderValidation(t *testing.T) {
+	truncateConfigs(db)
+	router := setupRouter(db)
+
+	rec := performTenantRequest(router, http.MethodPut, "/variables/host", "", "", `{"value":"pay.example.com"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Configs are validated as rendered with the variables of their environment
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":"${host}","enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments", "", "", `{"max_limit":"${limit}","enabled":true}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = performTenantRequest(router, http.MethodPut, "/variables/limit?env=prod", "", "", `{"value":900}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performTenantRequest(router, http.MethodPost, "/configs/payment_config/payments?env=prod", "", "", `{"max_limit":"${limit}","enabled":true}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"invalid name", http.MethodPut, "/variables/1st", `{"value":1}`, http.StatusBadRequest},
+		{"name with dashes", http.MethodPut, "/variables/db-host", `{"value":1}`, http.StatusBadRequest},
+		{"missing value", http.MethodPut, "/variables/host", `{}`, http.StatusBadRequest},
+		{"unknown variable", http.MethodDelete, "/variables/nope", "", http.StatusNotFound},
+		{"dotted name", http.MethodPut, "/variables/db.host", `{"value":"db"}`, http.StatusOK},
+		{"delete", http.MethodDelete, "/variables/host", "", http.StatusNoContent},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := performTenantRequest(router, test.method, test.path, "", "", test.body)
+			assert.Equal(t, test.status, rec.Code)
+		})
+	}
+
+	// Variables are kept per environment
+	var variables []web.VariableResponse
+	rec = performTenantRequest(router, http.MethodGet, "/variables", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &variables))
+	if assert.Len(t, variables, 1) {
+		assert.Equal(t, "db.host", variables[0].Name)
+	}
+
+	rec = performTenantRequest(router, http.MethodGet, "/variables?env=prod", "", "", "")
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &variables))
+	if assert.Len(t, variables, 1) {
+		assert.Equal(t, "limit", variables[0].Name)
+		assert.Equal(t, float64(900), variables[0].Value)
+	}
+
+	var audited int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE action = 'variable.set' AND environment = 'prod' AND name = 'limit'`).Scan(&audited))
+	assert.NotZero(t, audited)
+}
+
+func TestVariablesRequireAccessToEveryConfig(t *testing.T) {
+	truncateConfigs(db)
+	router := authRouter()
+	key := createTestAPIKey(t, "payments", false)
+	bindTestRole(t, "apikey:payments", auth.RoleWriter, "payment_config", "*")
+
+	rec := performAuthRequest(router, http.MethodPut, "/variables/limit", key.Key, strings.NewReader(`{"value":1}`))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requires the writer role on */*")
+
+	rec = performAuthRequest(router, http.MethodGet, "/variables", key.Key, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	bindTestRole(t, "apikey:payments", auth.RoleWriter, "*", "*")
+	rec = performAuthRequest(router, http.MethodPut, "/variables/limit", key.Key, strings.NewReader(`{"value":1}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = performAuthRequest(router, http.MethodGet, "/variables", key.Key, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestVariableClients(t *testing.T) {
+	truncateConfigs(db)
+	ctx := context.Background()
+	grpcClient := setupGRPCClient(t)
+	server := setupClientServer(t, nil)
+	configClient := client.New(server.URL)
+
+	variable, err := configClient.SetVariable(ctx, "limit", 500)
+	assert.NoError(t, err)
+	assert.Equal(t, "limit", variable.Name)
+
+	variables, err := configClient.ListVariables(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, variables, 1)
+
+	_, err = configClient.CreateConfig(ctx, "payment_config", "payments", map[string]interface{}{"max_limit": "${limit}", "enabled": true})
+	assert.NoError(t, err)
+
+	config, err := configClient.FetchConfig(ctx, "payment_config", "payments", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(500), config.Data["max_limit"])
+
+	fetched, err := grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments", Strict: true})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(500), fetched.GetData().AsMap()["max_limit"])
+
+	// The config can't be validated without the variable, so it is written
+	// around the service
+	assert.NoError(t, configClient.DeleteVariable(ctx, "limit"))
+	_, err = db.Exec(`INSERT INTO configs (tenant, environment, schema, name, version, data, created_at) VALUES ('default', 'default', 'payment_config', 'payments', 2, '{"max_limit":1,"enabled":"${enabled}"}', CURRENT_TIMESTAMP)`)
+	assert.NoError(t, err)
+
+	_, err = client.New(server.URL, client.WithStrictVariables()).FetchConfig(ctx, "payment_config", "payments", 0)
+	var apiErr *client.APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	}
+
+	_, err = grpcClient.FetchConfig(ctx, &configpb.FetchConfigRequest{Schema: "payment_config", Name: "payments", Strict: true})
+	assert.ErrorContains(t, err, "undefined variables: enabled")
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"config-service/model/domain"
+	"config-service/model/web"
+	"config-service/repository"
+	"config-service/service"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchSendsResolvedConfigs(t *testing.T) {
+	truncateConfigs(db)
+	withServiceSchema(t)
+	withEndpointsSchema(t)
+	validate := validator.New()
+	variableRepository := repository.NewVariableRepository()
+	configService := service.NewConfigService(newTestConfigRepository(testSettings()), db, validate, service.WithVariables(variableRepository))
+	variableService := service.NewVariableService(variableRepository, db, validate, newTestAuditService(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	variableService.SetVariable(ctx, "level", web.VariableSetRequest{Value: "info"})
+	configService.CreateConfig(ctx, "endpoints", "shared", web.ConfigCreateRequest{Data: map[string]interface{}{"hosts": []interface{}{"a"}}})
+	configService.CreateConfig(ctx, "service_config", "base", web.ConfigCreateRequest{Data: map[string]interface{}{
+		"timeouts":  map[string]interface{}{"connect": 5, "read": 10},
+		"hosts":     map[string]interface{}{"$configRef": "endpoints/shared#/hosts"},
+		"log_level": "${level}",
+	}})
+	configService.CreateConfig(ctx, "service_config", "eu", web.ConfigCreateRequest{Data: map[string]interface{}{"$parent": "base", "timeouts": map[string]interface{}{"read": 30}}})
+
+	events, _ := configService.Watch(ctx, "service_config", "eu")
+
+	// Versions are sent as fetched, merged with their parents and with their
+	// references and placeholders resolved
+	configService.UpdateConfig(ctx, "service_config", "eu", web.ConfigUpdateRequest{Data: map[string]interface{}{"$parent": "base", "timeouts": map[string]interface{}{"read": 40}}})
+	event := nextEvent(t, events)
+	assert.Equal(t, 2, event.Version)
+	assert.JSONEq(t, `{"timeouts":{"connect":5,"read":40},"hosts":["a"],"log_level":"info"}`, mustJSON(t, event.Data))
+
+	// Changes of the parent and of the referenced configs are sent with the
+	// version of the watched config
+	configService.UpdateConfig(ctx, "service_config", "base", web.ConfigUpdateRequest{Data: map[string]interface{}{
+		"timeouts":  map[string]interface{}{"connect": 7, "read": 10},
+		"hosts":     map[string]interface{}{"$configRef": "endpoints/shared#/hosts"},
+		"log_level": "${level}",
+	}})
+	event = nextEvent(t, events)
+	assert.Equal(t, 2, event.Version)
+	assert.JSONEq(t, `{"timeouts":{"connect":7,"read":40},"hosts":["a"],"log_level":"info"}`, mustJSON(t, event.Data))
+
+	configService.UpdateConfig(ctx, "endpoints", "shared", web.ConfigUpdateRequest{Data: map[string]interface{}{"hosts": []interface{}{"b"}}})
+	event = nextEvent(t, events)
+	assert.Equal(t, []interface{}{"b"}, event.Data["hosts"])
+
+	// Unrelated configs and changes keeping the data are not sent
+	configService.CreateConfig(ctx, "endpoints", "other", web.ConfigCreateRequest{Data: map[string]interface{}{"hosts": []interface{}{"c"}}})
+	configService.UpdateConfig(ctx, "endpoints", "shared", web.ConfigUpdateRequest{Data: map[string]interface{}{"hosts": []interface{}{"b"}, "unused": true}})
+	select {
+	case event := <-events:
+		t.Fatalf("unchanged config sent: %v", event.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	configService.UpdateConfig(ctx, "service_config", "eu", web.ConfigUpdateRequest{Data: map[string]interface{}{"$parent": "base", "timeouts": map[string]interface{}{"read": 50}}})
+	assert.Equal(t, 3, nextEvent(t, events).Version)
+}
+
+func TestWatcherClosesSubscriptionsFallingBehind(t *testing.T) {
+	watcher := service.NewConfigWatcher()
+	slow := watcher.Subscribe("default", "dev")
+	defer slow.Close()
+	other := watcher.Subscribe("default", "prod")
+	defer other.Close()
+
+	for version := 1; version <= 65; version++ {
+		watcher.Publish(domain.ConfigRecord{Tenant: "default", Environment: "dev", Schema: "service_config", Name: "eu", Version: version})
+	}
+
+	// The buffered versions are delivered before the channel is closed
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, 64, received)
+	assert.ErrorIs(t, slow.Err(), service.ErrWatchFellBehind)
+
+	// Subscribers of other environments are kept
+	watcher.Publish(domain.ConfigRecord{Tenant: "default", Environment: "prod", Schema: "service_config", Name: "eu", Version: 1})
+	assert.Equal(t, 1, (<-other.Events()).Version)
+	other.Close()
+	_, open := <-other.Events()
+	assert.False(t, open)
+	assert.NoError(t, other.Err())
+}
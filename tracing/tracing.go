@@ -0,0 +1,112 @@
+// Package tracing configures OpenTelemetry and provides the helpers used to
+// create spans in the service and repository layers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName identifies this service in traces.
+const ServiceName = "config-service"
+
+// Exporters accepted by Setup.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The OTLP exporter sends spans over HTTP to endpoint, or to
+// OTEL_EXPORTER_OTLP_ENDPOINT when endpoint is empty. The returned function
+// flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, exporter, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		spanExporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// ValidExporter reports whether Setup accepts exporter.
+func ValidExporter(exporter string) bool {
+	switch exporter {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+		return true
+	}
+	return false
+}
+
+// Start creates a span with the global tracer provider.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(ServiceName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartQuery creates a client span for a database query.
+func StartQuery(ctx context.Context, name, statement string) (context.Context, trace.Span) {
+	return otel.Tracer(ServiceName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemSqlite,
+			semconv.DBQueryText(statement),
+		),
+	)
+}
+
+// End ends span and marks it failed when the caller is panicking, which is
+// how the service reports errors. Use it as defer tracing.End(span).
+func End(span trace.Span) {
+	if r := recover(); r != nil {
+		span.SetStatus(codes.Error, fmt.Sprintf("%v", r))
+		span.RecordError(fmt.Errorf("%v", r))
+		span.End()
+		panic(r)
+	}
+
+	span.End()
+}
+
+// ConfigAttributes describes the config a span works on.
+func ConfigAttributes(schema, name string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("config.schema", schema),
+		attribute.String("config.name", name),
+	}
+}